@@ -0,0 +1,100 @@
+/*
+Copyright 2024 Agoda.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+
+// EtcdBackupList contains a list of EtcdBackup
+type EtcdBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdBackup `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Key",type=string,JSONPath=`.status.key`
+// +kubebuilder:printcolumn:name="Size",type=string,JSONPath=`.status.size`
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.status.revision`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// EtcdBackup is the Schema for the etcdbackups API
+type EtcdBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdBackupSpec   `json:"spec,omitempty"`
+	Status EtcdBackupStatus `json:"status,omitempty"`
+}
+
+// EtcdBackupSpec defines the desired state of EtcdBackup
+//
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type EtcdBackupSpec struct {
+	// ClusterName is the name of EtcdCluster in the same namespace
+	ClusterName string `json:"clusterName"`
+
+	// Key of backup object, defaults to `<namespace>/<cluster>/<timestamp>`
+	Key string `json:"key,omitempty"`
+}
+
+// EtcdBackupStatus defines the observed state of EtcdBackup
+type EtcdBackupStatus struct {
+	// Lifecycle phase
+	Phase BackupPhase `json:"phase,omitempty"`
+
+	// Key of uploaded backup object
+	Key string `json:"key,omitempty"`
+
+	// Size of uploaded backup object
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// Revision of etcd snapshot
+	Revision int64 `json:"revision,omitempty"`
+
+	// StartTime is the time backup job was started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time backup job was completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Reason of backup failure
+	Reason string `json:"reason,omitempty"`
+
+	// Message is human readable failure details
+	Message string `json:"message,omitempty"`
+}
+
+type BackupPhase string
+
+var (
+	BackupPending   = BackupPhase("Pending")
+	BackupRunning   = BackupPhase("Running")
+	BackupSucceeded = BackupPhase("Succeeded")
+	BackupFailed    = BackupPhase("Failed")
+)
+
+func init() {
+	SchemeBuilder.Register(&EtcdBackup{}, &EtcdBackupList{})
+}
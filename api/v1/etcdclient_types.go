@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Agoda.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+
+// EtcdClientList contains a list of EtcdClient
+type EtcdClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdClient `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`
+// +kubebuilder:printcolumn:name="Username",type=string,JSONPath=`.status.username`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.status.secretName`
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".status.notAfter"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:validation:XValidation:rule="(has(self.spec.username) && self.spec.username != '') || self.metadata.name != 'root'",message="root credentials are provided by <cluster>-user-root secret, spec.username is required for EtcdClient named root"
+
+// EtcdClient is the Schema for the etcdclients API
+type EtcdClient struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdClientSpec   `json:"spec,omitempty"`
+	Status EtcdClientStatus `json:"status,omitempty"`
+}
+
+// EtcdClientSpec defines client certificate of an application issued by server CA of the cluster.
+// The secret contains `tls.crt`, `tls.key` and `ca.crt` together with connection details.
+type EtcdClientSpec struct {
+	// ClusterName is the name of EtcdCluster in the same namespace
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterName is immutable"
+	ClusterName string `json:"clusterName"`
+
+	// Username is etcd user set as common name of client certificate, defaults to the name of EtcdClient.
+	// Root credentials are only provided by `<cluster>-user-root` secret.
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="username is immutable"
+	// +kubebuilder:validation:XValidation:rule="self != 'root'",message="root credentials are provided by <cluster>-user-root secret"
+	Username string `json:"username,omitempty"`
+
+	// SecretName of client credentials, defaults to the name of EtcdClient
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="secretName is immutable"
+	SecretName string `json:"secretName,omitempty"`
+
+	// MountPath where application mounts the secret, referenced by etcdctl env file and client config.
+	// Defaults to `/etc/etcd/client`.
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Duration of client certificate, defaults to `spec.pki.clientDuration` of the cluster
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Rotate reissues client certificate with a new private key whenever the value is changed, e.g. set to current date
+	Rotate string `json:"rotate,omitempty"`
+}
+
+// EtcdClientStatus defines the observed state of EtcdClient
+type EtcdClientStatus struct {
+	// Lifecycle phase
+	Phase ClientPhase `json:"phase,omitempty"`
+
+	// Username of issued client certificate
+	Username string `json:"username,omitempty"`
+
+	// SecretName of client credentials
+	SecretName string `json:"secretName,omitempty"`
+
+	// Endpoint of the cluster
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// NotAfter is the expiration time of current client certificate
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// RenewalTime is the time current client certificate is renewed
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+
+	// Rotate is the value of spec.rotate applied by the latest rotation
+	Rotate string `json:"rotate,omitempty"`
+
+	// Reason of pending credentials
+	Reason string `json:"reason,omitempty"`
+
+	// Message is human readable details of pending credentials
+	Message string `json:"message,omitempty"`
+}
+
+type ClientPhase string
+
+var (
+	ClientPending = ClientPhase("Pending")
+	ClientReady   = ClientPhase("Ready")
+)
+
+func init() {
+	SchemeBuilder.Register(&EtcdClient{}, &EtcdClientList{})
+}
@@ -39,6 +39,7 @@ type EtcdClusterList struct {
 // +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyReplicas`
 // +kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.availableReplicas`
 // +kubebuilder:printcolumn:name="Learners",type=integer,JSONPath=`.status.learnerReplicas`
+// +kubebuilder:printcolumn:name="Read",type=integer,JSONPath=`.status.readReplicas`
 // +kubebuilder:printcolumn:name="Updated",type=integer,JSONPath=`.status.updatedReplicas`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
@@ -52,6 +53,10 @@ type EtcdCluster struct {
 }
 
 // EtcdClusterSpec defines the desired state of EtcdCluster
+//
+// +kubebuilder:validation:XValidation:rule="has(self.storage) == has(oldSelf.storage)",message="storage can not be added or removed"
+// +kubebuilder:validation:XValidation:rule="(has(self.pki) && has(self.pki.serverIssuerRef)) == (has(oldSelf.pki) && has(oldSelf.pki.serverIssuerRef))",message="pki.serverIssuerRef can not be added or removed"
+// +kubebuilder:validation:XValidation:rule="(has(self.pki) && has(self.pki.peerIssuerRef)) == (has(oldSelf.pki) && has(oldSelf.pki.peerIssuerRef))",message="pki.peerIssuerRef can not be added or removed"
 type EtcdClusterSpec struct {
 	Pause bool `json:"pause,omitempty"`
 
@@ -65,6 +70,13 @@ type EtcdClusterSpec struct {
 	// +kubebuilder:default=v3.5.7
 	Version string `json:"version"`
 
+	// ReadReplicas is the number of additional members kept as learners serving serializable reads,
+	// they are not promoted and do not count towards quorum. Requires etcd v3.6 or later.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=5
+	ReadReplicas int32 `json:"readReplicas,omitempty"`
+
 	PodTemplate *PodTemplate `json:"podTemplate,omitempty"`
 
 	Restore *RestoreSpec `json:"restore,omitempty"`
@@ -74,6 +86,137 @@ type EtcdClusterSpec struct {
 	// Compute Resources required by each member of cluster.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	Resources corev1.ResourceList `json:"resources,omitempty"`
+
+	// Storage configures persistent volume claims for member data.
+	// Members use ephemeral emptyDir volumes when not set.
+	Storage *StorageSpec `json:"storage,omitempty"`
+
+	// Recovery configures automatic recovery of failed cluster.
+	Recovery *RecoverySpec `json:"recovery,omitempty"`
+
+	// DeletionPolicy of cluster data when cluster is deleted:
+	// `Retain` keeps backup objects of the cluster,
+	// `Delete` deletes backup objects of the cluster,
+	// `BackupThenDelete` uploads final backup before cluster is deleted and keeps backup objects.
+	//
+	// +kubebuilder:validation:Enum=Delete;BackupThenDelete;Retain
+	// +kubebuilder:default=Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Etcd configures etcd server tuning parameters.
+	// Changes are rolled out by replacing members one at a time.
+	Etcd *EtcdSpec `json:"etcd,omitempty"`
+
+	// Mirror runs cluster as read-only standby continuously mirroring keys of a source cluster.
+	// Requires auth, only the mirror worker writes with root credentials until cluster is promoted.
+	Mirror *MirrorSpec `json:"mirror,omitempty"`
+
+	// PKI configures issuers of member and client certificates, operator creates self-signed CAs by default.
+	// Issuers can only be set when cluster is created.
+	PKI *PKISpec `json:"pki,omitempty"`
+
+	// Service exposes cluster to clients outside of Kubernetes cluster.
+	// Changes of certificate names are rolled out by replacing members one at a time.
+	Service *ServiceSpec `json:"service,omitempty"`
+
+	// Auth enables etcd authentication, users and roles are managed by EtcdUser and EtcdRole.
+	// Changes are rolled out by replacing members one at a time.
+	Auth *AuthSpec `json:"auth,omitempty"`
+
+	// ConsistencyCheck enables periodic comparison of member data hashes,
+	// members diverging from the majority are replaced one at a time.
+	ConsistencyCheck *ConsistencyCheckSpec `json:"consistencyCheck,omitempty"`
+}
+
+// ConsistencyCheckSpec configures periodic consistency check of member data
+type ConsistencyCheckSpec struct {
+	// Interval between consistency checks, defaults to 1h. Hashing reads the whole database of every member.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// AuthSpec configures etcd authentication. Clients authenticate with common name of their certificates,
+// `<cluster>-user-root` credentials and members authenticate as `root` user.
+type AuthSpec struct {
+	// Enabled turns on etcd authentication once all members authenticate as `root` user
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// PKISpec defines cert-manager issuers of cluster certificates
+type PKISpec struct {
+	// ServerIssuerRef signs server certificates of members and client certificates,
+	// the issuer has to populate CA of issued certificates.
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="issuer can not be changed"
+	ServerIssuerRef *IssuerReference `json:"serverIssuerRef,omitempty"`
+
+	// PeerIssuerRef signs peer certificates of members,
+	// the issuer has to populate CA of issued certificates.
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="issuer can not be changed"
+	PeerIssuerRef *IssuerReference `json:"peerIssuerRef,omitempty"`
+
+	// Rotate requests rotation of self-signed CAs whenever the value is changed, e.g. set to current date.
+	// CAs are also rotated automatically before they expire.
+	Rotate string `json:"rotate,omitempty"`
+
+	// KeyAlgorithm of private keys of self-signed CAs, member and client certificates, RSA by default
+	//
+	// +kubebuilder:validation:Enum=RSA;ECDSA;Ed25519
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
+
+	// KeySize of private keys, 2048-8192 bits for RSA (default 2048) and 256, 384 or 521 for ECDSA (default 256).
+	// Ed25519 keys have fixed size.
+	//
+	// +kubebuilder:validation:Minimum=0
+	KeySize int `json:"keySize,omitempty"`
+
+	// Duration of member certificates, 90 days by default
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// RenewBefore is the time before expiry when member certificates are renewed, a quarter of duration by default
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
+	// CADuration of self-signed CAs, 5 years by default. CAs are rotated once 4/5 of their lifetime have passed.
+	CADuration *metav1.Duration `json:"caDuration,omitempty"`
+
+	// ClientDuration of client certificates issued by operator, 90 days by default
+	ClientDuration *metav1.Duration `json:"clientDuration,omitempty"`
+}
+
+// IssuerReference refers to cert-manager Issuer in cluster namespace or ClusterIssuer
+type IssuerReference struct {
+	// Name of the issuer
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the issuer
+	//
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	Kind string `json:"kind,omitempty"`
+}
+
+// ServiceSpec defines external service of voting members and additional names of their server certificates
+type ServiceSpec struct {
+	// Type of the external service
+	//
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +kubebuilder:default=LoadBalancer
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Annotations of the external service, e.g. load balancer configuration of cloud provider
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ExternalDNSNames resolve to the external service, the first name is reported as external endpoint.
+	ExternalDNSNames []string `json:"externalDNSNames,omitempty"`
+
+	// DNSNames are additional subject alternative names of server certificates
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// IPAddresses are additional subject alternative IP addresses of server certificates,
+	// e.g. reserved load balancer address
+	IPAddresses []string `json:"ipAddresses,omitempty"`
 }
 
 type PodTemplate struct {
@@ -82,25 +225,186 @@ type PodTemplate struct {
 
 	// Annotations
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Spec is merged into generated pod spec of members, backup and defrag jobs as strategic merge patch.
+	// Containers, init containers and volumes generated by the operator, restartPolicy, serviceAccountName
+	// and shareProcessNamespace can not be overridden. Containers and init containers are only added to members.
+	//
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec *corev1.PodSpec `json:"spec,omitempty"`
 }
 
 // BackupSpec defines the configuration to backup cluster to
 type BackupSpec struct {
 	Suspend  bool   `json:"suspend,omitempty"`
 	Schedule string `json:"schedule,omitempty"`
+
+	// HistoryLimit is the number of finished scheduled EtcdBackup objects to retain, defaults to 24.
+	//
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
 }
 
 // RestoreSpec defines the configuration to restore cluster from
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.fromCluster) || (!has(self.prefix) && !has(self.key))",message="fromCluster can not be combined with prefix or key"
 type RestoreSpec struct {
 	Prefix *string `json:"prefix,omitempty"`
 	Key    *string `json:"key,omitempty"`
+
+	// FromCluster bootstraps cluster from a snapshot streamed from another running cluster,
+	// backup object storage is not involved.
+	FromCluster *ClusterReference `json:"fromCluster,omitempty"`
+}
+
+// ClusterReference refers to EtcdCluster in the same or another namespace
+type ClusterReference struct {
+	// Namespace of the cluster, defaults to the namespace of the referring object.
+	Namespace string `json:"namespace,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// StorageSpec defines the persistent volume claimed by each member
+type StorageSpec struct {
+	// Size of the volume, defaults to the storage quota of the cluster.
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// StorageClassName of the volume, cluster default storage class is used when not set.
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// AccessModes of the volume, defaults to ReadWriteOnce.
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+}
+
+// RecoverySpec defines how failed cluster is recovered
+type RecoverySpec struct {
+	// Policy of recovery:
+	// `None` leaves cluster in Failed phase,
+	// `RestoreLatestBackup` bootstraps cluster from the latest backup object,
+	// `ForceNewCluster` restarts the surviving member as a new single member cluster keeping its data,
+	// every attempt is approved with etcd.fleet.agoda.com/force-new-cluster-approved annotation.
+	//
+	// +kubebuilder:validation:Enum=None;RestoreLatestBackup;ForceNewCluster
+	// +kubebuilder:default=None
+	Policy RecoveryPolicy `json:"policy,omitempty"`
+
+	// MaxAttempts is the number of recovery attempts before cluster is left in Failed phase, defaults to 3.
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
 }
 
-// DefragSpec defines the configuration for automated cluster defrag
+type RecoveryPolicy string
+
+var (
+	RecoveryNone                = RecoveryPolicy("None")
+	RecoveryRestoreLatestBackup = RecoveryPolicy("RestoreLatestBackup")
+	RecoveryForceNewCluster     = RecoveryPolicy("ForceNewCluster")
+)
+
+// EtcdSpec defines etcd server tuning parameters
+type EtcdSpec struct {
+	// HeartbeatInterval is the time between leader heartbeats, defaults to 100ms.
+	HeartbeatInterval *metav1.Duration `json:"heartbeatInterval,omitempty"`
+
+	// ElectionTimeout is the time follower waits for leader heartbeat before starting election, defaults to 1s.
+	// Election timeout must be at least 5 times the heartbeat interval and at most 50s.
+	ElectionTimeout *metav1.Duration `json:"electionTimeout,omitempty"`
+
+	// SnapshotCount is the number of committed transactions to trigger a snapshot to disk, defaults to 10000.
+	//
+	// +kubebuilder:validation:Minimum=1
+	SnapshotCount *int64 `json:"snapshotCount,omitempty"`
+
+	// AutoCompactionMode is the history compaction mode, defaults to `revision`.
+	//
+	// +kubebuilder:validation:Enum=periodic;revision
+	AutoCompactionMode *CompactionMode `json:"autoCompactionMode,omitempty"`
+
+	// AutoCompactionRetention is the number of revisions for `revision` mode, defaults to 100,
+	// or the duration (e.g. `1h`) for `periodic` mode.
+	AutoCompactionRetention *string `json:"autoCompactionRetention,omitempty"`
+
+	// MaxRequestBytes is the maximum client request size the server accepts, defaults to 1.5MiB.
+	MaxRequestBytes *resource.Quantity `json:"maxRequestBytes,omitempty"`
+
+	// GRPCKeepAlive configures gRPC keepalive of client connections.
+	GRPCKeepAlive *GRPCKeepAliveSpec `json:"grpcKeepAlive,omitempty"`
+
+	// LogLevel of etcd server, defaults to `info`.
+	//
+	// +kubebuilder:validation:Enum=debug;info;warn;error;panic;fatal
+	LogLevel *string `json:"logLevel,omitempty"`
+
+	// EnablePProf enables runtime profiling data via HTTP server.
+	EnablePProf bool `json:"enablePProf,omitempty"`
+}
+
+type CompactionMode string
+
+var (
+	CompactionPeriodic = CompactionMode("periodic")
+	CompactionRevision = CompactionMode("revision")
+)
+
+// GRPCKeepAliveSpec defines keepalive of client gRPC connections
+type GRPCKeepAliveSpec struct {
+	// MinTime is the minimum interval client should wait before pinging server, defaults to 5s.
+	MinTime *metav1.Duration `json:"minTime,omitempty"`
+
+	// Interval is the frequency of server-to-client ping to check if connection is alive, defaults to 2h.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Timeout is the additional duration of wait before closing non-responsive connection, defaults to 20s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// MirrorSpec defines the source cluster of standby cluster
+type MirrorSpec struct {
+	// Endpoint of the source cluster.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+
+	// SecretName of the secret with tls.crt, tls.key and ca.crt of the source cluster client.
+	//
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Prefix of mirrored keys, all keys are mirrored when not set.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Promote stops mirroring and makes cluster writable, promoted cluster can not resume mirroring.
+	Promote bool `json:"promote,omitempty"`
+}
+
+type DeletionPolicy string
+
+var (
+	DeletionPolicyDelete           = DeletionPolicy("Delete")
+	DeletionPolicyBackupThenDelete = DeletionPolicy("BackupThenDelete")
+	DeletionPolicyRetain           = DeletionPolicy("Retain")
+)
+
+// DefragSpec defines the configuration for automated cluster defrag.
+// Operator defragments members one at a time, followers before leader, while all members are available.
 type DefragSpec struct {
-	Suspend  *bool              `json:"suspend,omitempty"`
-	Schedule *string            `json:"schedule,omitempty"`
-	Size     *resource.Quantity `json:"size,omitempty"`
+	// Suspend stops scheduled defrag, defrag in progress is paused
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// Schedule in cron format starting defrag of members exceeding thresholds, defaults to `0 1 * * *`
+	Schedule *string `json:"schedule,omitempty"`
+
+	// Size is the threshold of unused space of member database, defaults to 128M
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// Ratio is the threshold ratio of unused space to member database size, defaults to 0.7.
+	// Member is defragmented when its unused space exceeds both thresholds.
+	//
 	// +kubebuilder:validation:Pattern=`^(1\.0|0\.[0-9]+)$`
 	Ratio *string `json:"ratio,omitempty"`
 }
@@ -116,6 +420,18 @@ type EtcdClusterStatus struct {
 	// Backup
 	Backup *BackupStatus `json:"backup,omitempty"`
 
+	// Restore is the status of the latest in-place restore
+	Restore *RestoreStatus `json:"restore,omitempty"`
+
+	// Mirror is the progress of mirroring from the source cluster
+	Mirror *MirrorStatus `json:"mirror,omitempty"`
+
+	// PKI is the state of self-signed CAs
+	PKI *PKIStatus `json:"pki,omitempty"`
+
+	// RecoveryAttempts is the number of automatic recovery attempts
+	RecoveryAttempts int32 `json:"recoveryAttempts,omitempty"`
+
 	// Replicas is the number of non-terminated members.
 	// +kubebuilder:default=0
 	Replicas int32 `json:"replicas"`
@@ -132,6 +448,9 @@ type EtcdClusterStatus struct {
 	// +kubebuilder:default=0
 	LearnerReplicas int32 `json:"learnerReplicas"`
 
+	// ReadReplicas is the number of read replica members, they are not counted in other replica numbers
+	ReadReplicas int32 `json:"readReplicas,omitempty"`
+
 	// UpdatedReplicas is the number of members that are synced with cluster spec
 	// +kubebuilder:default=0
 	UpdatedReplicas int32 `json:"updatedReplicas"`
@@ -139,9 +458,15 @@ type EtcdClusterStatus struct {
 	// Version is the observed version of etcd cluster
 	Version string `json:"version,omitempty"`
 
+	// DowngradeVersion is the target cluster version of downgrade in progress
+	DowngradeVersion string `json:"downgradeVersion,omitempty"`
+
 	// Endpoint is the etcd client endpoint
 	Endpoint string `json:"endpoint,omitempty"`
 
+	// ExternalEndpoint is the etcd client endpoint of the external service
+	ExternalEndpoint string `json:"externalEndpoint,omitempty"`
+
 	// SecretName is the name of the secret containing the etcd client certificate
 	SecretName string `json:"secretName,omitempty"`
 
@@ -156,6 +481,60 @@ type EtcdClusterStatus struct {
 	// +listMapKey=id
 	// +optional
 	Members []MemberStatus `json:"members,omitempty"`
+
+	// Defrag is the progress of scheduled defrag of members
+	// +optional
+	Defrag *DefragStatus `json:"defrag,omitempty"`
+
+	// ConsistencyCheck is the result of the latest consistency check of member data
+	// +optional
+	ConsistencyCheck *ConsistencyCheckStatus `json:"consistencyCheck,omitempty"`
+
+	// Alarms are active alarms of cluster members
+	// +optional
+	Alarms []AlarmStatus `json:"alarms,omitempty"`
+}
+
+// DefragStatus defines the observed state of scheduled defrag
+type DefragStatus struct {
+	// ObservedTime is the time defrag schedule was first observed, the first defrag is scheduled after it
+	ObservedTime *metav1.Time `json:"observedTime,omitempty"`
+
+	// LastScheduleTime is the time the latest defrag was started
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// CompletionTime is the time the latest defrag was completed, defrag is in progress until then
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Failed are members which could not be defragmented, they are skipped until the next schedule
+	Failed []string `json:"failed,omitempty"`
+}
+
+// ConsistencyCheckStatus defines the observed state of consistency check
+type ConsistencyCheckStatus struct {
+	// LastCheckTime is the time of the latest consistency check
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// Revision at which hashes of member data were compared
+	Revision int64 `json:"revision,omitempty"`
+
+	// CompactRevision of member data when hashes were compared
+	CompactRevision int64 `json:"compactRevision,omitempty"`
+
+	// Diverged are members which data diverged from the majority
+	Diverged []string `json:"diverged,omitempty"`
+}
+
+// AlarmStatus is an active alarm raised by cluster member
+type AlarmStatus struct {
+	// MemberID of the member raising the alarm
+	MemberID string `json:"memberID"`
+
+	// Name of the member raising the alarm
+	Name string `json:"name,omitempty"`
+
+	// Alarm type, e.g. NOSPACE or CORRUPT
+	Alarm string `json:"alarm"`
 }
 
 type ClusterPhase string
@@ -164,6 +543,7 @@ var (
 	ClusterBootstrap = ClusterPhase("Bootstrap")
 	ClusterRunning   = ClusterPhase("Running")
 	ClusterFailed    = ClusterPhase("Failed")
+	ClusterRestoring = ClusterPhase("Restoring")
 )
 
 type MemberRole string
@@ -173,15 +553,61 @@ var (
 	MemberRoleLearner     = MemberRole("Learner")
 	MemberRoleMember      = MemberRole("Member")
 	MemberRoleLeader      = MemberRole("Leader")
+	MemberRoleReadReplica = MemberRole("ReadReplica")
 )
 
-var MemberRoleOrder = []MemberRole{MemberRoleLeader, MemberRoleMember, MemberRoleLearner, MemberRoleUnspecified}
+var MemberRoleOrder = []MemberRole{MemberRoleLeader, MemberRoleMember, MemberRoleLearner, MemberRoleReadReplica, MemberRoleUnspecified}
 
 type BackupStatus struct {
 	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
 	LastScheduleTime   *metav1.Time `json:"lastScheduleTime,omitempty"`
 }
 
+// MirrorStatus defines the observed progress of mirroring
+type MirrorStatus struct {
+	// Revision is the last source revision applied to the cluster
+	Revision int64 `json:"revision,omitempty"`
+
+	// SourceRevision is the current revision of the source cluster
+	SourceRevision int64 `json:"sourceRevision,omitempty"`
+
+	// Lag is the number of source revisions not yet applied to the cluster
+	Lag int64 `json:"lag,omitempty"`
+
+	// ObservedTime of the revisions
+	ObservedTime *metav1.Time `json:"observedTime,omitempty"`
+
+	// PromotedTime is the time mirroring was stopped and cluster became writable
+	PromotedTime *metav1.Time `json:"promotedTime,omitempty"`
+}
+
+// PKIStatus defines the observed state of self-signed CAs
+type PKIStatus struct {
+	// Generation of CAs signing member and client certificates, incremented by each rotation
+	Generation int32 `json:"generation,omitempty"`
+
+	// Rotate is the value of spec.pki.rotate applied by the latest rotation
+	Rotate string `json:"rotate,omitempty"`
+
+	// NotAfter is the expiration time of the earliest expiring signing CA
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// RestoreStatus defines the state of in-place restore requested by EtcdRestore or recovery
+type RestoreStatus struct {
+	// Name of EtcdRestore, empty when restore is started by recovery
+	Name string `json:"name,omitempty"`
+
+	// Key of backup object
+	Key string `json:"key"`
+
+	// StartTime is the time restore was started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time restored cluster became available
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
 type ClusterCondition struct {
 	Type               ClusterConditionType   `json:"type"`
 	Status             corev1.ConditionStatus `json:"status"`
@@ -193,11 +619,18 @@ type ClusterCondition struct {
 type ClusterConditionType string
 
 const (
-	ClusterAvailable ClusterConditionType = "Available"
-	ClusterScaling   ClusterConditionType = "Scaling"
-	ClusterUpgrading ClusterConditionType = "Upgrading"
-	ClusterBackup    ClusterConditionType = "Backup"
-	ClusterRestore   ClusterConditionType = "Restore"
+	ClusterAvailable   ClusterConditionType = "Available"
+	ClusterScaling     ClusterConditionType = "Scaling"
+	ClusterUpgrading   ClusterConditionType = "Upgrading"
+	ClusterBackup      ClusterConditionType = "Backup"
+	ClusterRestore     ClusterConditionType = "Restore"
+	ClusterRecovering  ClusterConditionType = "Recovering"
+	ClusterDowngrading ClusterConditionType = "Downgrading"
+	ClusterMirroring   ClusterConditionType = "Mirroring"
+	ClusterCARotation  ClusterConditionType = "CARotation"
+	ClusterAuth        ClusterConditionType = "Auth"
+	ClusterAlarm       ClusterConditionType = "Alarm"
+	ClusterConsistent  ClusterConditionType = "Consistent"
 )
 
 // MemberStatus defines the observed state of EtcdCluster member
@@ -210,14 +643,36 @@ type MemberStatus struct {
 
 	Available bool `json:"available"`
 
+	// LastSuccessfulTime is the time status of the member was last queried, refreshed at most once a minute
 	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
 
 	Version string `json:"version,omitempty"`
 
 	Role MemberRole `json:"role,omitempty"`
 
+	// IsLearner is true for learners and read replicas
+	IsLearner bool `json:"isLearner,omitempty"`
+
 	Size *resource.Quantity `json:"size,omitempty"`
 
+	// SizeInUse is the size of member database actually in use, the rest is reclaimed by defrag
+	SizeInUse *resource.Quantity `json:"sizeInUse,omitempty"`
+
+	// RaftTerm is the current raft term of the member
+	RaftTerm uint64 `json:"raftTerm,omitempty"`
+
+	// RaftIndex is the current raft index of the member
+	RaftIndex uint64 `json:"raftIndex,omitempty"`
+
+	// RaftAppliedIndex is the raft index applied by the member
+	RaftAppliedIndex uint64 `json:"raftAppliedIndex,omitempty"`
+
+	// Alarms are active alarms of the member, e.g. NOSPACE or CORRUPT
+	Alarms []string `json:"alarms,omitempty"`
+
+	// LastDefragTime is the time the member was last defragmented by operator
+	LastDefragTime *metav1.Time `json:"lastDefragTime,omitempty"`
+
 	Errors []string `json:"errors,omitempty"`
 }
 
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 Agoda.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+
+// EtcdRestoreList contains a list of EtcdRestore
+type EtcdRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdRestore `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Key",type=string,JSONPath=`.status.key`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// EtcdRestore is the Schema for the etcdrestores API
+type EtcdRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdRestoreSpec   `json:"spec,omitempty"`
+	Status EtcdRestoreStatus `json:"status,omitempty"`
+}
+
+// EtcdRestoreSpec defines the backup to restore running cluster from.
+// Latest backup object with `<namespace>/<cluster>` prefix is used when neither key nor backup name is specified.
+//
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+// +kubebuilder:validation:XValidation:rule="!(has(self.key) && has(self.backupName))",message="key and backupName are mutually exclusive"
+type EtcdRestoreSpec struct {
+	// ClusterName is the name of EtcdCluster in the same namespace
+	ClusterName string `json:"clusterName"`
+
+	// Key of backup object
+	Key string `json:"key,omitempty"`
+
+	// BackupName is the name of succeeded EtcdBackup in the same namespace
+	BackupName string `json:"backupName,omitempty"`
+}
+
+// EtcdRestoreStatus defines the observed state of EtcdRestore
+type EtcdRestoreStatus struct {
+	// Lifecycle phase
+	Phase RestorePhase `json:"phase,omitempty"`
+
+	// Key of restored backup object
+	Key string `json:"key,omitempty"`
+
+	// StartTime is the time cluster members were stopped
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time restored cluster became available
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Reason of restore failure
+	Reason string `json:"reason,omitempty"`
+
+	// Message is human readable failure details
+	Message string `json:"message,omitempty"`
+}
+
+type RestorePhase string
+
+var (
+	RestorePending   = RestorePhase("Pending")
+	RestoreRunning   = RestorePhase("Running")
+	RestoreSucceeded = RestorePhase("Succeeded")
+	RestoreFailed    = RestorePhase("Failed")
+)
+
+func init() {
+	SchemeBuilder.Register(&EtcdRestore{}, &EtcdRestoreList{})
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 Agoda.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+
+// EtcdRoleList contains a list of EtcdRole
+type EtcdRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdRole `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`
+// +kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.status.roleName`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// EtcdRole is the Schema for the etcdroles API
+type EtcdRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdRoleSpec   `json:"spec,omitempty"`
+	Status EtcdRoleStatus `json:"status,omitempty"`
+}
+
+// EtcdRoleSpec defines etcd role and its key permissions, permissions granted outside of the spec are revoked
+type EtcdRoleSpec struct {
+	// ClusterName is the name of EtcdCluster in the same namespace
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterName is immutable"
+	ClusterName string `json:"clusterName"`
+
+	// RoleName is etcd role, defaults to the name of EtcdRole
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="roleName is immutable"
+	// +kubebuilder:validation:XValidation:rule="self != 'root'",message="root role can not be managed"
+	RoleName string `json:"roleName,omitempty"`
+
+	// Permissions on key ranges
+	Permissions []Permission `json:"permissions,omitempty"`
+}
+
+// Permission grants access to a single key, key prefix or key range
+//
+// +kubebuilder:validation:XValidation:rule="!(has(self.prefix) && self.prefix && has(self.rangeEnd))",message="prefix and rangeEnd are mutually exclusive"
+type Permission struct {
+	// Type of access
+	//
+	// +kubebuilder:validation:Enum=Read;Write;ReadWrite
+	Type PermissionType `json:"type"`
+
+	// Key or start of key range
+	//
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// RangeEnd is the exclusive end of key range, `\0` grants access to all keys greater than or equal to key
+	RangeEnd string `json:"rangeEnd,omitempty"`
+
+	// Prefix grants access to all keys with the key as prefix
+	Prefix bool `json:"prefix,omitempty"`
+}
+
+type PermissionType string
+
+var (
+	PermissionRead      = PermissionType("Read")
+	PermissionWrite     = PermissionType("Write")
+	PermissionReadWrite = PermissionType("ReadWrite")
+)
+
+// EtcdRoleStatus defines the observed state of EtcdRole
+type EtcdRoleStatus struct {
+	// Lifecycle phase
+	Phase AuthPhase `json:"phase,omitempty"`
+
+	// RoleName of managed etcd role
+	RoleName string `json:"roleName,omitempty"`
+
+	// ObservedGeneration is the generation of spec applied to etcd
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ReadOnly is true when write permissions are withheld while cluster is mirroring standby
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Drift describes changes made outside of the operator which were reverted by the latest reconciliation
+	Drift string `json:"drift,omitempty"`
+
+	// DriftTime is the time the latest drift was reverted
+	DriftTime *metav1.Time `json:"driftTime,omitempty"`
+
+	// Reason of pending or failed role
+	Reason string `json:"reason,omitempty"`
+
+	// Message is human readable details of pending or failed role
+	Message string `json:"message,omitempty"`
+}
+
+// AuthPhase is the lifecycle phase of etcd users and roles
+type AuthPhase string
+
+var (
+	AuthPending = AuthPhase("Pending")
+	AuthReady   = AuthPhase("Ready")
+	AuthFailed  = AuthPhase("Failed")
+)
+
+func init() {
+	SchemeBuilder.Register(&EtcdRole{}, &EtcdRoleList{})
+}
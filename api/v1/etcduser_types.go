@@ -0,0 +1,97 @@
+/*
+Copyright 2024 Agoda.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+
+// EtcdUserList contains a list of EtcdUser
+type EtcdUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EtcdUser `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`
+// +kubebuilder:printcolumn:name="Username",type=string,JSONPath=`.status.username`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// EtcdUser is the Schema for the etcdusers API
+type EtcdUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EtcdUserSpec   `json:"spec,omitempty"`
+	Status EtcdUserStatus `json:"status,omitempty"`
+}
+
+// EtcdUserSpec defines etcd user and its roles, roles granted outside of the spec are revoked.
+// Users have no password and authenticate with common name of client certificate, see EtcdClient.
+type EtcdUserSpec struct {
+	// ClusterName is the name of EtcdCluster in the same namespace
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterName is immutable"
+	ClusterName string `json:"clusterName"`
+
+	// Username is etcd user, defaults to the name of EtcdUser
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="username is immutable"
+	// +kubebuilder:validation:XValidation:rule="self != 'root'",message="root user can not be managed"
+	Username string `json:"username,omitempty"`
+
+	// Roles granted to the user, e.g. role names of EtcdRoles
+	//
+	// +kubebuilder:validation:XValidation:rule="self.all(role, role != 'root')",message="root role can not be granted"
+	Roles []string `json:"roles,omitempty"`
+}
+
+// EtcdUserStatus defines the observed state of EtcdUser
+type EtcdUserStatus struct {
+	// Lifecycle phase
+	Phase AuthPhase `json:"phase,omitempty"`
+
+	// Username of managed etcd user
+	Username string `json:"username,omitempty"`
+
+	// Roles granted to the user
+	Roles []string `json:"roles,omitempty"`
+
+	// ObservedGeneration is the generation of spec applied to etcd
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Drift describes changes made outside of the operator which were reverted by the latest reconciliation
+	Drift string `json:"drift,omitempty"`
+
+	// DriftTime is the time the latest drift was reverted
+	DriftTime *metav1.Time `json:"driftTime,omitempty"`
+
+	// Reason of pending or failed user
+	Reason string `json:"reason,omitempty"`
+
+	// Message is human readable details of pending or failed user
+	Message string `json:"message,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EtcdUser{}, &EtcdUserList{})
+}
@@ -12,12 +12,58 @@ const (
 	ClusterLabel  = "etcd.fleet.agoda.com/cluster"
 	MemberIDLabel = "etcd.fleet.agoda.com/member-id"
 	LearnerLabel  = "etcd.fleet.agoda.com/learner"
+
+	// ReadReplicaLabel marks learner members which are never promoted
+	ReadReplicaLabel = "etcd.fleet.agoda.com/read-replica"
+
+	// MirrorLabel selects mirror worker pods of standby cluster
+	MirrorLabel = "etcd.fleet.agoda.com/mirror"
+
+	// ScheduledLabel marks backups created by backup schedule
+	ScheduledLabel = "etcd.fleet.agoda.com/scheduled"
 )
 
 const (
 	RenewAtAnnotation = "etcd.fleet.agoda.com/renew-at"
+
+	// ForceNewClusterAnnotation marks the surviving member pod to be restarted as a new cluster
+	ForceNewClusterAnnotation = "etcd.fleet.agoda.com/force-new-cluster"
+
+	// ForceNewClusterApprovedAnnotation of EtcdCluster approves the next ForceNewCluster recovery attempt
+	ForceNewClusterApprovedAnnotation = "etcd.fleet.agoda.com/force-new-cluster-approved"
+
+	// ConfigHashAnnotation of member pod template rolls out changes of etcd tuning parameters
+	ConfigHashAnnotation = "etcd.fleet.agoda.com/config-hash"
+
+	// RestartRequestedAnnotation marks member pod waiting for restart slot to reload etcd
+	RestartRequestedAnnotation = "etcd.fleet.agoda.com/restart-requested"
+
+	// RestartGrantedAnnotation marks the only member pod of cluster allowed to restart etcd
+	RestartGrantedAnnotation = "etcd.fleet.agoda.com/restart-granted"
+
+	// ReplaceAnnotation marks member pod to be replaced by a new member without data
+	ReplaceAnnotation = "etcd.fleet.agoda.com/replace"
 )
 
+// Keys of CA bundle config map trusted by members during CA rotation
+const (
+	ServerCABundleKey = "server-ca.crt"
+	PeerCABundleKey   = "peer-ca.crt"
+)
+
+// Keys of connection details in EtcdClient secret next to client credentials
+const (
+	ClientEndpointKey = "endpoint"
+	ClientEnvKey      = "etcdctl.env"
+	ClientConfigKey   = "client.yaml"
+)
+
+// DeletionPolicyFinalizer keeps cluster until deletion policy is applied
+const DeletionPolicyFinalizer = "etcd.fleet.agoda.com/deletion-policy"
+
+// AuthFinalizer keeps EtcdUser and EtcdRole until they are deleted from etcd
+const AuthFinalizer = "etcd.fleet.agoda.com/auth"
+
 func ClusterLabelValue(cluster client.ObjectKey) string {
 	return strings.Join([]string{cluster.Name, cluster.Namespace}, ".")
 }
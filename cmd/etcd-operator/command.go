@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/agoda-com/etcd-operator/pkg/backup"
+	"github.com/agoda-com/etcd-operator/pkg/cluster"
 	"github.com/spf13/cobra"
 
 	corev1 "k8s.io/api/core/v1"
@@ -28,12 +29,15 @@ type Config struct {
 	Pod               client.ObjectKey
 	MetricsAddr       string
 	HealthProbeAddr   string
+	WebhookPort       int
+	WebhookCertDir    string
 	LeaderElection    bool
 	WatchNamespaces   []string
 	WatchSelector     LabelSelector
 	Image             string
 	ControllerImage   string
 	PriorityClassName string
+	ClusterDomain     string
 	BackupEnv         map[string]string
 }
 
@@ -56,9 +60,12 @@ func Command() *cobra.Command {
 	flags.StringSliceVar(&config.WatchNamespaces, "watch-namespaces", nil, "Namespaces to watch for resources.")
 	flags.Var(&config.WatchSelector, "watch-selector", "Selector to watch for resources.")
 	flags.StringVar(&config.PriorityClassName, "priority-class-name", "", "ETCD cluster pods priorityClassName")
+	flags.StringVar(&config.ClusterDomain, "cluster-domain", cluster.DefaultClusterDomain, "DNS domain of Kubernetes services used in cluster endpoints and certificates.")
 
 	flags.StringVar(&config.MetricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flags.StringVar(&config.HealthProbeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flags.IntVar(&config.WebhookPort, "webhook-port", 9443, "The port the admission webhook server binds to.")
+	flags.StringVar(&config.WebhookCertDir, "webhook-cert-dir", "", "Directory with tls.crt and tls.key of the admission webhook server. Webhooks are disabled when not set.")
 	flags.BoolVar(&config.LeaderElection, "leader-elect", false, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 
 	stdFlags := flag.NewFlagSet("etcd-operator", flag.ContinueOnError)
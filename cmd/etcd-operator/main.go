@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	crwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/go-logr/logr"
@@ -40,6 +41,7 @@ import (
 	"github.com/agoda-com/etcd-operator/pkg/cluster"
 	"github.com/agoda-com/etcd-operator/pkg/etcd"
 	"github.com/agoda-com/etcd-operator/pkg/metrics"
+	"github.com/agoda-com/etcd-operator/pkg/webhook"
 )
 
 func main() {
@@ -88,6 +90,10 @@ func run(ctx context.Context, logger logr.Logger, kubeconfig *rest.Config, confi
 		Cache: cache.Options{
 			DefaultNamespaces: namespaces,
 		},
+		WebhookServer: crwebhook.NewServer(crwebhook.Options{
+			Port:    config.WebhookPort,
+			CertDir: config.WebhookCertDir,
+		}),
 	})
 	if err != nil {
 		return fmt.Errorf("manager: %w", err)
@@ -98,16 +104,51 @@ func run(ctx context.Context, logger logr.Logger, kubeconfig *rest.Config, confi
 		return fmt.Errorf("tls cache: %w", err)
 	}
 
-	err = cluster.SetupWithManager(mgr, tlsCache, cluster.Config{
+	clusterConfig := cluster.Config{
 		Image:             config.Image,
 		ControllerImage:   config.ControllerImage,
 		PriorityClassName: config.PriorityClassName,
+		ClusterDomain:     config.ClusterDomain,
 		BackupEnv:         config.BackupEnv,
-	})
+	}
+
+	err = cluster.SetupWithManager(mgr, tlsCache, clusterConfig)
 	if err != nil {
 		return fmt.Errorf("cluster controller: %w", err)
 	}
 
+	err = cluster.SetupBackupWithManager(mgr, clusterConfig)
+	if err != nil {
+		return fmt.Errorf("backup controller: %w", err)
+	}
+
+	err = cluster.SetupRestoreWithManager(mgr, clusterConfig)
+	if err != nil {
+		return fmt.Errorf("restore controller: %w", err)
+	}
+
+	err = cluster.SetupClientWithManager(mgr)
+	if err != nil {
+		return fmt.Errorf("client controller: %w", err)
+	}
+
+	err = cluster.SetupUserWithManager(mgr, tlsCache)
+	if err != nil {
+		return fmt.Errorf("user controller: %w", err)
+	}
+
+	err = cluster.SetupRoleWithManager(mgr, tlsCache)
+	if err != nil {
+		return fmt.Errorf("role controller: %w", err)
+	}
+
+	if config.WebhookCertDir != "" {
+		err = webhook.SetupWithManager(mgr)
+		if err != nil {
+			return fmt.Errorf("cluster webhook: %w", err)
+		}
+	}
+
 	meterProvider, err := SetupTelemetry(ctx)
 	if err != nil {
 		return fmt.Errorf("metrics provider: %w", err)
@@ -51,6 +51,18 @@ func Command() *cobra.Command {
 	flags.DurationVar(&config.Timeout, "timeout", DefaultTimeout, "operation timeout.")
 	flags.DurationVar(&config.ShutdownTimeout, "shutdown-timeout", DefaultTimeout, "shutdown timeout.")
 	flags.BoolVar(&config.Prune, "prune", true, "prune members without pods.")
+	flags.BoolVar(&config.Persistent, "persistent", false, "restart member in place using existing data dir.")
+	flags.StringVar(&config.ServerIssuer, "server-issuer", "", "kind/name of server certificate issuer, defaults to CA issuer of the cluster.")
+	flags.StringVar(&config.PeerIssuer, "peer-issuer", "", "kind/name of peer certificate issuer, defaults to CA issuer of the cluster.")
+	flags.StringVar(&config.CABundle, "ca-bundle", "", "config map of CAs trusted next to CA of the issuers during CA rotation.")
+	flags.StringVar(&config.KeyAlgorithm, "key-algorithm", string(cmv1.RSAKeyAlgorithm), "private key algorithm of member certificates: RSA, ECDSA or Ed25519.")
+	flags.IntVar(&config.KeySize, "key-size", 0, "private key size of member certificates, defaults to the size of key algorithm.")
+	flags.DurationVar(&config.Duration, "duration", cmv1.DefaultCertificateDuration, "duration of member certificates.")
+	flags.DurationVar(&config.RenewBefore, "renew-before", 0, "time before expiry when member certificates are renewed, defaults to a quarter of duration.")
+	flags.StringVar(&config.CommonName, "common-name", "", "common name of server certificate authenticating member as etcd user, defaults to certificate name.")
+	flags.StringVar(&config.ClusterDomain, "cluster-domain", "cluster.local", "DNS domain of Kubernetes services.")
+	flags.StringArrayVar(&config.DNSNames, "dns-name", nil, "additional DNS name of server certificate.")
+	flags.StringArrayVar(&config.IPAddresses, "ip-address", nil, "additional IP address of server certificate.")
 
 	_ = cmd.MarkFlagRequired("base-config")
 	_ = cmd.MarkFlagRequired("config")
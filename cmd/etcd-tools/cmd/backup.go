@@ -3,6 +3,7 @@ package cmd
 import (
 	"archive/tar"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -21,6 +22,7 @@ import (
 
 	etcdv3 "go.etcd.io/etcd/client/v3"
 
+	"github.com/agoda-com/etcd-operator/pkg/backup"
 	"github.com/agoda-com/etcd-operator/pkg/etcd"
 )
 
@@ -36,14 +38,15 @@ const (
 func BackupCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Short: "Backup cluster",
-		Use:   "backup [--credentials-dir DIR] [--endpoint ENDPOINT] [--bucket-info FILE] [--key KEY | --prefix PREFIX] [--retention DURATION]",
+		Use:   "backup [--credentials-dir DIR] [--endpoint ENDPOINT] [--bucket-info FILE] [--key KEY | --prefix PREFIX] [--retention DURATION] [--result-file FILE]",
 	}
 
 	flags := cmd.Flags()
 
 	endpoint := flags.String("endpoint", "", "etcd endpoint")
 	credentialsDir := flags.String("credentials-dir", "", "etcd credentials directory")
-	bucketInfoPath := flags.String("bucket-info", "", "object storage bucket info file")
+	bucketInfoPath := flags.String("bucket-info", "", "object storage bucket info file, AWS environment variables are used when not set")
+	resultPath := flags.String("result-file", "", "backup result output file")
 
 	params := BackupParams{}
 	flags.StringVar(&params.Key, "key", "", "object key")
@@ -63,6 +66,28 @@ func BackupCommand() *cobra.Command {
 			return fmt.Errorf("connect etcd: %w", err)
 		}
 
+		// use bucket configured by environment
+		if *bucketInfoPath == "" {
+			if params.Key == "" {
+				params.Key = path.Join(params.Prefix, time.Now().UTC().Format(DateFormat))
+			}
+
+			scl, err := backup.NewClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			result, err := backup.Backup(ctx, ecl, scl, backup.Location{
+				Bucket: os.Getenv("AWS_BUCKET_NAME"),
+				Key:    params.Key,
+			})
+			if err != nil {
+				return err
+			}
+
+			return WriteResult(*resultPath, result)
+		}
+
 		bucketInfo, err := LoadBucketInfo(*bucketInfoPath)
 		if err != nil {
 			return fmt.Errorf("load bucket: %w", err)
@@ -80,6 +105,20 @@ func BackupCommand() *cobra.Command {
 	return cmd
 }
 
+// WriteResult writes backup result as json, backup job reports it as container termination message
+func WriteResult(name string, result *backup.Result) error {
+	if name == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, 0644)
+}
+
 type BackupParams struct {
 	Bucket    string
 	Key       string
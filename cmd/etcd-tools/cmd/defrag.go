@@ -1,16 +1,12 @@
 package cmd
 
 import (
-	"context"
-	"errors"
 	"fmt"
 	"os"
 
+	"github.com/agoda-com/etcd-operator/pkg/defrag"
 	"github.com/agoda-com/etcd-operator/pkg/etcd"
 	"github.com/spf13/cobra"
-	"sigs.k8s.io/controller-runtime/pkg/log"
-
-	etcdv3 "go.etcd.io/etcd/client/v3"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -25,9 +21,9 @@ func DefragCommand() *cobra.Command {
 	endpoint := flags.String("endpoint", "", "etcd endpoint")
 	credentialsDir := flags.String("credentials-dir", "", "etcd credentials directory")
 
-	ratio := flags.Float64("unused-ratio", 0.7, "threshold ratio of unused space")
+	ratio := flags.Float64("unused-ratio", defrag.DefaultRatio, "threshold ratio of unused space")
 	size := resource.QuantityValue{
-		Quantity: resource.MustParse("128M"),
+		Quantity: *resource.NewQuantity(defrag.DefaultSize, resource.DecimalSI),
 	}
 	flags.Var(&size, "unused-size", "threshold size of unused space")
 
@@ -44,7 +40,7 @@ func DefragCommand() *cobra.Command {
 			return fmt.Errorf("connect etcd: %w", err)
 		}
 
-		return Defrag(ctx, ecl, DefragParams{
+		return defrag.Defrag(ctx, ecl, defrag.Params{
 			Ratio: *ratio,
 			Size:  size.Value(),
 		})
@@ -52,56 +48,3 @@ func DefragCommand() *cobra.Command {
 
 	return cmd
 }
-
-type DefragParams struct {
-	Ratio float64
-	Size  int64
-}
-
-func Defrag(ctx context.Context, ecl *etcdv3.Client, params DefragParams) error {
-	logger := log.FromContext(ctx)
-
-	members, err := ecl.MemberList(ctx)
-	if err != nil {
-		return err
-	}
-
-	var errs []error
-	for _, member := range members.Members {
-		if member.IsLearner || len(member.ClientURLs) == 0 {
-			continue
-		}
-
-		endpoint := member.ClientURLs[0]
-		logger := logger.WithValues("endpoint", endpoint)
-
-		memberStatus, err := ecl.Status(ctx, endpoint)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("status: %w", err))
-			continue
-		}
-
-		unused := memberStatus.DbSize - memberStatus.DbSizeInUse
-		ratio := float64(memberStatus.DbSize) / float64(memberStatus.DbSizeInUse)
-		if ratio > params.Ratio && unused > params.Size {
-			logger.Info("skipped")
-			continue
-		}
-
-		_, err = ecl.Compact(ctx, memberStatus.Header.Revision)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("compact: %w", err))
-			continue
-		}
-
-		_, err = ecl.Defragment(ctx, endpoint)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("defragment: %w", err))
-			continue
-		}
-
-		logger.Info("defragmented")
-	}
-
-	return errors.Join(errs...)
-}
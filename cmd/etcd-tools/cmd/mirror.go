@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+	"github.com/agoda-com/etcd-operator/pkg/mirror"
+)
+
+func MirrorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Short: "Mirror keys from source cluster",
+		Long:  "Keys are copied once and then kept in sync using watch API, mirroring resumes from the last synced revision after restart.",
+		Use:   "mirror [--credentials-dir DIR] [--endpoint ENDPOINT] [--source-credentials-dir DIR] [--source-endpoint ENDPOINT] [--prefix PREFIX]",
+	}
+
+	flags := cmd.Flags()
+	endpoint := flags.String("endpoint", "", "etcd endpoint of the standby cluster")
+	credentialsDir := flags.String("credentials-dir", "", "etcd credentials directory of the standby cluster")
+	sourceEndpoint := flags.String("source-endpoint", "", "etcd endpoint of the source cluster")
+	sourceCredentialsDir := flags.String("source-credentials-dir", "", "etcd credentials directory of the source cluster")
+	prefix := flags.String("prefix", "", "prefix of mirrored keys")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) (err error) {
+		ctx := cmd.Context()
+
+		tlsConfig, err := etcd.TLSConfig(etcd.LoadDir(os.DirFS(*credentialsDir)))
+		if err != nil {
+			return err
+		}
+
+		target, err := etcd.Connect(ctx, tlsConfig, *endpoint)
+		if err != nil {
+			return fmt.Errorf("connect etcd: %w", err)
+		}
+		defer func() {
+			err = errors.Join(err, target.Close())
+		}()
+
+		sourceTLSConfig, err := etcd.TLSConfig(etcd.LoadDir(os.DirFS(*sourceCredentialsDir)))
+		if err != nil {
+			return err
+		}
+
+		source, err := etcd.Connect(ctx, sourceTLSConfig, *sourceEndpoint)
+		if err != nil {
+			return fmt.Errorf("connect source etcd: %w", err)
+		}
+		defer func() {
+			err = errors.Join(err, source.Close())
+		}()
+
+		syncer := &mirror.Syncer{
+			Source: source,
+			Target: target,
+			Prefix: *prefix,
+		}
+
+		return syncer.Run(ctx)
+	}
+
+	return cmd
+}
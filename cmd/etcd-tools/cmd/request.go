@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/backup"
+)
+
+func RequestBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Short: "Request cluster backup.",
+		Long:  "Creates EtcdBackup for the cluster and deletes finished scheduled backups exceeding history limit.",
+		Use:   "request-backup --namespace NAMESPACE --cluster NAME [--history-limit LIMIT]",
+	}
+
+	flags := cmd.Flags()
+
+	cluster := client.ObjectKey{}
+	flags.StringVar(&cluster.Namespace, "namespace", "", "cluster namespace")
+	flags.StringVar(&cluster.Name, "cluster", "", "cluster name")
+	historyLimit := flags.Int("history-limit", 24, "number of finished scheduled backups to retain")
+
+	_ = cmd.MarkFlagRequired("namespace")
+	_ = cmd.MarkFlagRequired("cluster")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		if *historyLimit < 1 {
+			return errors.New("history limit must be positive")
+		}
+
+		kubeconfig, err := clientconfig.GetConfig()
+		if err != nil {
+			return fmt.Errorf("kubeconfig: %w", err)
+		}
+
+		builder := runtime.NewSchemeBuilder(
+			kscheme.AddToScheme,
+			apiv1.AddToScheme,
+		)
+		scheme := runtime.NewScheme()
+		if err := builder.AddToScheme(scheme); err != nil {
+			return err
+		}
+
+		kcl, err := client.New(kubeconfig, client.Options{
+			Scheme: scheme,
+		})
+		if err != nil {
+			return fmt.Errorf("k8s client: %w", err)
+		}
+
+		_, err = backup.Request(ctx, kcl, cluster, *historyLimit)
+		return err
+	}
+
+	return cmd
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -20,6 +21,7 @@ import (
 
 	"go.etcd.io/etcd/etcdutl/v3/snapshot"
 
+	"github.com/agoda-com/etcd-operator/pkg/backup"
 	"github.com/agoda-com/etcd-operator/pkg/etcd"
 )
 
@@ -34,15 +36,18 @@ func RestoreCommand() *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Short: "Restore database from bucket object.",
-		Long:  "When prefix is specified latest backup file will be used.",
-		Use:   "restore [--config=FILE] [--bucket-info=FILE] [--prefix=PREFIX | --key=KEY]",
+		Short: "Restore database from bucket object or from another cluster.",
+		Long:  "When prefix is specified latest backup file will be used. When endpoint is specified snapshot is streamed from the cluster instead.",
+		Use:   "restore [--config=FILE] [--bucket-info=FILE] [--prefix=PREFIX | --key=KEY | --endpoint=ENDPOINT --credentials-dir=DIR] [--result-file=FILE]",
 	}
 
 	flags := cmd.Flags()
 
 	bucketInfoPath := flags.String("bucket-info", "", "object storage bucket info file")
 	configPath := flags.String("config", "", "ETCD config file path")
+	endpoint := flags.String("endpoint", "", "etcd endpoint of the cluster to clone")
+	credentialsDir := flags.String("credentials-dir", "", "etcd credentials directory of the cluster to clone")
+	resultPath := flags.String("result-file", "", "clone result output file")
 
 	params := RestoreParams{}
 	flags.StringVar(&params.Key, "key", "", "S3 backup object")
@@ -53,6 +58,15 @@ func RestoreCommand() *cobra.Command {
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
+		if *endpoint != "" {
+			err := etcd.LoadConfig(*configPath, config)
+			if err != nil {
+				return err
+			}
+
+			return Clone(ctx, config, *endpoint, *credentialsDir, *resultPath)
+		}
+
 		if (params.Key == "" && params.Prefix == "") || (params.Key != "" && params.Prefix != "") {
 			return errors.New("either --prefix or --key have to be specified")
 		}
@@ -79,6 +93,52 @@ func RestoreCommand() *cobra.Command {
 	return cmd
 }
 
+// Clone restores member data from a snapshot of the cluster at endpoint,
+// snapshot revision is written to result file
+func Clone(ctx context.Context, config *etcd.Config, endpoint, credentialsDir, resultPath string) (err error) {
+	exists, err := DataExists(config)
+	switch {
+	case err != nil:
+		return err
+	case exists:
+		log.FromContext(ctx).Info("skipping existing data", "dir", config.DataDir)
+		return nil
+	}
+
+	tlsConfig, err := etcd.TLSConfig(etcd.LoadDir(os.DirFS(credentialsDir)))
+	if err != nil {
+		return err
+	}
+
+	ecl, err := etcd.Connect(ctx, tlsConfig, endpoint)
+	if err != nil {
+		return fmt.Errorf("connect etcd: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	result, err := backup.Clone(ctx, ecl, config)
+	if result == nil || err != nil {
+		return err
+	}
+
+	return WriteResult(resultPath, result)
+}
+
+// DataExists returns true when member is restarted with persistent data and restore has to be skipped
+func DataExists(config *etcd.Config) (bool, error) {
+	_, err := os.Stat(filepath.Join(config.DataDir, "member"))
+	switch {
+	case err == nil:
+		return true, nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return false, fmt.Errorf("stat data dir: %w", err)
+	}
+
+	return false, nil
+}
+
 type RestoreParams struct {
 	Bucket string
 	Key    string
@@ -93,6 +153,16 @@ func Restore(ctx context.Context, scl *s3.Client, config *etcd.Config, params Re
 		return nil
 	}
 
+	// member restarted with persistent data
+	exists, err := DataExists(config)
+	switch {
+	case err != nil:
+		return err
+	case exists:
+		logger.Info("skipping existing data", "dir", config.DataDir)
+		return nil
+	}
+
 	// if key not found find latest backup by prefix
 	// first file matching backup date format will be latest as they are ordered lexicographically
 	if params.Key == "" {
@@ -29,6 +29,8 @@ func RootCommand() *cobra.Command {
 	cmd.AddCommand(BackupCommand())
 	cmd.AddCommand(DefragCommand())
 	cmd.AddCommand(RestoreCommand())
+	cmd.AddCommand(RequestBackupCommand())
+	cmd.AddCommand(MirrorCommand())
 
 	return cmd
 }
@@ -1,11 +1,12 @@
 package e2e
 
 import (
-	"path"
+	"context"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,20 +44,29 @@ func TestBackup(t *testing.T) {
 		t.Fatal("failed to put key:", err)
 	}
 
-	// create backup
+	// request backup
 	key = client.ObjectKey{
 		Namespace: cluster.Namespace,
 		Name:      cluster.Name + "-backup",
 	}
 	triggerCronJob(t, kcl, key, 5*time.Minute)
 
+	// wait for requested backup to succeed
+	backup := scheduledBackup(t, kcl, cluster)
+	Poll(t, kcl, backup, 5*time.Minute, BackupFinished)
+	if backup.Status.Phase != apiv1.BackupSucceeded {
+		t.Fatalf("backup %q %s: %s", backup.Name, backup.Status.Reason, backup.Status.Message)
+	}
+
+	t.Logf("backup %q uploaded to %q at revision %d", backup.Name, backup.Status.Key, backup.Status.Revision)
+
 	// restore from backup
 	cluster = createCluster(t, kcl, 3*time.Minute, apiv1.EtcdClusterSpec{
 		Version:   "v3.5.14",
 		Replicas:  1,
 		Resources: resources,
 		Restore: &apiv1.RestoreSpec{
-			Prefix: ptr.To(path.Join(cluster.Namespace, cluster.Name)),
+			Key: ptr.To(backup.Status.Key),
 		},
 	})
 
@@ -76,4 +86,47 @@ func TestBackup(t *testing.T) {
 	if got = string(resp.Kvs[0].Value); got != v {
 		t.Errorf("key %q: expected %q, got %q", k, got, v)
 	}
+
+	// overwrite value and restore in-place
+	if _, err := ecl.Put(t.Context(), k, "nibbler"); err != nil {
+		t.Fatal("failed to put key:", err)
+	}
+
+	restore := &apiv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name,
+		},
+		Spec: apiv1.EtcdRestoreSpec{
+			ClusterName: cluster.Name,
+			BackupName:  backup.Name,
+		},
+	}
+	if err := kcl.Create(t.Context(), restore); err != nil {
+		t.Fatal("create restore:", err)
+	}
+	t.Cleanup(func() {
+		_ = kcl.Delete(context.Background(), restore)
+	})
+
+	Poll(t, kcl, restore, 5*time.Minute, RestoreFinished)
+	if restore.Status.Phase != apiv1.RestoreSucceeded {
+		t.Fatalf("restore %q %s: %s", restore.Name, restore.Status.Reason, restore.Status.Message)
+	}
+
+	Poll(t, kcl, cluster, 3*time.Minute, Available)
+
+	ecl = etcdClient(t, kcl, cluster)
+
+	resp, err = ecl.Get(t.Context(), k)
+	switch {
+	case err != nil:
+		t.Fatalf("get %q: %v", k, err)
+	case len(resp.Kvs) != 1:
+		t.Fatalf("key %q: expected exactly one value", k)
+	}
+
+	if got = string(resp.Kvs[0].Value); got != v {
+		t.Errorf("key %q: expected %q after in-place restore, got %q", k, v, got)
+	}
 }
@@ -2,6 +2,8 @@ package e2e
 
 //+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdclusters;etcdtenants,verbs=get;create;patch;delete
 //+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdclusters/scale,verbs=get;update
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdbackups,verbs=get;list
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdrestores,verbs=get;create;delete
 
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list
 //+kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
@@ -256,3 +256,31 @@ func triggerCronJob(t testing.TB, kcl client.Client, key client.ObjectKey, timeo
 
 	t.Logf("backup job %q completed", client.ObjectKeyFromObject(job))
 }
+
+// scheduledBackup returns backup created by the cluster backup cronjob
+func scheduledBackup(t testing.TB, kcl client.Client, cluster *apiv1.EtcdCluster) *apiv1.EtcdBackup {
+	t.Helper()
+
+	backups := &apiv1.EtcdBackupList{}
+	err := kcl.List(t.Context(), backups, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel:   apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+		apiv1.ScheduledLabel: "true",
+	})
+	if err != nil {
+		t.Fatal("list backups:", err)
+	}
+
+	if len(backups.Items) == 0 {
+		t.Fatalf("cluster %q has no scheduled backups", cluster.Name)
+	}
+
+	return &backups.Items[0]
+}
+
+func BackupFinished(backup *apiv1.EtcdBackup) bool {
+	return backup.Status.Phase == apiv1.BackupSucceeded || backup.Status.Phase == apiv1.BackupFailed
+}
+
+func RestoreFinished(restore *apiv1.EtcdRestore) bool {
+	return restore.Status.Phase == apiv1.RestoreSucceeded || restore.Status.Phase == apiv1.RestoreFailed
+}
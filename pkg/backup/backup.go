@@ -15,6 +15,8 @@ import (
 
 	"github.com/klauspost/pgzip"
 	etcdv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.uber.org/zap"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
@@ -28,9 +30,16 @@ const (
 	BackupTagDaily  BackupTag = "Daily"
 )
 
-func Backup(ctx context.Context, ecl *etcdv3.Client, scl *s3.Client, location Location) error {
+// Result describes uploaded backup object
+type Result struct {
+	Key      string `json:"key"`
+	Size     int64  `json:"size"`
+	Revision int64  `json:"revision"`
+}
+
+func Backup(ctx context.Context, ecl *etcdv3.Client, scl *s3.Client, location Location) (*Result, error) {
 	if location.Bucket == "" || location.Key == "" {
-		return ErrInvalidLocation
+		return nil, ErrInvalidLocation
 	}
 
 	logger := log.FromContext(ctx,
@@ -40,7 +49,7 @@ func Backup(ctx context.Context, ecl *etcdv3.Client, scl *s3.Client, location Lo
 
 	dir, err := os.MkdirTemp(os.TempDir(), "backup.*")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		err = errors.Join(err, os.RemoveAll(dir))
@@ -49,15 +58,20 @@ func Backup(ctx context.Context, ecl *etcdv3.Client, scl *s3.Client, location Lo
 	uncompressed := filepath.Join(dir, "snapshot.db")
 	err = SaveSnapshot(ctx, ecl, uncompressed)
 	if err != nil {
-		return fmt.Errorf("save snapshot to %q: %w", uncompressed, err)
+		return nil, fmt.Errorf("save snapshot to %q: %w", uncompressed, err)
+	}
+
+	status, err := snapshot.NewV3(zap.NewNop()).Status(uncompressed)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot status %q: %w", uncompressed, err)
 	}
 
-	logger.Info("saved snapshot", "target", uncompressed)
+	logger.Info("saved snapshot", "target", uncompressed, "revision", status.Revision)
 
 	compressed := filepath.Join(dir, "snapshot.tar.gz")
 	err = Compress(uncompressed, compressed)
 	if err != nil {
-		return fmt.Errorf("compress %q: %w", uncompressed, err)
+		return nil, fmt.Errorf("compress %q: %w", uncompressed, err)
 	}
 
 	logger.Info("compressed",
@@ -67,7 +81,7 @@ func Backup(ctx context.Context, ecl *etcdv3.Client, scl *s3.Client, location Lo
 
 	f, err := os.Open(compressed)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		err = errors.Join(err, f.Close())
@@ -84,7 +98,7 @@ func Backup(ctx context.Context, ecl *etcdv3.Client, scl *s3.Client, location Lo
 		obj, err := LatestBackup(ctx, scl, location.Bucket, prefix)
 		switch {
 		case err != nil:
-			return err
+			return nil, err
 		case obj == nil:
 			logger.Info("daily backup")
 			tag = BackupTagDaily
@@ -103,11 +117,21 @@ func Backup(ctx context.Context, ecl *etcdv3.Client, scl *s3.Client, location Lo
 	})
 	if err != nil {
 		logger.Error(err, "upload", "source", compressed)
-		return fmt.Errorf("upload snapshot: %w", err)
+		return nil, fmt.Errorf("upload snapshot: %w", err)
 	}
 
-	logger.Info("uploaded")
-	return nil
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("uploaded", "size", info.Size())
+
+	return &Result{
+		Key:      location.Key,
+		Size:     info.Size(),
+		Revision: status.Revision,
+	}, nil
 }
 
 func SaveSnapshot(ctx context.Context, ecl etcdv3.Maintenance, name string) (err error) {
@@ -39,10 +39,13 @@ func TestBackupRestore(t *testing.T) {
 		Bucket: os.Getenv("AWS_BUCKET_NAME"),
 		Key:    path.Join("backup-test", time.Now().Format(DateFormat)),
 	}
-	err = Backup(t.Context(), ecl, scl, location)
+	result, err := Backup(t.Context(), ecl, scl, location)
 	if err != nil {
 		t.Fatal("backup:", err)
 	}
+	if result.Key != location.Key || result.Size == 0 || result.Revision == 0 {
+		t.Errorf("unexpected backup result: %+v", result)
+	}
 
 	dataDir := t.TempDir()
 	config := &etcd.Config{
@@ -64,6 +67,60 @@ func TestBackupRestore(t *testing.T) {
 		DataDir: dataDir,
 	}
 	setupEtcd(t, db)
+
+	deleted, err := DeleteObjects(t.Context(), scl, location.Bucket, location.Key)
+	switch {
+	case err != nil:
+		t.Fatal("delete objects:", err)
+	case deleted != 1:
+		t.Errorf("expected 1 deleted object, got %d", deleted)
+	}
+}
+
+func TestClone(t *testing.T) {
+	if testing.Short() || os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("envtest is not configured")
+	}
+
+	source := &envtest.Etcd{
+		Path: filepath.Join(os.Getenv("KUBEBUILDER_ASSETS"), "etcd"),
+	}
+	ecl := setupEtcd(t, source)
+
+	_, err := ecl.Put(t.Context(), "clone-test", "value")
+	if err != nil {
+		t.Fatal("put:", err)
+	}
+
+	dataDir := t.TempDir()
+	config := &etcd.Config{
+		Name:                     "peer0",
+		InitialCluster:           "peer0=http://localhost:2380",
+		InitialAdvertisePeerURLs: "http://localhost:2380",
+		InitialClusterState:      etcd.InitialStateNew,
+		InitialClusterToken:      "clone",
+		DataDir:                  dataDir,
+	}
+	result, err := Clone(t.Context(), ecl, config)
+	switch {
+	case err != nil:
+		t.Fatal("clone:", err)
+	case result == nil || result.Revision == 0:
+		t.Fatalf("unexpected clone result: %+v", result)
+	}
+
+	// start etcd from cloned data dir
+	target := &envtest.Etcd{
+		Path:    source.Path,
+		DataDir: dataDir,
+	}
+	res, err := setupEtcd(t, target).Get(t.Context(), "clone-test")
+	switch {
+	case err != nil:
+		t.Fatal("get:", err)
+	case len(res.Kvs) != 1 || string(res.Kvs[0].Value) != "value":
+		t.Errorf("expected cloned key, got %v", res.Kvs)
+	}
 }
 
 func setupEtcd(t testing.TB, db *envtest.Etcd) *etcdv3.Client {
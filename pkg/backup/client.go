@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"iter"
 	"net/http"
 
@@ -92,3 +93,57 @@ func LatestBackup(ctx context.Context, client manager.ListObjectsV2APIClient, bu
 
 	return latest, nil
 }
+
+// DeleteObjects deletes all objects with prefix and returns number of deleted objects
+func DeleteObjects(ctx context.Context, client *s3.Client, bucket, prefix string) (int, error) {
+	if bucket == "" {
+		return 0, errors.New("AWS_BUCKET_NAME is required")
+	}
+
+	deleted := 0
+	var identifiers []types.ObjectIdentifier
+	flush := func() error {
+		if len(identifiers) == 0 {
+			return nil
+		}
+
+		resp, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: identifiers,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// quiet response reports failed keys only
+		deleted += len(identifiers) - len(resp.Errors)
+		identifiers = identifiers[:0]
+		if len(resp.Errors) != 0 {
+			first := resp.Errors[0]
+			return fmt.Errorf("failed to delete %d objects, %q: %s", len(resp.Errors), aws.ToString(first.Key), aws.ToString(first.Message))
+		}
+
+		return nil
+	}
+
+	for obj, err := range ListObjects(ctx, client, bucket, prefix) {
+		if err != nil {
+			return deleted, err
+		}
+
+		identifiers = append(identifiers, types.ObjectIdentifier{Key: obj.Key})
+
+		// delete request is limited to 1000 keys
+		if len(identifiers) == 1000 {
+			err = flush()
+			if err != nil {
+				return deleted, err
+			}
+		}
+	}
+
+	return deleted, flush()
+}
@@ -0,0 +1,73 @@
+package backup
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdbackups,verbs=get;list;create;delete
+
+// Request creates scheduled EtcdBackup for the cluster and deletes finished scheduled backups exceeding history limit
+func Request(ctx context.Context, kcl client.Client, cluster client.ObjectKey, historyLimit int) (*apiv1.EtcdBackup, error) {
+	logger := log.FromContext(ctx, "cluster", cluster)
+
+	labels := map[string]string{
+		apiv1.ClusterLabel:   apiv1.ClusterLabelValue(cluster),
+		apiv1.ScheduledLabel: "true",
+	}
+
+	backup := &apiv1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    cluster.Namespace,
+			GenerateName: cluster.Name + "-",
+			Labels:       labels,
+		},
+		Spec: apiv1.EtcdBackupSpec{
+			ClusterName: cluster.Name,
+		},
+	}
+	err := kcl.Create(ctx, backup)
+	if err != nil {
+		return nil, fmt.Errorf("create backup: %w", err)
+	}
+
+	logger.Info("created backup", "name", backup.Name)
+
+	backups := &apiv1.EtcdBackupList{}
+	err = kcl.List(ctx, backups, client.InNamespace(cluster.Namespace), client.MatchingLabels(labels))
+	if err != nil {
+		return nil, fmt.Errorf("list backups: %w", err)
+	}
+
+	// keep latest finished backups
+	finished := slices.DeleteFunc(backups.Items, func(backup apiv1.EtcdBackup) bool {
+		return backup.Status.Phase != apiv1.BackupSucceeded && backup.Status.Phase != apiv1.BackupFailed
+	})
+	if len(finished) <= historyLimit {
+		return backup, nil
+	}
+
+	slices.SortFunc(finished, func(l, r apiv1.EtcdBackup) int {
+		return cmp.Compare(r.CreationTimestamp.UnixNano(), l.CreationTimestamp.UnixNano())
+	})
+
+	for _, obsolete := range finished[historyLimit:] {
+		err = kcl.Delete(ctx, &obsolete)
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("delete backup %q: %w", obsolete.Name, err)
+		}
+
+		logger.Info("deleted backup", "name", obsolete.Name)
+	}
+
+	return backup, nil
+}
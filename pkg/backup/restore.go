@@ -15,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/klauspost/pgzip"
+	etcdv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/etcdutl/v3/snapshot"
 	"go.uber.org/zap"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -67,9 +68,70 @@ func Restore(ctx context.Context, scl *s3.Client, config *etcd.Config, location
 		"target", decompressed,
 	)
 
+	err = RestoreSnapshot(decompressed, config)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("restored from snapshot",
+		"snapshot", decompressed,
+		"data", config.DataDir,
+	)
+
+	return nil
+}
+
+// Clone restores member data from a snapshot streamed from another cluster
+func Clone(ctx context.Context, ecl etcdv3.Maintenance, config *etcd.Config) (result *Result, err error) {
+	logger := log.FromContext(ctx)
+
+	if config.InitialClusterState != etcd.InitialStateNew {
+		logger.Info("skipping existing cluster")
+		return nil, nil
+	}
+
+	dir, err := os.MkdirTemp(os.TempDir(), "clone.*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, os.RemoveAll(dir))
+	}()
+
+	name := filepath.Join(dir, "snapshot.db")
+	err = SaveSnapshot(ctx, ecl, name)
+	if err != nil {
+		return nil, fmt.Errorf("save snapshot to %q: %w", name, err)
+	}
+
+	status, err := snapshot.NewV3(zap.NewNop()).Status(name)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot status %q: %w", name, err)
+	}
+
+	logger.Info("saved snapshot", "target", name, "revision", status.Revision)
+
+	err = RestoreSnapshot(name, config)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("restored from snapshot",
+		"snapshot", name,
+		"data", config.DataDir,
+	)
+
+	return &Result{
+		Size:     status.TotalSize,
+		Revision: status.Revision,
+	}, nil
+}
+
+// RestoreSnapshot writes member data directory from snapshot file
+func RestoreSnapshot(name string, config *etcd.Config) error {
 	sm := snapshot.NewV3(zap.NewNop())
-	err = sm.Restore(snapshot.RestoreConfig{
-		SnapshotPath:        decompressed,
+	err := sm.Restore(snapshot.RestoreConfig{
+		SnapshotPath:        name,
 		Name:                config.Name,
 		OutputDataDir:       config.DataDir,
 		PeerURLs:            strings.Split(config.InitialAdvertisePeerURLs, ","),
@@ -77,14 +139,9 @@ func Restore(ctx context.Context, scl *s3.Client, config *etcd.Config, location
 		InitialClusterToken: config.InitialClusterToken,
 	})
 	if err != nil {
-		return fmt.Errorf("restore %q: %w", decompressed, err)
+		return fmt.Errorf("restore %q: %w", name, err)
 	}
 
-	logger.Info("restored from snapshot",
-		"snapshot", decompressed,
-		"data", config.DataDir,
-	)
-
 	return nil
 }
 
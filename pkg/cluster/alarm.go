@@ -0,0 +1,254 @@
+package cluster
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/defrag"
+)
+
+const (
+	// MemberRefreshInterval is the interval of refreshing last successful time of member status
+	MemberRefreshInterval = time.Minute
+	// AlarmPollInterval of alarm remediation steps
+	AlarmPollInterval = 10 * time.Second
+	// DefragTimeout of member defragmentation, member does not serve requests until it is defragmented
+	DefragTimeout = 5 * time.Minute
+	// DefragUnusedRatio is the ratio of unused space which is reclaimed from members during NOSPACE alarm remediation
+	DefragUnusedRatio = 0.1
+)
+
+// ReconcileAlarms reports active alarms in Alarm condition and remediates NOSPACE alarm: revisions are compacted,
+// members are defragmented one at a time and the alarm is disarmed once database size of all members is below quota
+func (r *Reconciler) ReconcileAlarms(ctx context.Context, cluster *apiv1.EtcdCluster) (err error) {
+	if cluster.Status.Phase != apiv1.ClusterRunning {
+		return nil
+	}
+
+	cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterAlarm)
+	if len(cluster.Status.Alarms) == 0 {
+		if cond.Status == corev1.ConditionTrue {
+			r.alarmCondition(cluster, corev1.ConditionFalse, "Disarmed", "no active alarms")
+		}
+		return nil
+	}
+
+	message := FormatAlarms(cluster.Status.Alarms)
+	if cond.Status != corev1.ConditionTrue {
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "AlarmRaised", "Alarms raised: %s", message)
+	}
+
+	// CORRUPT alarm is not remediated, corrupted member has to be replaced
+	if !slices.ContainsFunc(cluster.Status.Alarms, noSpace) {
+		r.alarmCondition(cluster, corev1.ConditionTrue, "Corrupt", message)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, DefragTimeout, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	// revisions are compacted once when remediation starts
+	if cond.Status != corev1.ConditionTrue || (cond.Reason != "Defragmenting" && cond.Reason != "QuotaExceeded") {
+		rev, err := defrag.Compact(ctx, ecl, cluster.Status.Endpoint)
+		if err != nil {
+			r.alarmCondition(cluster, corev1.ConditionTrue, "Compacting", fmt.Sprintf("%s, compact: %v", message, rpctypes.ErrorDesc(err)))
+			return nil
+		}
+
+		r.alarmCondition(cluster, corev1.ConditionTrue, "Defragmenting", message)
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "Compacted", "Compacted revisions before %d", rev)
+		return nil
+	}
+
+	quota := StorageQuota(cluster)
+	member := NextDefrag(cluster.Status.Members, ReclaimsQuota(quota))
+	switch {
+	// defrag blocks member requests, wait for other members to be available. Members report active alarms
+	// as errors and are not available until alarms are disarmed, members which respond are counted instead.
+	case member != nil && RespondingReplicas(cluster.Status.Members) < cluster.Status.Replicas:
+		r.alarmCondition(cluster, corev1.ConditionTrue, "Defragmenting", fmt.Sprintf("%s, waiting for members to be available", message))
+		return nil
+	case member != nil:
+		_, err = ecl.Defragment(ctx, member.Endpoint)
+		if err != nil {
+			r.alarmCondition(cluster, corev1.ConditionTrue, "Defragmenting", fmt.Sprintf("%s, defragment member %s: %v", message, member.Name, rpctypes.ErrorDesc(err)))
+			return nil
+		}
+
+		member.LastDefragTime = ptr.To(metav1.Now())
+		r.alarmCondition(cluster, corev1.ConditionTrue, "Defragmenting", message)
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "Defragmented", "Defragmented member %s", member.Name)
+		return nil
+	}
+
+	// defrag does not reclaim enough space, storage quota has to be increased or keys deleted
+	exceeded := slices.ContainsFunc(cluster.Status.Members, func(member apiv1.MemberStatus) bool {
+		return member.Size != nil && member.Size.Cmp(quota) >= 0
+	})
+	if exceeded {
+		if cond.Reason != "QuotaExceeded" {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "QuotaExceeded", "Database size exceeds storage quota %s, increase storage or delete keys", quota.String())
+		}
+		r.alarmCondition(cluster, corev1.ConditionTrue, "QuotaExceeded", fmt.Sprintf("%s, database size exceeds storage quota %s", message, quota.String()))
+		return nil
+	}
+
+	alarms, err := ecl.AlarmList(ctx)
+	if err != nil {
+		r.alarmCondition(cluster, corev1.ConditionTrue, "Defragmenting", fmt.Sprintf("%s, list alarms: %v", message, rpctypes.ErrorDesc(err)))
+		return nil
+	}
+
+	for _, alarm := range alarms.Alarms {
+		if alarm.Alarm != etcdserverpb.AlarmType_NOSPACE {
+			continue
+		}
+
+		_, err = ecl.AlarmDisarm(ctx, &etcdv3.AlarmMember{MemberID: alarm.MemberID, Alarm: alarm.Alarm})
+		if err != nil {
+			r.alarmCondition(cluster, corev1.ConditionTrue, "Defragmenting", fmt.Sprintf("%s, disarm alarm: %v", message, rpctypes.ErrorDesc(err)))
+			return nil
+		}
+	}
+
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "AlarmDisarmed", "Disarmed alarms: %s", message)
+
+	// disarmed alarms are dropped from status until it is refreshed
+	cluster.Status.Alarms = slices.DeleteFunc(cluster.Status.Alarms, noSpace)
+	for i := range cluster.Status.Members {
+		cluster.Status.Members[i].Alarms = slices.DeleteFunc(cluster.Status.Members[i].Alarms, func(alarm string) bool {
+			return alarm == etcdserverpb.AlarmType_NOSPACE.String()
+		})
+	}
+
+	if len(cluster.Status.Alarms) != 0 {
+		r.alarmCondition(cluster, corev1.ConditionTrue, "Corrupt", FormatAlarms(cluster.Status.Alarms))
+		return nil
+	}
+
+	r.alarmCondition(cluster, corev1.ConditionFalse, "Disarmed", "no active alarms")
+
+	return nil
+}
+
+func (r *Reconciler) alarmCondition(cluster *apiv1.EtcdCluster, status corev1.ConditionStatus, reason, message string) {
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterAlarm,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// NextDefrag returns the next member to defragment, followers are defragmented before leader.
+// Members without database size are skipped.
+func NextDefrag(members []apiv1.MemberStatus, needed func(member *apiv1.MemberStatus) bool) *apiv1.MemberStatus {
+	var leader *apiv1.MemberStatus
+	for i := range members {
+		member := &members[i]
+		if member.Endpoint == "" || member.Size == nil || member.SizeInUse == nil || !needed(member) {
+			continue
+		}
+
+		if member.Role != apiv1.MemberRoleLeader {
+			return member
+		}
+		leader = member
+	}
+
+	return leader
+}
+
+// RespondingReplicas returns the number of members except read replicas which responded to status request
+func RespondingReplicas(members []apiv1.MemberStatus) int32 {
+	var responding int32
+	for _, member := range members {
+		if member.Version != "" && member.Role != apiv1.MemberRoleReadReplica {
+			responding++
+		}
+	}
+
+	return responding
+}
+
+// ReclaimsQuota returns true for members which defrag brings below quota or reclaims at least DefragUnusedRatio of their size
+func ReclaimsQuota(quota resource.Quantity) func(member *apiv1.MemberStatus) bool {
+	return func(member *apiv1.MemberStatus) bool {
+		size, inUse := member.Size.Value(), member.SizeInUse.Value()
+		if size >= quota.Value() && inUse < quota.Value() {
+			return true
+		}
+
+		return float64(size-inUse) >= DefragUnusedRatio*float64(size)
+	}
+}
+
+// FormatAlarms formats alarms for condition messages and events
+func FormatAlarms(alarms []apiv1.AlarmStatus) string {
+	formatted := make([]string, len(alarms))
+	for i, alarm := range alarms {
+		formatted[i] = fmt.Sprintf("%s of member %s", alarm.Alarm, cmp.Or(alarm.Name, alarm.MemberID))
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func noSpace(alarm apiv1.AlarmStatus) bool {
+	return alarm.Alarm == etcdserverpb.AlarmType_NOSPACE.String()
+}
+
+// MemberAlarms maps alarm list response to alarm status of members sorted by member name and alarm type
+func MemberAlarms(members []*etcdserverpb.Member, alarms []*etcdserverpb.AlarmMember) []apiv1.AlarmStatus {
+	var statuses []apiv1.AlarmStatus
+	for _, alarm := range alarms {
+		if alarm.Alarm == etcdserverpb.AlarmType_NONE {
+			continue
+		}
+
+		status := apiv1.AlarmStatus{
+			MemberID: apiv1.FormatMemberID(alarm.MemberID),
+			Alarm:    alarm.Alarm.String(),
+		}
+		if i := slices.IndexFunc(members, func(member *etcdserverpb.Member) bool {
+			return member.ID == alarm.MemberID
+		}); i != -1 {
+			status.Name = members[i].Name
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	slices.SortFunc(statuses, func(l, r apiv1.AlarmStatus) int {
+		return cmp.Or(
+			strings.Compare(l.Name, r.Name),
+			strings.Compare(l.MemberID, r.MemberID),
+			strings.Compare(l.Alarm, r.Alarm),
+		)
+	})
+
+	return statuses
+}
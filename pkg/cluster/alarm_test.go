@@ -0,0 +1,341 @@
+package cluster
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+)
+
+func TestMemberAlarms(t *testing.T) {
+	members := []*etcdserverpb.Member{
+		{ID: 1, Name: "test-cluster-b"},
+		{ID: 2, Name: "test-cluster-a"},
+	}
+
+	tests := []struct {
+		name     string
+		alarms   []*etcdserverpb.AlarmMember
+		expected []apiv1.AlarmStatus
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "nospace",
+			alarms: []*etcdserverpb.AlarmMember{
+				{MemberID: 1, Alarm: etcdserverpb.AlarmType_NOSPACE},
+				{MemberID: 2, Alarm: etcdserverpb.AlarmType_NOSPACE},
+			},
+			expected: []apiv1.AlarmStatus{
+				{MemberID: apiv1.FormatMemberID(2), Name: "test-cluster-a", Alarm: "NOSPACE"},
+				{MemberID: apiv1.FormatMemberID(1), Name: "test-cluster-b", Alarm: "NOSPACE"},
+			},
+		},
+		{
+			name: "removed member",
+			alarms: []*etcdserverpb.AlarmMember{
+				{MemberID: 1, Alarm: etcdserverpb.AlarmType_NONE},
+				{MemberID: 1, Alarm: etcdserverpb.AlarmType_CORRUPT},
+				{MemberID: 3, Alarm: etcdserverpb.AlarmType_NOSPACE},
+			},
+			expected: []apiv1.AlarmStatus{
+				{MemberID: apiv1.FormatMemberID(3), Alarm: "NOSPACE"},
+				{MemberID: apiv1.FormatMemberID(1), Name: "test-cluster-b", Alarm: "CORRUPT"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alarms := MemberAlarms(members, tt.alarms)
+			if !slices.Equal(alarms, tt.expected) {
+				t.Errorf("expected alarms %v, got %v", tt.expected, alarms)
+			}
+		})
+	}
+}
+
+func TestNextDefrag(t *testing.T) {
+	quota := resource.MustParse("2G")
+	member := func(name string, role apiv1.MemberRole, size, inUse string) apiv1.MemberStatus {
+		return apiv1.MemberStatus{
+			Name:      name,
+			Endpoint:  "https://" + name + ":2379",
+			Role:      role,
+			Size:      ptr.To(resource.MustParse(size)),
+			SizeInUse: ptr.To(resource.MustParse(inUse)),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		members  []apiv1.MemberStatus
+		expected string
+	}{
+		{
+			name: "defragmented",
+			members: []apiv1.MemberStatus{
+				member("test-cluster-a", apiv1.MemberRoleLeader, "1G", "950M"),
+				member("test-cluster-b", apiv1.MemberRoleMember, "1G", "950M"),
+			},
+		},
+		{
+			name: "leader last",
+			members: []apiv1.MemberStatus{
+				member("test-cluster-a", apiv1.MemberRoleLeader, "2G", "500M"),
+				member("test-cluster-b", apiv1.MemberRoleMember, "1G", "950M"),
+				member("test-cluster-c", apiv1.MemberRoleMember, "2G", "500M"),
+			},
+			expected: "test-cluster-c",
+		},
+		{
+			name: "leader",
+			members: []apiv1.MemberStatus{
+				member("test-cluster-a", apiv1.MemberRoleLeader, "2G", "500M"),
+				member("test-cluster-b", apiv1.MemberRoleMember, "500M", "500M"),
+			},
+			expected: "test-cluster-a",
+		},
+		{
+			name: "below quota",
+			members: []apiv1.MemberStatus{
+				member("test-cluster-a", apiv1.MemberRoleLeader, "2G", "1950M"),
+			},
+			expected: "test-cluster-a",
+		},
+		{
+			name: "quota exceeded",
+			members: []apiv1.MemberStatus{
+				member("test-cluster-a", apiv1.MemberRoleLeader, "2100M", "2G"),
+			},
+		},
+		{
+			name: "unknown",
+			members: []apiv1.MemberStatus{
+				{Name: "test-cluster-a", Role: apiv1.MemberRoleLearner},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var name string
+			if member := NextDefrag(tt.members, ReclaimsQuota(quota)); member != nil {
+				name = member.Name
+			}
+			if name != tt.expected {
+				t.Errorf("expected member %q, got %q", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestReconcileAlarms(t *testing.T) {
+	member := func(name, version, size, inUse string, alarms ...string) apiv1.MemberStatus {
+		return apiv1.MemberStatus{
+			ID:        name,
+			Name:      "test-cluster-" + name,
+			Endpoint:  "https://test-cluster-" + name + ":2379",
+			Version:   version,
+			Role:      apiv1.MemberRoleMember,
+			Size:      ptr.To(resource.MustParse(size)),
+			SizeInUse: ptr.To(resource.MustParse(inUse)),
+			Alarms:    alarms,
+		}
+	}
+	noSpace := apiv1.AlarmStatus{MemberID: "a", Name: "test-cluster-a", Alarm: "NOSPACE"}
+	corrupt := apiv1.AlarmStatus{MemberID: "b", Name: "test-cluster-b", Alarm: "CORRUPT"}
+
+	tests := []struct {
+		name         string
+		reason       string
+		alarms       []apiv1.AlarmStatus
+		members      []apiv1.MemberStatus
+		errors       map[string]error
+		requests     []string
+		expected     string
+		defragmented string
+		statusAlarms []apiv1.AlarmStatus
+		memberAlarms []string
+	}{
+		{
+			name:     "corrupt",
+			alarms:   []apiv1.AlarmStatus{corrupt},
+			expected: "Corrupt",
+		},
+		{
+			name:     "compacted",
+			alarms:   []apiv1.AlarmStatus{noSpace},
+			requests: []string{"Status https://test-cluster.default.svc.cluster.local:2379", "Compact 42"},
+			expected: "Defragmenting",
+		},
+		{
+			name:     "compact failed",
+			alarms:   []apiv1.AlarmStatus{noSpace},
+			errors:   map[string]error{"Compact 42": errors.New("timeout")},
+			requests: []string{"Status https://test-cluster.default.svc.cluster.local:2379", "Compact 42"},
+			expected: "Compacting",
+		},
+		{
+			name:   "degraded",
+			reason: "Defragmenting",
+			alarms: []apiv1.AlarmStatus{noSpace},
+			members: []apiv1.MemberStatus{
+				member("a", "3.5.7", "4G", "1G", "NOSPACE"),
+				member("b", "", "4G", "1G"),
+				member("c", "3.5.7", "4G", "1G"),
+			},
+			expected: "Defragmenting",
+		},
+		{
+			name:   "defragmented",
+			reason: "Defragmenting",
+			alarms: []apiv1.AlarmStatus{noSpace},
+			members: []apiv1.MemberStatus{
+				member("a", "3.5.7", "1G", "1G", "NOSPACE"),
+				member("b", "3.5.7", "4G", "1G"),
+				member("c", "3.5.7", "4G", "1G"),
+			},
+			requests:     []string{"Defragment https://test-cluster-b:2379"},
+			expected:     "Defragmenting",
+			defragmented: "test-cluster-b",
+		},
+		{
+			name:   "quota exceeded",
+			reason: "Defragmenting",
+			alarms: []apiv1.AlarmStatus{noSpace},
+			members: []apiv1.MemberStatus{
+				member("a", "3.5.7", "4G", "4G", "NOSPACE"),
+				member("b", "3.5.7", "1G", "1G"),
+				member("c", "3.5.7", "1G", "1G"),
+			},
+			expected:     "QuotaExceeded",
+			statusAlarms: []apiv1.AlarmStatus{noSpace},
+			memberAlarms: []string{"NOSPACE"},
+		},
+		{
+			name:   "disarmed",
+			reason: "QuotaExceeded",
+			alarms: []apiv1.AlarmStatus{noSpace, corrupt},
+			members: []apiv1.MemberStatus{
+				member("a", "3.5.7", "1G", "1G", "NOSPACE", "CORRUPT"),
+				member("b", "3.5.7", "1G", "1G"),
+				member("c", "3.5.7", "1G", "1G"),
+			},
+			requests:     []string{"AlarmList", "AlarmDisarm a NOSPACE"},
+			expected:     "Corrupt",
+			statusAlarms: []apiv1.AlarmStatus{corrupt},
+			memberAlarms: []string{"CORRUPT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Status.Replicas = 3
+			cluster.Status.Alarms = tt.alarms
+			cluster.Status.Members = tt.members
+			if tt.reason != "" {
+				conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+					Type:   apiv1.ClusterAlarm,
+					Status: corev1.ConditionTrue,
+					Reason: tt.reason,
+				})
+			}
+
+			fake := &fakeEtcd{
+				errors:   tt.errors,
+				revision: 42,
+				alarms: []*etcdserverpb.AlarmMember{
+					{MemberID: 0xa, Alarm: etcdserverpb.AlarmType_NOSPACE},
+					{MemberID: 0xb, Alarm: etcdserverpb.AlarmType_CORRUPT},
+				},
+			}
+			fakeConnect(t, fake)
+
+			r := &Reconciler{
+				kcl:      createTestClient(t),
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			err := r.ReconcileAlarms(t.Context(), cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterAlarm)
+			switch {
+			case !slices.Equal(fake.requests, tt.requests):
+				t.Errorf("expected requests %v, got %v", tt.requests, fake.requests)
+			case cond.Status != corev1.ConditionTrue || cond.Reason != tt.expected:
+				t.Errorf("expected alarm condition %s, got %s/%s: %s", tt.expected, cond.Status, cond.Reason, cond.Message)
+			}
+
+			for _, member := range cluster.Status.Members {
+				expected := member.Name == tt.defragmented
+				if defragmented := member.LastDefragTime != nil; defragmented != expected {
+					t.Errorf("expected member %s defragmented %v, got %v", member.Name, expected, defragmented)
+				}
+			}
+
+			if tt.statusAlarms == nil {
+				return
+			}
+			if !slices.Equal(cluster.Status.Alarms, tt.statusAlarms) {
+				t.Errorf("expected alarms %v, got %v", tt.statusAlarms, cluster.Status.Alarms)
+			}
+			if alarms := cluster.Status.Members[0].Alarms; !slices.Equal(alarms, tt.memberAlarms) {
+				t.Errorf("expected member alarms %v, got %v", tt.memberAlarms, alarms)
+			}
+		})
+	}
+}
+
+func TestReconcileAlarmsDisarmed(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Status.Replicas = 1
+	cluster.Status.Alarms = []apiv1.AlarmStatus{{MemberID: "a", Alarm: "NOSPACE"}}
+	cluster.Status.Members = []apiv1.MemberStatus{
+		{ID: "a", Version: "3.5.7", Size: ptr.To(resource.MustParse("1G")), Alarms: []string{"NOSPACE"}},
+	}
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:   apiv1.ClusterAlarm,
+		Status: corev1.ConditionTrue,
+		Reason: "Defragmenting",
+	})
+
+	fake := &fakeEtcd{
+		alarms: []*etcdserverpb.AlarmMember{{MemberID: 0xa, Alarm: etcdserverpb.AlarmType_NOSPACE}},
+	}
+	fakeConnect(t, fake)
+
+	r := &Reconciler{
+		kcl:      createTestClient(t),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	err := r.ReconcileAlarms(t.Context(), cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterAlarm)
+	switch {
+	case cond.Status != corev1.ConditionFalse || cond.Reason != "Disarmed":
+		t.Errorf("expected disarmed alarm condition, got %s/%s: %s", cond.Status, cond.Reason, cond.Message)
+	case len(cluster.Status.Alarms) != 0 || len(cluster.Status.Members[0].Alarms) != 0:
+		t.Errorf("expected no alarms, got %v and member alarms %v", cluster.Status.Alarms, cluster.Status.Members[0].Alarms)
+	case len(fake.alarms) != 0:
+		t.Errorf("expected alarms to be disarmed in etcd, got %v", fake.alarms)
+	}
+}
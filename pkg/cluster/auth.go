@@ -0,0 +1,238 @@
+package cluster
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+const (
+	// RootUser is etcd user of root credentials and members while auth is enabled
+	RootUser = "root"
+	// AuthPollInterval of auth changes waiting for members and root credentials
+	AuthPollInterval = 30 * time.Second
+	// AuthDriftInterval is the interval of drift detection of users and roles
+	AuthDriftInterval = 5 * time.Minute
+)
+
+// AuthEnabled returns true when auth is enabled by spec or has not been disabled in etcd yet,
+// members and root credentials authenticate as root user until then
+func AuthEnabled(cluster *apiv1.EtcdCluster) bool {
+	return (cluster.Spec.Auth != nil && cluster.Spec.Auth.Enabled) || conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterAuth)
+}
+
+// AuthPending returns true while etcd auth differs from spec
+func AuthPending(cluster *apiv1.EtcdCluster) bool {
+	enabled := cluster.Spec.Auth != nil && cluster.Spec.Auth.Enabled
+	return enabled != conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterAuth)
+}
+
+// ReconcileAuth enables etcd auth once members and root credentials authenticate as root user,
+// auth is disabled before they are reissued with default common names
+func (r *Reconciler) ReconcileAuth(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	if cluster.Status.Phase != apiv1.ClusterRunning || !AuthPending(cluster) {
+		return nil
+	}
+
+	enabled := cluster.Spec.Auth != nil && cluster.Spec.Auth.Enabled
+	cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterAuth)
+
+	switch {
+	case enabled:
+		return r.enableAuth(ctx, cluster)
+	// auth was disabled before it was enabled in etcd
+	case cond.Reason == "Enabling":
+		r.authCondition(cluster, corev1.ConditionFalse, "Disabled", "authentication is disabled")
+		return nil
+	}
+
+	return r.disableAuth(ctx, cluster)
+}
+
+func (r *Reconciler) enableAuth(ctx context.Context, cluster *apiv1.EtcdCluster) (err error) {
+	restarted, err := r.membersRestarted(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	authenticated, err := r.rootAuthenticated(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if !restarted || !authenticated {
+		r.authCondition(cluster, corev1.ConditionFalse, "Enabling", "waiting for members and root credentials to authenticate as root user")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, 30*time.Second, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	// etcd requires root user with root role to enable auth
+	_, err = ecl.RoleAdd(ctx, RootUser)
+	if err != nil && !errors.Is(err, rpctypes.ErrRoleAlreadyExist) {
+		r.authCondition(cluster, corev1.ConditionFalse, "Enabling", fmt.Sprintf("add root role: %v", rpctypes.ErrorDesc(err)))
+		return nil
+	}
+
+	_, err = ecl.UserAddWithOptions(ctx, RootUser, "", &etcdv3.UserAddOptions{NoPassword: true})
+	if err != nil && !errors.Is(err, rpctypes.ErrUserAlreadyExist) {
+		r.authCondition(cluster, corev1.ConditionFalse, "Enabling", fmt.Sprintf("add root user: %v", rpctypes.ErrorDesc(err)))
+		return nil
+	}
+
+	_, err = ecl.UserGrantRole(ctx, RootUser, RootUser)
+	if err != nil {
+		r.authCondition(cluster, corev1.ConditionFalse, "Enabling", fmt.Sprintf("grant root role: %v", rpctypes.ErrorDesc(err)))
+		return nil
+	}
+
+	_, err = ecl.AuthEnable(ctx)
+	if err != nil {
+		r.authCondition(cluster, corev1.ConditionFalse, "Enabling", fmt.Sprintf("enable auth: %v", rpctypes.ErrorDesc(err)))
+		return nil
+	}
+
+	r.authCondition(cluster, corev1.ConditionTrue, "Enabled", "authentication is enabled")
+	r.recorder.Event(cluster, corev1.EventTypeNormal, "AuthEnabled", "Enabled etcd authentication")
+
+	// mirror worker of standby cluster is deployed once only root credentials can write
+	if Mirroring(cluster) {
+		cluster.Status.ObservedGeneration = 0
+	}
+
+	return nil
+}
+
+func (r *Reconciler) disableAuth(ctx context.Context, cluster *apiv1.EtcdCluster) (err error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, 30*time.Second, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	_, err = ecl.AuthDisable(ctx)
+	if err != nil {
+		r.authCondition(cluster, corev1.ConditionTrue, "Disabling", fmt.Sprintf("disable auth: %v", rpctypes.ErrorDesc(err)))
+		return nil
+	}
+
+	// members and root credentials are reissued with default common names
+	r.authCondition(cluster, corev1.ConditionFalse, "Disabled", "authentication is disabled")
+	cluster.Status.ObservedGeneration = 0
+	r.recorder.Event(cluster, corev1.EventTypeNormal, "AuthDisabled", "Disabled etcd authentication")
+
+	return nil
+}
+
+// rootAuthenticated returns true once root credentials are issued for root user
+func (r *Reconciler) rootAuthenticated(ctx context.Context, cluster *apiv1.EtcdCluster) (bool, error) {
+	secret := &corev1.Secret{}
+	err := r.kcl.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Status.SecretName}, secret)
+	if err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	cert, err := parseCertificate(secret.Data[etcd.DefaultCertFile])
+	if err != nil {
+		return false, nil
+	}
+
+	return cert.Subject.CommonName == RootUser, nil
+}
+
+func (r *Reconciler) authCondition(cluster *apiv1.EtcdCluster, status corev1.ConditionStatus, reason, message string) {
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterAuth,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// connect returns etcd client of the cluster authenticated with root credentials, replaced by tests
+var connect = func(ctx context.Context, tlsCache *etcd.TLSCache, cluster *apiv1.EtcdCluster) (*etcdv3.Client, error) {
+	key := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Status.SecretName,
+	}
+	tlsConfig, err := tlsCache.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("tls config: %v", err)
+	}
+
+	ecl, err := etcd.Connect(ctx, tlsConfig, cluster.Status.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("connect to cluster %v: %w", key, err)
+	}
+
+	return ecl, nil
+}
+
+// authOwner returns the oldest of objects managing the same etcd user or role, ties are broken by name
+func authOwner[T client.Object](objects []T) T {
+	return slices.MinFunc(objects, func(l, r T) int {
+		return cmp.Or(
+			l.GetCreationTimestamp().Compare(r.GetCreationTimestamp().Time),
+			strings.Compare(l.GetName(), r.GetName()),
+		)
+	})
+}
+
+// addAuthFinalizer keeps EtcdUser or EtcdRole until it is deleted from etcd
+func addAuthFinalizer(ctx context.Context, kcl client.Client, obj client.Object) error {
+	if controllerutil.ContainsFinalizer(obj, apiv1.AuthFinalizer) {
+		return nil
+	}
+
+	base := obj.DeepCopyObject().(client.Object)
+	controllerutil.AddFinalizer(obj, apiv1.AuthFinalizer)
+
+	err := kcl.Patch(ctx, obj, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	if err != nil {
+		return fmt.Errorf("patch finalizer: %w", err)
+	}
+
+	return nil
+}
+
+func removeAuthFinalizer(ctx context.Context, kcl client.Client, obj client.Object) error {
+	base := obj.DeepCopyObject().(client.Object)
+	controllerutil.RemoveFinalizer(obj, apiv1.AuthFinalizer)
+
+	err := kcl.Patch(ctx, obj, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	if err != nil {
+		return fmt.Errorf("patch finalizer: %w", err)
+	}
+
+	return nil
+}
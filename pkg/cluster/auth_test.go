@@ -0,0 +1,839 @@
+package cluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+// fakeEtcd serves etcd requests of reconcilers from memory and records them,
+// requests which are not implemented panic on nil embedded interfaces
+type fakeEtcd struct {
+	etcdv3.Cluster
+	etcdv3.KV
+	etcdv3.Maintenance
+	etcdv3.Auth
+
+	requests []string
+	errors   map[string]error
+	users    map[string][]string
+	roles    map[string][]*authpb.Permission
+	revision int64
+	alarms   []*etcdserverpb.AlarmMember
+}
+
+// fakeConnect replaces connect with clients served by fake until the test is finished
+func fakeConnect(t *testing.T, fake *fakeEtcd) {
+	t.Helper()
+
+	orig := connect
+	t.Cleanup(func() {
+		connect = orig
+	})
+
+	// client does not connect until it is used by requests which are not served by fake
+	connect = func(context.Context, *etcd.TLSCache, *apiv1.EtcdCluster) (*etcdv3.Client, error) {
+		ecl, err := etcdv3.New(etcdv3.Config{
+			Endpoints: []string{"127.0.0.1:2379"},
+			Logger:    zap.NewNop(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ecl.Cluster, ecl.KV, ecl.Maintenance, ecl.Auth = fake, fake, fake, fake
+		return ecl, nil
+	}
+}
+
+func (f *fakeEtcd) request(format string, args ...any) error {
+	request := fmt.Sprintf(format, args...)
+	f.requests = append(f.requests, request)
+	return f.errors[request]
+}
+
+func (f *fakeEtcd) Status(_ context.Context, endpoint string) (*etcdv3.StatusResponse, error) {
+	err := f.request("Status %s", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &etcdv3.StatusResponse{Header: &etcdserverpb.ResponseHeader{Revision: f.revision}}, nil
+}
+
+func (f *fakeEtcd) Compact(_ context.Context, rev int64, _ ...etcdv3.CompactOption) (*etcdv3.CompactResponse, error) {
+	return &etcdv3.CompactResponse{}, f.request("Compact %d", rev)
+}
+
+func (f *fakeEtcd) Defragment(_ context.Context, endpoint string) (*etcdv3.DefragmentResponse, error) {
+	return &etcdv3.DefragmentResponse{}, f.request("Defragment %s", endpoint)
+}
+
+func (f *fakeEtcd) AlarmList(context.Context) (*etcdv3.AlarmResponse, error) {
+	err := f.request("AlarmList")
+	if err != nil {
+		return nil, err
+	}
+
+	return &etcdv3.AlarmResponse{Alarms: slices.Clone(f.alarms)}, nil
+}
+
+func (f *fakeEtcd) AlarmDisarm(_ context.Context, m *etcdv3.AlarmMember) (*etcdv3.AlarmResponse, error) {
+	err := f.request("AlarmDisarm %s %s", apiv1.FormatMemberID(m.MemberID), m.Alarm)
+	if err != nil {
+		return nil, err
+	}
+
+	f.alarms = slices.DeleteFunc(f.alarms, func(alarm *etcdserverpb.AlarmMember) bool {
+		return alarm.MemberID == m.MemberID && alarm.Alarm == m.Alarm
+	})
+	return &etcdv3.AlarmResponse{}, nil
+}
+
+func (f *fakeEtcd) AuthEnable(context.Context) (*etcdv3.AuthEnableResponse, error) {
+	return &etcdv3.AuthEnableResponse{}, f.request("AuthEnable")
+}
+
+func (f *fakeEtcd) AuthDisable(context.Context) (*etcdv3.AuthDisableResponse, error) {
+	return &etcdv3.AuthDisableResponse{}, f.request("AuthDisable")
+}
+
+func (f *fakeEtcd) UserAddWithOptions(_ context.Context, name, _ string, _ *etcdv3.UserAddOptions) (*etcdv3.AuthUserAddResponse, error) {
+	err := f.request("UserAdd %s", name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.users[name]; ok {
+		return nil, rpctypes.ErrUserAlreadyExist
+	}
+
+	if f.users == nil {
+		f.users = map[string][]string{}
+	}
+	f.users[name] = nil
+	return &etcdv3.AuthUserAddResponse{}, nil
+}
+
+func (f *fakeEtcd) UserGet(_ context.Context, name string) (*etcdv3.AuthUserGetResponse, error) {
+	err := f.request("UserGet %s", name)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, ok := f.users[name]
+	if !ok {
+		return nil, rpctypes.ErrUserNotFound
+	}
+	return &etcdv3.AuthUserGetResponse{Roles: roles}, nil
+}
+
+func (f *fakeEtcd) UserDelete(_ context.Context, name string) (*etcdv3.AuthUserDeleteResponse, error) {
+	err := f.request("UserDelete %s", name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.users[name]; !ok {
+		return nil, rpctypes.ErrUserNotFound
+	}
+
+	delete(f.users, name)
+	return &etcdv3.AuthUserDeleteResponse{}, nil
+}
+
+func (f *fakeEtcd) UserGrantRole(_ context.Context, name, role string) (*etcdv3.AuthUserGrantRoleResponse, error) {
+	err := f.request("UserGrantRole %s %s", name, role)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.roles[role]; !ok {
+		return nil, rpctypes.ErrRoleNotFound
+	}
+
+	f.users[name] = append(f.users[name], role)
+	return &etcdv3.AuthUserGrantRoleResponse{}, nil
+}
+
+func (f *fakeEtcd) UserRevokeRole(_ context.Context, name, role string) (*etcdv3.AuthUserRevokeRoleResponse, error) {
+	err := f.request("UserRevokeRole %s %s", name, role)
+	if err != nil {
+		return nil, err
+	}
+
+	f.users[name] = slices.DeleteFunc(f.users[name], func(r string) bool {
+		return r == role
+	})
+	return &etcdv3.AuthUserRevokeRoleResponse{}, nil
+}
+
+func (f *fakeEtcd) RoleAdd(_ context.Context, name string) (*etcdv3.AuthRoleAddResponse, error) {
+	err := f.request("RoleAdd %s", name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.roles[name]; ok {
+		return nil, rpctypes.ErrRoleAlreadyExist
+	}
+
+	if f.roles == nil {
+		f.roles = map[string][]*authpb.Permission{}
+	}
+	f.roles[name] = nil
+	return &etcdv3.AuthRoleAddResponse{}, nil
+}
+
+func (f *fakeEtcd) RoleGet(_ context.Context, name string) (*etcdv3.AuthRoleGetResponse, error) {
+	err := f.request("RoleGet %s", name)
+	if err != nil {
+		return nil, err
+	}
+
+	perms, ok := f.roles[name]
+	if !ok {
+		return nil, rpctypes.ErrRoleNotFound
+	}
+	return &etcdv3.AuthRoleGetResponse{Perm: perms}, nil
+}
+
+func (f *fakeEtcd) RoleDelete(_ context.Context, name string) (*etcdv3.AuthRoleDeleteResponse, error) {
+	err := f.request("RoleDelete %s", name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.roles[name]; !ok {
+		return nil, rpctypes.ErrRoleNotFound
+	}
+
+	delete(f.roles, name)
+	return &etcdv3.AuthRoleDeleteResponse{}, nil
+}
+
+func (f *fakeEtcd) RoleGrantPermission(_ context.Context, name, key, rangeEnd string, permType etcdv3.PermissionType) (*etcdv3.AuthRoleGrantPermissionResponse, error) {
+	perm := &authpb.Permission{PermType: authpb.Permission_Type(permType), Key: []byte(key), RangeEnd: []byte(rangeEnd)}
+	err := f.request("RoleGrantPermission %s %s", name, FormatPermission(perm))
+	if err != nil {
+		return nil, err
+	}
+
+	// granted permission replaces the type of permission of the same key range
+	f.roles[name] = append(slices.DeleteFunc(f.roles[name], func(p *authpb.Permission) bool {
+		return sameRange(p, perm)
+	}), perm)
+	return &etcdv3.AuthRoleGrantPermissionResponse{}, nil
+}
+
+func (f *fakeEtcd) RoleRevokePermission(_ context.Context, name, key, rangeEnd string) (*etcdv3.AuthRoleRevokePermissionResponse, error) {
+	err := f.request("RoleRevokePermission %s %s", name, FormatPermission(&authpb.Permission{Key: []byte(key), RangeEnd: []byte(rangeEnd)}))
+	if err != nil {
+		return nil, err
+	}
+
+	f.roles[name] = slices.DeleteFunc(f.roles[name], func(perm *authpb.Permission) bool {
+		return string(perm.Key) == key && string(perm.RangeEnd) == rangeEnd
+	})
+	return &etcdv3.AuthRoleRevokePermissionResponse{}, nil
+}
+
+// createTestCertificate returns PEM encoded self-signed certificate with given common name
+func createTestCertificate(t testing.TB, commonName string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("generate key:", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal("create certificate:", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// createTestDeployment returns member deployment rolled out with given sidecar arguments
+func createTestDeployment(name string, cluster *apiv1.EtcdCluster, args []string) *appsv1.Deployment {
+	replicas := cluster.Spec.Replicas
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  cluster.Namespace,
+			Generation: 1,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "sidecar", Args: args},
+					},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           replicas,
+			UpdatedReplicas:    replicas,
+			AvailableReplicas:  replicas,
+		},
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []apiv1.Permission
+		expected    []string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "key",
+			permissions: []apiv1.Permission{
+				{Type: apiv1.PermissionRead, Key: "/config"},
+			},
+			expected: []string{`READ "/config"`},
+		},
+		{
+			name: "prefix",
+			permissions: []apiv1.Permission{
+				{Type: apiv1.PermissionReadWrite, Key: "/app/", Prefix: true},
+			},
+			expected: []string{`READWRITE prefix "/app/"`},
+		},
+		{
+			name: "range",
+			permissions: []apiv1.Permission{
+				{Type: apiv1.PermissionWrite, Key: "/b", RangeEnd: "/d"},
+				{Type: apiv1.PermissionRead, Key: "/a", RangeEnd: `\0`},
+			},
+			expected: []string{`READ ["/a", end)`, `WRITE ["/b", "/d")`},
+		},
+		{
+			name: "merged",
+			permissions: []apiv1.Permission{
+				{Type: apiv1.PermissionRead, Key: "/app/", Prefix: true},
+				{Type: apiv1.PermissionWrite, Key: "/app/", Prefix: true},
+				{Type: apiv1.PermissionRead, Key: "/app/", Prefix: true},
+			},
+			expected: []string{`READWRITE prefix "/app/"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role := &apiv1.EtcdRole{Spec: apiv1.EtcdRoleSpec{Permissions: tt.permissions}}
+
+			var perms []string
+			for _, perm := range RolePermissions(role) {
+				perms = append(perms, FormatPermission(perm))
+			}
+			if !slices.Equal(perms, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, perms)
+			}
+		})
+	}
+}
+
+func TestDiffPermissions(t *testing.T) {
+	perm := func(tpe authpb.Permission_Type, key string) *authpb.Permission {
+		return &authpb.Permission{PermType: tpe, Key: []byte(key)}
+	}
+	format := func(perms []*authpb.Permission) []string {
+		var formatted []string
+		for _, perm := range perms {
+			formatted = append(formatted, FormatPermission(perm))
+		}
+		return formatted
+	}
+
+	tests := []struct {
+		name    string
+		current []*authpb.Permission
+		desired []*authpb.Permission
+		grant   []string
+		revoke  []string
+	}{
+		{
+			name:    "equal",
+			current: []*authpb.Permission{perm(authpb.READ, "/a")},
+			desired: []*authpb.Permission{perm(authpb.READ, "/a")},
+		},
+		{
+			name:    "grant",
+			desired: []*authpb.Permission{perm(authpb.READ, "/a")},
+			grant:   []string{`READ "/a"`},
+		},
+		{
+			name:    "revoke",
+			current: []*authpb.Permission{perm(authpb.WRITE, "/b"), perm(authpb.READ, "/a")},
+			desired: []*authpb.Permission{perm(authpb.READ, "/a")},
+			revoke:  []string{`WRITE "/b"`},
+		},
+		{
+			name:    "type changed",
+			current: []*authpb.Permission{perm(authpb.READ, "/a")},
+			desired: []*authpb.Permission{perm(authpb.READWRITE, "/a")},
+			grant:   []string{`READWRITE "/a"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant, revoke := DiffPermissions(tt.current, tt.desired)
+			if !slices.Equal(format(grant), tt.grant) {
+				t.Errorf("expected grant %v, got %v", tt.grant, format(grant))
+			}
+			if !slices.Equal(format(revoke), tt.revoke) {
+				t.Errorf("expected revoke %v, got %v", tt.revoke, format(revoke))
+			}
+		})
+	}
+}
+
+func TestDiffRoles(t *testing.T) {
+	user := &apiv1.EtcdUser{Spec: apiv1.EtcdUserSpec{Roles: []string{"writer", "reader", "writer"}}}
+	roles := UserRoles(user)
+	if !slices.Equal(roles, []string{"reader", "writer"}) {
+		t.Errorf("expected roles %v, got %v", []string{"reader", "writer"}, roles)
+	}
+
+	grant, revoke := DiffRoles([]string{"admin", "reader"}, roles)
+	if !slices.Equal(grant, []string{"writer"}) {
+		t.Errorf("expected grant %v, got %v", []string{"writer"}, grant)
+	}
+	if !slices.Equal(revoke, []string{"admin"}) {
+		t.Errorf("expected revoke %v, got %v", []string{"admin"}, revoke)
+	}
+}
+
+func TestReconcileAuth(t *testing.T) {
+	rootArgs := []string{"--common-name=" + RootUser}
+	enabling := []string{"RoleAdd root", "UserAdd root", "UserGrantRole root root", "AuthEnable"}
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		condition  *apiv1.ClusterCondition
+		available  int32
+		args       []string
+		commonName string
+		errors     map[string]error
+		requests   []string
+		status     corev1.ConditionStatus
+		reason     string
+	}{
+		{
+			name:       "members not restarted",
+			enabled:    true,
+			available:  3,
+			commonName: RootUser,
+			status:     corev1.ConditionFalse,
+			reason:     "Enabling",
+		},
+		{
+			name:       "member unavailable",
+			enabled:    true,
+			available:  2,
+			args:       rootArgs,
+			commonName: RootUser,
+			status:     corev1.ConditionFalse,
+			reason:     "Enabling",
+		},
+		{
+			name:       "root credentials not reissued",
+			enabled:    true,
+			available:  3,
+			args:       rootArgs,
+			commonName: "test-cluster-root",
+			status:     corev1.ConditionFalse,
+			reason:     "Enabling",
+		},
+		{
+			name:       "enable failed",
+			enabled:    true,
+			available:  3,
+			args:       rootArgs,
+			commonName: RootUser,
+			errors:     map[string]error{"AuthEnable": rpctypes.ErrRootUserNotExist},
+			requests:   enabling,
+			status:     corev1.ConditionFalse,
+			reason:     "Enabling",
+		},
+		{
+			name:       "enabled",
+			enabled:    true,
+			available:  3,
+			args:       rootArgs,
+			commonName: RootUser,
+			requests:   enabling,
+			status:     corev1.ConditionTrue,
+			reason:     "Enabled",
+		},
+		{
+			name:      "disable failed",
+			condition: &apiv1.ClusterCondition{Type: apiv1.ClusterAuth, Status: corev1.ConditionTrue, Reason: "Enabled"},
+			available: 3,
+			errors:    map[string]error{"AuthDisable": rpctypes.ErrPermissionDenied},
+			requests:  []string{"AuthDisable"},
+			status:    corev1.ConditionTrue,
+			reason:    "Disabling",
+		},
+		{
+			name:      "disabled",
+			condition: &apiv1.ClusterCondition{Type: apiv1.ClusterAuth, Status: corev1.ConditionTrue, Reason: "Disabling"},
+			available: 3,
+			requests:  []string{"AuthDisable"},
+			status:    corev1.ConditionFalse,
+			reason:    "Disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Status.ObservedGeneration = 1
+			cluster.Status.AvailableReplicas = tt.available
+			if tt.enabled {
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			}
+			if tt.condition != nil {
+				conditions.Upsert(&cluster.Status.Conditions, *tt.condition)
+			}
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: cluster.Namespace,
+					Name:      cluster.Status.SecretName,
+				},
+				Data: map[string][]byte{
+					etcd.DefaultCertFile: createTestCertificate(t, tt.commonName),
+				},
+			}
+
+			fake := &fakeEtcd{errors: tt.errors}
+			fakeConnect(t, fake)
+
+			r := &Reconciler{
+				kcl:      createTestClient(t, secret, createTestDeployment(cluster.Name, cluster, tt.args)),
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			err := r.ReconcileAuth(t.Context(), cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterAuth)
+			switch {
+			case !slices.Equal(fake.requests, tt.requests):
+				t.Errorf("expected requests %v, got %v", tt.requests, fake.requests)
+			case cond.Status != tt.status || cond.Reason != tt.reason:
+				t.Errorf("expected condition %s/%s, got %s/%s: %s", tt.status, tt.reason, cond.Status, cond.Reason, cond.Message)
+			case tt.reason == "Disabled" && cluster.Status.ObservedGeneration != 0:
+				t.Error("expected resources to be reapplied once auth is disabled")
+			}
+		})
+	}
+}
+
+func TestAuthOwner(t *testing.T) {
+	created := metav1.NewTime(time.Now().Truncate(time.Second))
+	user := func(name string, age time.Duration) *apiv1.EtcdUser {
+		return &apiv1.EtcdUser{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		users    []*apiv1.EtcdUser
+		expected string
+	}{
+		{
+			name:     "oldest",
+			users:    []*apiv1.EtcdUser{user("a", time.Minute), user("b", time.Hour)},
+			expected: "b",
+		},
+		{
+			name:     "same age",
+			users:    []*apiv1.EtcdUser{user("b", time.Hour), user("a", time.Hour)},
+			expected: "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := authOwner(tt.users)
+			if owner.Name != tt.expected {
+				t.Errorf("expected owner %q, got %q", tt.expected, owner.Name)
+			}
+		})
+	}
+}
+
+func TestReconcileUserConflict(t *testing.T) {
+	cluster := createTestCluster()
+	created := metav1.NewTime(time.Now().Truncate(time.Second))
+
+	user := func(name string, created metav1.Time) *apiv1.EtcdUser {
+		return &apiv1.EtcdUser{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         cluster.Namespace,
+				Name:              name,
+				UID:               types.UID(name),
+				Generation:        1,
+				CreationTimestamp: created,
+			},
+			Spec: apiv1.EtcdUserSpec{
+				ClusterName: cluster.Name,
+				Username:    "app",
+				Roles:       []string{"reader"},
+			},
+		}
+	}
+	owner := user("app", metav1.NewTime(created.Add(-time.Hour)))
+	duplicate := user("app-copy", created)
+
+	fake := &fakeEtcd{roles: map[string][]*authpb.Permission{"reader": nil}}
+	fakeConnect(t, fake)
+
+	r := &UserReconciler{
+		kcl:      createTestClient(t, cluster, owner, duplicate),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	err := r.ReconcileUser(t.Context(), duplicate)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case duplicate.Status.Phase != apiv1.AuthPending || duplicate.Status.Reason != "Conflict":
+		t.Errorf("expected pending conflict, got %s/%s", duplicate.Status.Phase, duplicate.Status.Reason)
+	case len(fake.requests) != 0:
+		t.Errorf("expected no requests by conflicting user, got %v", fake.requests)
+	}
+
+	err = r.ReconcileUser(t.Context(), owner)
+	expected := []string{"UserGet app", "UserAdd app", "UserGrantRole app reader"}
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case owner.Status.Phase != apiv1.AuthReady:
+		t.Errorf("expected owner to be ready, got %s/%s: %s", owner.Status.Phase, owner.Status.Reason, owner.Status.Message)
+	case !slices.Equal(fake.requests, expected):
+		t.Errorf("expected requests %v, got %v", expected, fake.requests)
+	}
+}
+
+func TestReconcileStandbyReadOnly(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source"}
+	cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+
+	meta := metav1.ObjectMeta{Namespace: cluster.Namespace, Name: "app", Generation: 1}
+	role := &apiv1.EtcdRole{
+		ObjectMeta: meta,
+		Spec: apiv1.EtcdRoleSpec{
+			ClusterName: cluster.Name,
+			Permissions: []apiv1.Permission{
+				{Type: apiv1.PermissionReadWrite, Key: "/app/", Prefix: true},
+				{Type: apiv1.PermissionWrite, Key: "/lock"},
+			},
+		},
+	}
+	user := &apiv1.EtcdUser{
+		ObjectMeta: meta,
+		Spec: apiv1.EtcdUserSpec{
+			ClusterName: cluster.Name,
+			Roles:       []string{"app", RootUser},
+		},
+	}
+
+	fake := &fakeEtcd{roles: map[string][]*authpb.Permission{RootUser: nil}}
+	fakeConnect(t, fake)
+
+	kcl := createTestClient(t, cluster, role, user)
+	roles := &RoleReconciler{kcl: kcl, recorder: record.NewFakeRecorder(10)}
+	users := &UserReconciler{kcl: kcl, recorder: record.NewFakeRecorder(10)}
+
+	steps := []struct {
+		name     string
+		promote  bool
+		readOnly bool
+		requests []string
+	}{
+		{
+			name:     "mirroring",
+			readOnly: true,
+			requests: []string{
+				"RoleGet app", "RoleAdd app", `RoleGrantPermission app READ prefix "/app/"`,
+				"UserGet app", "UserAdd app", "UserGrantRole app app",
+			},
+		},
+		{
+			name:     "unchanged",
+			readOnly: true,
+			requests: []string{"RoleGet app", "UserGet app"},
+		},
+		{
+			name:    "promoted",
+			promote: true,
+			requests: []string{
+				"RoleGet app", `RoleGrantPermission app READWRITE prefix "/app/"`, `RoleGrantPermission app WRITE "/lock"`,
+				"UserGet app", "UserGrantRole app root",
+			},
+		},
+	}
+
+	for _, step := range steps {
+		if step.promote {
+			cluster.Spec.Mirror.Promote = true
+			err := kcl.Update(t.Context(), cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		fake.requests = nil
+
+		err := roles.ReconcileRole(t.Context(), role)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		err = users.ReconcileUser(t.Context(), user)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		switch {
+		case !slices.Equal(fake.requests, step.requests):
+			t.Errorf("%s: expected requests %v, got %v", step.name, step.requests, fake.requests)
+		case role.Status.ReadOnly != step.readOnly:
+			t.Errorf("%s: expected read-only %t, got %t", step.name, step.readOnly, role.Status.ReadOnly)
+		case role.Status.Phase != apiv1.AuthReady || user.Status.Phase != apiv1.AuthReady:
+			t.Errorf("%s: expected ready, got role %s: %s and user %s: %s", step.name, role.Status.Phase, role.Status.Message, user.Status.Phase, user.Status.Message)
+		case role.Status.Drift != "" || user.Status.Drift != "":
+			t.Errorf("%s: expected no drift, got role %q and user %q", step.name, role.Status.Drift, user.Status.Drift)
+		}
+	}
+}
+
+func TestReconcileAuthDeletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		cluster  bool
+		applied  bool
+		requests []string
+	}{
+		{
+			name:     "deleted",
+			cluster:  true,
+			applied:  true,
+			requests: []string{"UserDelete app", "RoleDelete app"},
+		},
+		{
+			name:    "never applied",
+			cluster: true,
+		},
+		{
+			name:    "cluster not found",
+			applied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			meta := metav1.ObjectMeta{
+				Namespace:         cluster.Namespace,
+				Name:              "app",
+				DeletionTimestamp: ptr.To(metav1.Now()),
+				Finalizers:        []string{apiv1.AuthFinalizer},
+			}
+			user := &apiv1.EtcdUser{ObjectMeta: meta, Spec: apiv1.EtcdUserSpec{ClusterName: cluster.Name}}
+			role := &apiv1.EtcdRole{ObjectMeta: meta, Spec: apiv1.EtcdRoleSpec{ClusterName: cluster.Name}}
+
+			objects := []client.Object{user, role}
+			if tt.cluster {
+				objects = append(objects, cluster)
+			}
+			kcl := createTestClient(t, objects...)
+
+			fake := &fakeEtcd{
+				users: map[string][]string{"app": {"app"}},
+				roles: map[string][]*authpb.Permission{"app": nil},
+			}
+			fakeConnect(t, fake)
+
+			// deleted objects are read back for resource version
+			for _, obj := range []client.Object{user, role} {
+				err := kcl.Get(t.Context(), client.ObjectKeyFromObject(obj), obj)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.applied {
+				user.Status.ObservedGeneration = 1
+				role.Status.ObservedGeneration = 1
+			}
+
+			userReconciler := &UserReconciler{kcl: kcl, recorder: record.NewFakeRecorder(10)}
+			err := userReconciler.ReconcileDeletion(t.Context(), user)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			roleReconciler := &RoleReconciler{kcl: kcl, recorder: record.NewFakeRecorder(10)}
+			err = roleReconciler.ReconcileDeletion(t.Context(), role)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(fake.requests, tt.requests) {
+				t.Errorf("expected requests %v, got %v", tt.requests, fake.requests)
+			}
+			for _, obj := range []client.Object{&apiv1.EtcdUser{}, &apiv1.EtcdRole{}} {
+				err = kcl.Get(t.Context(), client.ObjectKey{Namespace: cluster.Namespace, Name: "app"}, obj)
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected %T to be deleted once finalizer is removed, got %v", obj, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/backup"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+// ClusterNameField indexes EtcdBackup and EtcdRestore by spec.clusterName
+const ClusterNameField = "spec.clusterName"
+
+type BackupReconciler struct {
+	kcl      client.Client
+	recorder record.EventRecorder
+	config   Config
+}
+
+// SetupBackupWithManager creates EtcdBackup controller
+func SetupBackupWithManager(mgr manager.Manager, config Config) error {
+	reconciler := &BackupReconciler{
+		kcl:      mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("etcdbackup"),
+		config:   config,
+	}
+
+	return builder.ControllerManagedBy(mgr).
+		For(&apiv1.EtcdBackup{}).
+		Owns(&batchv1.Job{}).
+		Complete(reconcile.AsReconciler(mgr.GetClient(), reconciler))
+}
+
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdbackups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdbackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;list;patch;update;watch;delete
+
+func (r *BackupReconciler) Reconcile(ctx context.Context, etcdBackup *apiv1.EtcdBackup) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(3).Info("Reconciling backup", "name", etcdBackup.Name)
+
+	// bail if backup is finished
+	if etcdBackup.Status.Phase == apiv1.BackupSucceeded || etcdBackup.Status.Phase == apiv1.BackupFailed {
+		return reconcile.Result{}, nil
+	}
+
+	base := etcdBackup.DeepCopy()
+
+	result, err := r.ReconcileJob(ctx, etcdBackup)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile job: %w", err)
+	}
+
+	// bail if status did not change
+	if reflect.DeepEqual(base.Status, etcdBackup.Status) {
+		return result, nil
+	}
+
+	patch := client.MergeFrom(base)
+	err = r.kcl.Status().Patch(ctx, etcdBackup, patch)
+	switch {
+	case client.IgnoreNotFound(err) != nil:
+		return reconcile.Result{}, fmt.Errorf("patch backup status: %v", err)
+	case err == nil:
+		logger.V(3).Info("patched backup status")
+	}
+
+	return result, nil
+}
+
+func (r *BackupReconciler) ReconcileJob(ctx context.Context, etcdBackup *apiv1.EtcdBackup) (reconcile.Result, error) {
+	if etcdBackup.Status.Phase == "" {
+		etcdBackup.Status.Phase = apiv1.BackupPending
+	}
+
+	if len(r.config.BackupEnv) == 0 {
+		r.fail(etcdBackup, "BackupNotConfigured", "backup is not configured")
+		return reconcile.Result{}, nil
+	}
+
+	key := client.ObjectKey{
+		Namespace: etcdBackup.Namespace,
+		Name:      etcdBackup.Spec.ClusterName,
+	}
+	cluster := &apiv1.EtcdCluster{}
+	err := r.kcl.Get(ctx, key, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+		r.fail(etcdBackup, "ClusterNotFound", fmt.Sprintf("cluster %q not found", key.Name))
+		return reconcile.Result{}, nil
+	case err != nil:
+		return reconcile.Result{}, fmt.Errorf("get cluster: %w", err)
+	}
+
+	// object key is determined before job is created
+	if etcdBackup.Status.Key == "" {
+		etcdBackup.Status.Key = etcdBackup.Spec.Key
+		if etcdBackup.Status.Key == "" {
+			etcdBackup.Status.Key = path.Join(cluster.Namespace, cluster.Name, etcdBackup.CreationTimestamp.UTC().Format(backup.DateFormat))
+		}
+		return reconcile.Result{}, nil
+	}
+
+	job := &batchv1.Job{}
+	err = r.kcl.Get(ctx, client.ObjectKeyFromObject(etcdBackup), job)
+	switch {
+	// wait for cluster to be running before job is created
+	case apierrors.IsNotFound(err) && cluster.Status.Phase != apiv1.ClusterRunning:
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	case apierrors.IsNotFound(err):
+		b := resources.NewBuilder(etcdBackup).
+			Label("app.kubernetes.io/managed-by", "etcd-operator").
+			Label(apiv1.ClusterLabel, apiv1.ClusterLabelValue(key))
+
+		BackupJob(b, cluster, r.config, etcdBackup.Status.Key)
+
+		err = b.Apply(ctx, r.kcl)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("apply backup job: %w", err)
+		}
+
+		r.recorder.Eventf(etcdBackup, corev1.EventTypeNormal, "Created", "Created backup job for %q", etcdBackup.Status.Key)
+
+		return reconcile.Result{}, nil
+	case err != nil:
+		return reconcile.Result{}, fmt.Errorf("get backup job: %w", err)
+	}
+
+	if job.Status.StartTime != nil {
+		etcdBackup.Status.Phase = apiv1.BackupRunning
+		etcdBackup.Status.StartTime = job.Status.StartTime
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch cond.Type {
+		case batchv1.JobComplete:
+			message, err := r.TerminationMessage(ctx, job, corev1.PodSucceeded)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			result := &backup.Result{}
+			err = json.Unmarshal([]byte(message), result)
+			if err != nil {
+				r.fail(etcdBackup, "InvalidResult", fmt.Sprintf("backup result: %v", err))
+				return reconcile.Result{}, nil
+			}
+
+			etcdBackup.Status.Phase = apiv1.BackupSucceeded
+			etcdBackup.Status.Key = result.Key
+			etcdBackup.Status.Size = resource.NewQuantity(result.Size, resource.BinarySI)
+			etcdBackup.Status.Revision = result.Revision
+			etcdBackup.Status.CompletionTime = job.Status.CompletionTime
+			r.recorder.Eventf(etcdBackup, corev1.EventTypeNormal, string(apiv1.BackupSucceeded), "Uploaded backup %q", result.Key)
+		case batchv1.JobFailed:
+			message, err := r.TerminationMessage(ctx, job, corev1.PodFailed)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if message == "" {
+				message = cond.Message
+			}
+
+			r.fail(etcdBackup, cond.Reason, message)
+			etcdBackup.Status.CompletionTime = ptr.To(cond.LastTransitionTime)
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// TerminationMessage returns termination message of the latest job pod in provided phase
+func (r *BackupReconciler) TerminationMessage(ctx context.Context, job *batchv1.Job, phase corev1.PodPhase) (string, error) {
+	pods := &corev1.PodList{}
+	err := r.kcl.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{
+		batchv1.JobNameLabel: job.Name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("list job pods: %w", err)
+	}
+
+	pods.Items = slices.DeleteFunc(pods.Items, func(pod corev1.Pod) bool {
+		return pod.Status.Phase != phase
+	})
+	slices.SortFunc(pods.Items, func(l, r corev1.Pod) int {
+		return r.CreationTimestamp.Compare(l.CreationTimestamp.Time)
+	})
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+				return status.State.Terminated.Message, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func (r *BackupReconciler) fail(etcdBackup *apiv1.EtcdBackup, reason, message string) {
+	r.recorder.Event(etcdBackup, corev1.EventTypeWarning, reason, message)
+	etcdBackup.Status.Phase = apiv1.BackupFailed
+	etcdBackup.Status.Reason = reason
+	etcdBackup.Status.Message = message
+}
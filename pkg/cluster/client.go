@@ -0,0 +1,385 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+// DefaultClientMountPath is the directory where applications mount EtcdClient secret
+const DefaultClientMountPath = "/etc/etcd/client"
+
+type ClientReconciler struct {
+	kcl      client.Client
+	recorder record.EventRecorder
+}
+
+// SetupClientWithManager creates EtcdClient controller
+func SetupClientWithManager(mgr manager.Manager) error {
+	reconciler := &ClientReconciler{
+		kcl:      mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("etcdclient"),
+	}
+
+	// index clients by cluster name
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1.EtcdClient{}, ClusterNameField, func(obj client.Object) []string {
+		return []string{obj.(*apiv1.EtcdClient).Spec.ClusterName}
+	})
+	if err != nil {
+		return fmt.Errorf("index clients: %w", err)
+	}
+
+	// cluster changes reissue client certificates, e.g. with the signing CA during CA rotation
+	clusterHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		clients := &apiv1.EtcdClientList{}
+		err := reconciler.kcl.List(ctx, clients, client.InNamespace(obj.GetNamespace()), client.MatchingFields{
+			ClusterNameField: obj.GetName(),
+		})
+		if err != nil {
+			log.FromContext(ctx).Error(err, "list clients")
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(clients.Items))
+		for _, etcdClient := range clients.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&etcdClient),
+			})
+		}
+
+		return requests
+	})
+
+	return builder.ControllerManagedBy(mgr).
+		For(&apiv1.EtcdClient{}).
+		Owns(&cmv1.Certificate{}).
+		Watches(&apiv1.EtcdCluster{}, clusterHandler).
+		Complete(reconcile.AsReconciler(mgr.GetClient(), reconciler))
+}
+
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdclients,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdclients/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates/status,verbs=patch
+
+func (r *ClientReconciler) Reconcile(ctx context.Context, etcdClient *apiv1.EtcdClient) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(3).Info("Reconciling client", "name", etcdClient.Name)
+
+	// certificate and secret are garbage collected
+	if !etcdClient.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	base := etcdClient.DeepCopy()
+
+	err := r.ReconcileCredentials(ctx, etcdClient)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile credentials: %w", err)
+	}
+
+	// bail if status did not change
+	if reflect.DeepEqual(base.Status, etcdClient.Status) {
+		return reconcile.Result{}, nil
+	}
+
+	patch := client.MergeFrom(base)
+	err = r.kcl.Status().Patch(ctx, etcdClient, patch)
+	switch {
+	case client.IgnoreNotFound(err) != nil:
+		return reconcile.Result{}, fmt.Errorf("patch client status: %v", err)
+	case err == nil:
+		logger.V(3).Info("patched client status")
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// ReconcileCredentials issues client certificate with server issuer of the cluster and adds connection details
+// to the secret once the certificate is ready
+func (r *ClientReconciler) ReconcileCredentials(ctx context.Context, etcdClient *apiv1.EtcdClient) error {
+	status := &etcdClient.Status
+	if status.Phase == "" {
+		status.Phase = apiv1.ClientPending
+		// certificate is issued for the first time
+		status.Rotate = etcdClient.Spec.Rotate
+	}
+	status.Username = ClientUsername(etcdClient)
+	status.SecretName = ClientSecretName(etcdClient)
+
+	// root username is never issued, including the fallback to the name of EtcdClient
+	if status.Username == RootUser {
+		r.pending(etcdClient, "InvalidUsername", "root credentials are provided by <cluster>-user-root secret, set spec.username")
+		return nil
+	}
+
+	key := client.ObjectKey{
+		Namespace: etcdClient.Namespace,
+		Name:      etcdClient.Spec.ClusterName,
+	}
+	cluster := &apiv1.EtcdCluster{}
+	err := r.kcl.Get(ctx, key, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+		r.pending(etcdClient, "ClusterNotFound", fmt.Sprintf("cluster %q not found", key.Name))
+		return nil
+	case err != nil:
+		return fmt.Errorf("get cluster: %w", err)
+	}
+
+	status.Endpoint = cluster.Status.Endpoint
+
+	// credentials of other certificates, e.g. root credentials of the cluster, are never replaced
+	conflict, err := r.conflict(ctx, etcdClient)
+	if err != nil {
+		return err
+	}
+	if conflict != "" {
+		r.pending(etcdClient, "Conflict", conflict)
+		return nil
+	}
+
+	b := resources.NewBuilder(etcdClient).
+		Label("app.kubernetes.io/managed-by", "etcd-operator").
+		Label(apiv1.ClusterLabel, apiv1.ClusterLabelValue(key))
+	ClientCredentials(b, cluster, etcdClient)
+
+	err = b.Apply(ctx, r.kcl)
+	if err != nil {
+		return fmt.Errorf("apply client certificate: %w", err)
+	}
+
+	cert := &cmv1.Certificate{}
+	err = r.kcl.Get(ctx, client.ObjectKeyFromObject(etcdClient), cert)
+	if err != nil {
+		return fmt.Errorf("get client certificate: %w", err)
+	}
+
+	status.NotAfter = cert.Status.NotAfter
+	status.RenewalTime = cert.Status.RenewalTime
+
+	if !certificateReady(cert) {
+		r.pending(etcdClient, "Issuing", "waiting for client certificate to be issued")
+		return nil
+	}
+	if status.Endpoint == "" {
+		r.pending(etcdClient, "ClusterPending", fmt.Sprintf("cluster %q has no endpoint yet", key.Name))
+		return nil
+	}
+
+	err = r.applyConnection(ctx, etcdClient)
+	if err != nil {
+		return err
+	}
+
+	if etcdClient.Spec.Rotate != status.Rotate {
+		err = r.rotate(ctx, etcdClient, cert)
+		if err != nil {
+			return err
+		}
+	}
+
+	status.Phase = apiv1.ClientReady
+	status.Reason = ""
+	status.Message = ""
+
+	return nil
+}
+
+// ClientCredentials builds client certificate of EtcdClient, private key is replaced on every renewal
+func ClientCredentials(builder *resources.Builder, cluster *apiv1.EtcdCluster, etcdClient *apiv1.EtcdClient) *cmv1.Certificate {
+	cert := builder.Certificate().
+		CommonName(ClientUsername(etcdClient)).
+		SecretName(ClientSecretName(etcdClient)).
+		IssuerRef(ServerIssuer(cluster)).
+		Usages(cmv1.UsageClientAuth).
+		SecretLabels(map[string]string{
+			apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+		})
+	ClientCertificate(cert, cluster)
+	cert.RotateKey()
+
+	if etcdClient.Spec.Duration != nil {
+		cert.Duration(etcdClient.Spec.Duration.Duration)
+	}
+
+	return cert.Certificate
+}
+
+// ClientConnection returns connection details of EtcdClient secret referring to credentials
+// mounted at the mount path of the client
+func ClientConnection(endpoint string, etcdClient *apiv1.EtcdClient) map[string][]byte {
+	dir := etcdClient.Spec.MountPath
+	if dir == "" {
+		dir = DefaultClientMountPath
+	}
+
+	caFile := path.Join(dir, etcd.DefaultCACertFile)
+	certFile := path.Join(dir, etcd.DefaultCertFile)
+	keyFile := path.Join(dir, etcd.DefaultKeyFile)
+
+	env := strings.Join([]string{
+		"ETCDCTL_API=3",
+		"ETCDCTL_ENDPOINTS=" + endpoint,
+		"ETCDCTL_CACERT=" + caFile,
+		"ETCDCTL_CERT=" + certFile,
+		"ETCDCTL_KEY=" + keyFile,
+	}, "\n") + "\n"
+
+	// go.etcd.io/etcd/client/v3/yaml config
+	config := strings.Join([]string{
+		"endpoints:",
+		"- " + endpoint,
+		"trusted-ca-file: " + caFile,
+		"cert-file: " + certFile,
+		"key-file: " + keyFile,
+	}, "\n") + "\n"
+
+	return map[string][]byte{
+		apiv1.ClientEndpointKey: []byte(endpoint),
+		apiv1.ClientEnvKey:      []byte(env),
+		apiv1.ClientConfigKey:   []byte(config),
+	}
+}
+
+// ClientUsername returns etcd user of EtcdClient
+func ClientUsername(etcdClient *apiv1.EtcdClient) string {
+	if etcdClient.Spec.Username != "" {
+		return etcdClient.Spec.Username
+	}
+
+	return etcdClient.Name
+}
+
+// ClientSecretName returns name of EtcdClient secret
+func ClientSecretName(etcdClient *apiv1.EtcdClient) string {
+	if etcdClient.Spec.SecretName != "" {
+		return etcdClient.Spec.SecretName
+	}
+
+	return etcdClient.Name
+}
+
+// conflict returns reason why certificate or secret of EtcdClient would replace objects it does not own
+func (r *ClientReconciler) conflict(ctx context.Context, etcdClient *apiv1.EtcdClient) (string, error) {
+	cert := &cmv1.Certificate{}
+	err := r.kcl.Get(ctx, client.ObjectKeyFromObject(etcdClient), cert)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return "", fmt.Errorf("get client certificate: %w", err)
+	case !metav1.IsControlledBy(cert, etcdClient):
+		return fmt.Sprintf("certificate %q is not owned by the client", cert.Name), nil
+	}
+
+	secret := &corev1.Secret{}
+	err = r.kcl.Get(ctx, client.ObjectKey{Namespace: etcdClient.Namespace, Name: etcdClient.Status.SecretName}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return "", fmt.Errorf("get client secret: %w", err)
+	case secret.Annotations[cmv1.CertificateNameKey] != etcdClient.Name:
+		return fmt.Sprintf("secret %q is not issued for the client", secret.Name), nil
+	}
+
+	return "", nil
+}
+
+// applyConnection adds connection details next to credentials written by cert-manager,
+// the secret is garbage collected with EtcdClient
+func (r *ClientReconciler) applyConnection(ctx context.Context, etcdClient *apiv1.EtcdClient) error {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: etcdClient.Namespace,
+			Name:      etcdClient.Status.SecretName,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: ClientConnection(etcdClient.Status.Endpoint, etcdClient),
+	}
+
+	err := controllerutil.SetOwnerReference(etcdClient, secret, r.kcl.Scheme())
+	if err != nil {
+		return err
+	}
+
+	err = r.kcl.Patch(ctx, secret, client.Apply, client.FieldOwner("etcd-operator"), client.ForceOwnership)
+	if err != nil {
+		return fmt.Errorf("apply client secret: %w", err)
+	}
+
+	return nil
+}
+
+// rotate triggers reissuance of client certificate the same way as `cmctl renew`
+func (r *ClientReconciler) rotate(ctx context.Context, etcdClient *apiv1.EtcdClient, cert *cmv1.Certificate) error {
+	base := cert.DeepCopy()
+	issuing := cmv1.CertificateCondition{
+		Type:               cmv1.CertificateConditionIssuing,
+		Status:             cmmeta.ConditionTrue,
+		Reason:             "ManuallyTriggered",
+		Message:            "Certificate re-issuance requested by spec.rotate of EtcdClient",
+		LastTransitionTime: ptr.To(metav1.Now()),
+		ObservedGeneration: cert.Generation,
+	}
+	i := slices.IndexFunc(cert.Status.Conditions, func(cond cmv1.CertificateCondition) bool {
+		return cond.Type == cmv1.CertificateConditionIssuing
+	})
+	if i == -1 {
+		cert.Status.Conditions = append(cert.Status.Conditions, issuing)
+	} else {
+		cert.Status.Conditions[i] = issuing
+	}
+
+	err := r.kcl.Status().Patch(ctx, cert, client.MergeFrom(base))
+	if err != nil {
+		return fmt.Errorf("patch client certificate status: %w", err)
+	}
+
+	etcdClient.Status.Rotate = etcdClient.Spec.Rotate
+	r.recorder.Eventf(etcdClient, corev1.EventTypeNormal, "Rotating", "Reissuing client certificate of user %q", etcdClient.Status.Username)
+
+	return nil
+}
+
+func (r *ClientReconciler) pending(etcdClient *apiv1.EtcdClient, reason, message string) {
+	etcdClient.Status.Phase = apiv1.ClientPending
+	etcdClient.Status.Reason = reason
+	etcdClient.Status.Message = message
+}
+
+func certificateReady(cert *cmv1.Certificate) bool {
+	for _, cond := range cert.Status.Conditions {
+		if cond.Type == cmv1.CertificateConditionReady {
+			return cond.Status == cmmeta.ConditionTrue && cond.ObservedGeneration == cert.Generation
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+func TestClientCredentials(t *testing.T) {
+	cluster := createTestCluster()
+	etcdClient := &apiv1.EtcdClient{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: apiv1.EtcdClientSpec{
+			ClusterName: cluster.Name,
+			Username:    "app-user",
+			SecretName:  "app-etcd",
+			Duration:    &metav1.Duration{Duration: 24 * time.Hour},
+		},
+	}
+
+	cert := ClientCredentials(resources.NewBuilder(etcdClient), cluster, etcdClient)
+
+	if cert.Name != "app" {
+		t.Errorf("expected name %q, got %q", "app", cert.Name)
+	}
+	if cert.Spec.CommonName != "app-user" {
+		t.Errorf("expected common name %q, got %q", "app-user", cert.Spec.CommonName)
+	}
+	if cert.Spec.SecretName != "app-etcd" {
+		t.Errorf("expected secret %q, got %q", "app-etcd", cert.Spec.SecretName)
+	}
+	if cert.Spec.IssuerRef != ServerIssuer(cluster) {
+		t.Errorf("expected issuer %v, got %v", ServerIssuer(cluster), cert.Spec.IssuerRef)
+	}
+	if cert.Spec.Duration == nil || cert.Spec.Duration.Duration != 24*time.Hour {
+		t.Errorf("expected duration %s, got %v", 24*time.Hour, cert.Spec.Duration)
+	}
+	if cert.Spec.PrivateKey == nil || cert.Spec.PrivateKey.RotationPolicy != cmv1.RotationPolicyAlways {
+		t.Errorf("expected private key rotation policy %q, got %v", cmv1.RotationPolicyAlways, cert.Spec.PrivateKey)
+	}
+}
+
+func TestReconcileCredentialsRootUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+	}{
+		{
+			name:     "root",
+			username: "root",
+		},
+		{
+			name: "root",
+		},
+	}
+
+	for _, tt := range tests {
+		etcdClient := &apiv1.EtcdClient{
+			ObjectMeta: metav1.ObjectMeta{Name: tt.name, Namespace: "default"},
+			Spec: apiv1.EtcdClientSpec{
+				ClusterName: "test-cluster",
+				Username:    tt.username,
+			},
+		}
+
+		kcl := createTestClient(t, createTestCluster())
+		r := &ClientReconciler{
+			kcl: kcl,
+		}
+
+		err := r.ReconcileCredentials(t.Context(), etcdClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		status := etcdClient.Status
+		if status.Phase != apiv1.ClientPending || status.Reason != "InvalidUsername" {
+			t.Errorf("username %q: expected Pending InvalidUsername, got %s %s", tt.username, status.Phase, status.Reason)
+		}
+
+		// certificate with root common name is never issued
+		cert := &cmv1.Certificate{}
+		err = kcl.Get(t.Context(), client.ObjectKeyFromObject(etcdClient), cert)
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("username %q: expected no certificate, got %v", tt.username, err)
+		}
+	}
+}
+
+func TestClientConnection(t *testing.T) {
+	endpoint := "https://test-cluster.default.svc.cluster.local:2379"
+
+	tests := []struct {
+		name      string
+		mountPath string
+		env       string
+		config    string
+	}{
+		{
+			name: "default mount path",
+			env: "ETCDCTL_API=3\n" +
+				"ETCDCTL_ENDPOINTS=" + endpoint + "\n" +
+				"ETCDCTL_CACERT=/etc/etcd/client/ca.crt\n" +
+				"ETCDCTL_CERT=/etc/etcd/client/tls.crt\n" +
+				"ETCDCTL_KEY=/etc/etcd/client/tls.key\n",
+			config: "endpoints:\n" +
+				"- " + endpoint + "\n" +
+				"trusted-ca-file: /etc/etcd/client/ca.crt\n" +
+				"cert-file: /etc/etcd/client/tls.crt\n" +
+				"key-file: /etc/etcd/client/tls.key\n",
+		},
+		{
+			name:      "custom mount path",
+			mountPath: "/var/run/etcd/",
+			env: "ETCDCTL_API=3\n" +
+				"ETCDCTL_ENDPOINTS=" + endpoint + "\n" +
+				"ETCDCTL_CACERT=/var/run/etcd/ca.crt\n" +
+				"ETCDCTL_CERT=/var/run/etcd/tls.crt\n" +
+				"ETCDCTL_KEY=/var/run/etcd/tls.key\n",
+			config: "endpoints:\n" +
+				"- " + endpoint + "\n" +
+				"trusted-ca-file: /var/run/etcd/ca.crt\n" +
+				"cert-file: /var/run/etcd/tls.crt\n" +
+				"key-file: /var/run/etcd/tls.key\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etcdClient := &apiv1.EtcdClient{Spec: apiv1.EtcdClientSpec{MountPath: tt.mountPath}}
+
+			data := ClientConnection(endpoint, etcdClient)
+			if string(data[apiv1.ClientEndpointKey]) != endpoint {
+				t.Errorf("expected endpoint %q, got %q", endpoint, data[apiv1.ClientEndpointKey])
+			}
+			if string(data[apiv1.ClientEnvKey]) != tt.env {
+				t.Errorf("expected env %q, got %q", tt.env, data[apiv1.ClientEnvKey])
+			}
+			if string(data[apiv1.ClientConfigKey]) != tt.config {
+				t.Errorf("expected config %q, got %q", tt.config, data[apiv1.ClientConfigKey])
+			}
+		})
+	}
+}
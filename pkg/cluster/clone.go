@@ -0,0 +1,117 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/backup"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+// CloneEndpointKey is the key of source cluster endpoint in the clone-source secret
+const CloneEndpointKey = "endpoint"
+
+// ReconcileCloneSource copies endpoint and root credentials of the source cluster into the cluster namespace,
+// false is returned while source cluster is not running and bootstrap has to wait
+func (r *Reconciler) ReconcileCloneSource(ctx context.Context, b *resources.Builder, cluster *apiv1.EtcdCluster) (bool, error) {
+	key := CloneSource(cluster)
+	if key == nil {
+		return true, nil
+	}
+
+	source := &apiv1.EtcdCluster{}
+	err := r.kcl.Get(ctx, *key, source)
+	switch {
+	case apierrors.IsNotFound(err):
+		r.cloneBlocked(cluster, "SourceNotFound", fmt.Sprintf("source cluster %s not found", key))
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("get source cluster: %w", err)
+	case source.Status.Phase != apiv1.ClusterRunning:
+		r.cloneBlocked(cluster, "SourceNotReady", fmt.Sprintf("waiting for source cluster %s to be %s", key, apiv1.ClusterRunning))
+		return false, nil
+	}
+
+	secret := &corev1.Secret{}
+	err = r.kcl.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: source.Status.SecretName}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		r.cloneBlocked(cluster, "SourceNotReady", fmt.Sprintf("waiting for credentials of source cluster %s", key))
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("get source credentials: %w", err)
+	}
+
+	b.Secret("clone-source").
+		Data(map[string][]byte{
+			etcd.DefaultCertFile:   secret.Data[etcd.DefaultCertFile],
+			etcd.DefaultKeyFile:    secret.Data[etcd.DefaultKeyFile],
+			etcd.DefaultCACertFile: secret.Data[etcd.DefaultCACertFile],
+			CloneEndpointKey:       []byte(source.Status.Endpoint),
+		})
+
+	return true, nil
+}
+
+// CompleteClone reports snapshot revision of the source cluster once bootstrap member is available
+// and removes copied credentials of the source cluster
+func (r *Reconciler) CompleteClone(ctx context.Context, cluster *apiv1.EtcdCluster, pods []corev1.Pod) error {
+	source := CloneSource(cluster)
+	if source == nil {
+		return nil
+	}
+
+	result := &backup.Result{}
+	for _, pod := range pods {
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != "restore" || status.State.Terminated == nil || status.State.Terminated.Message == "" {
+				continue
+			}
+
+			err := json.Unmarshal([]byte(status.State.Terminated.Message), result)
+			if err != nil {
+				return fmt.Errorf("clone result: %w", err)
+			}
+		}
+	}
+
+	message := fmt.Sprintf("cloned from cluster %s at revision %d", source, result.Revision)
+	r.recorder.Event(cluster, corev1.EventTypeNormal, "Cloned", message)
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterRestore,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Cloned",
+		Message: message,
+	})
+
+	secret := &corev1.Secret{}
+	secret.Namespace = cluster.Namespace
+	secret.Name = cluster.Name + "-clone-source"
+	err := r.kcl.Delete(ctx, secret)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete clone source credentials: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) cloneBlocked(cluster *apiv1.EtcdCluster, reason, message string) {
+	changed := conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterRestore,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if changed {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, reason, message)
+	}
+}
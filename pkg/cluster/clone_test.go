@@ -0,0 +1,193 @@
+package cluster
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+func createTestClone() *apiv1.EtcdCluster {
+	cluster := createTestCluster()
+	cluster.Name = "test-clone"
+	cluster.Status.Phase = apiv1.ClusterBootstrap
+	cluster.Spec.Restore = &apiv1.RestoreSpec{
+		FromCluster: &apiv1.ClusterReference{Name: "test-cluster"},
+	}
+
+	return cluster
+}
+
+func TestReconcileCloneSource(t *testing.T) {
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-user-root",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			etcd.DefaultCertFile:   []byte("cert"),
+			etcd.DefaultKeyFile:    []byte("key"),
+			etcd.DefaultCACertFile: []byte("ca"),
+		},
+	}
+
+	bootstrap := createTestCluster()
+	bootstrap.Status.Phase = apiv1.ClusterBootstrap
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+		ready   bool
+		reason  string
+	}{
+		{
+			name:   "source not found",
+			reason: "SourceNotFound",
+		},
+		{
+			name:    "source not running",
+			objects: []client.Object{bootstrap, credentials},
+			reason:  "SourceNotReady",
+		},
+		{
+			name:    "source credentials not found",
+			objects: []client.Object{createTestCluster()},
+			reason:  "SourceNotReady",
+		},
+		{
+			name:    "copy",
+			objects: []client.Object{createTestCluster(), credentials},
+			ready:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestClone()
+			r := &Reconciler{
+				kcl:      createTestClient(t, tt.objects...),
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			b := resources.NewBuilder(cluster)
+			ready, err := r.ReconcileCloneSource(t.Context(), b, cluster)
+			switch {
+			case err != nil:
+				t.Fatal(err)
+			case ready != tt.ready:
+				t.Errorf("expected ready %t, got %t", tt.ready, ready)
+			}
+
+			cond, ok := conditions.Get(cluster.Status.Conditions, apiv1.ClusterRestore)
+			switch {
+			case tt.reason == "" && ok:
+				t.Errorf("expected no Restore condition, got %s %s", cond.Status, cond.Reason)
+			case tt.reason != "" && (cond.Status != corev1.ConditionFalse || cond.Reason != tt.reason):
+				t.Errorf("expected Restore condition False %s, got %s %s", tt.reason, cond.Status, cond.Reason)
+			}
+
+			var secret *corev1.Secret
+			for _, obj := range b.Objects() {
+				if s, ok := obj.(*corev1.Secret); ok && s.Name == "test-clone-clone-source" {
+					secret = s
+				}
+			}
+
+			switch {
+			case !tt.ready && secret != nil:
+				t.Error("expected source credentials not to be copied")
+			case !tt.ready:
+			case secret == nil:
+				t.Fatal("expected source credentials to be copied")
+			case string(secret.Data[etcd.DefaultCertFile]) != "cert" || string(secret.Data[etcd.DefaultKeyFile]) != "key" || string(secret.Data[etcd.DefaultCACertFile]) != "ca":
+				t.Errorf("expected source credentials, got %v", secret.Data)
+			case string(secret.Data[CloneEndpointKey]) != "https://test-cluster.default.svc.cluster.local:2379":
+				t.Errorf("expected source endpoint, got %q", secret.Data[CloneEndpointKey])
+			}
+		})
+	}
+
+	// running cluster no longer clones
+	cluster := createTestClone()
+	cluster.Status.Phase = apiv1.ClusterRunning
+	r := &Reconciler{
+		kcl:      createTestClient(t),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	b := resources.NewBuilder(cluster)
+	ready, err := r.ReconcileCloneSource(t.Context(), b, cluster)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case !ready:
+		t.Error("expected running cluster to be ready")
+	case len(b.Objects()) != 0:
+		t.Errorf("expected no objects, got %d", len(b.Objects()))
+	}
+}
+
+func TestCompleteClone(t *testing.T) {
+	cluster := createTestClone()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-clone-clone-source",
+			Namespace: cluster.Namespace,
+		},
+	}
+
+	pods := []corev1.Pod{{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name: "restore",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Message: `{"key":"","size":1024,"revision":1234}`,
+					},
+				},
+			}},
+		},
+	}}
+
+	kcl := createTestClient(t, secret)
+	r := &Reconciler{
+		kcl:      kcl,
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	err := r.CompleteClone(t.Context(), cluster, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cond, ok := conditions.Get(cluster.Status.Conditions, apiv1.ClusterRestore)
+	switch {
+	case !ok:
+		t.Fatal("expected Restore condition")
+	case cond.Status != corev1.ConditionTrue || cond.Reason != "Cloned":
+		t.Errorf("expected Restore condition True Cloned, got %s %s", cond.Status, cond.Reason)
+	case cond.Message != "cloned from cluster default/test-cluster at revision 1234":
+		t.Errorf("unexpected Restore message %q", cond.Message)
+	}
+
+	// copied credentials of the source cluster are removed
+	err = kcl.Get(t.Context(), client.ObjectKeyFromObject(secret), secret)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected clone source secret to be deleted, got %v", err)
+	}
+
+	// already deleted credentials are ignored
+	err = r.CompleteClone(t.Context(), cluster, pods)
+	if err != nil {
+		t.Errorf("expected missing clone source secret to be ignored, got %v", err)
+	}
+}
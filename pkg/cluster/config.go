@@ -1,8 +1,21 @@
 package cluster
 
+// DefaultClusterDomain is the DNS domain of Kubernetes services
+const DefaultClusterDomain = "cluster.local"
+
 type Config struct {
 	Image             string
 	ControllerImage   string
 	PriorityClassName string
+	ClusterDomain     string
 	BackupEnv         map[string]string
 }
+
+// Domain returns DNS domain of Kubernetes services
+func (c Config) Domain() string {
+	if c.ClusterDomain == "" {
+		return DefaultClusterDomain
+	}
+
+	return c.ClusterDomain
+}
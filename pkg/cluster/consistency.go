@@ -0,0 +1,311 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+)
+
+const (
+	// DefaultConsistencyCheckInterval between consistency checks of member data
+	DefaultConsistencyCheckInterval = time.Hour
+	// ConsistencyCheckTimeout of hashing data of all members
+	ConsistencyCheckTimeout = time.Minute
+	// ConsistencyCheckAttempts while members compact revisions during the check
+	ConsistencyCheckAttempts = 3
+	// ConsistencyPollInterval of pending consistency checks and member replacements
+	ConsistencyPollInterval = 30 * time.Second
+)
+
+// errCompactRevisionChanged is returned when members compacted revisions while their data were hashed
+var errCompactRevisionChanged = errors.New("compact revision changed during check")
+
+// ConsistencyCheckInterval returns interval between consistency checks, zero when consistency check is disabled
+func ConsistencyCheckInterval(cluster *apiv1.EtcdCluster) time.Duration {
+	check := cluster.Spec.ConsistencyCheck
+	switch {
+	case check == nil:
+		return 0
+	case check.Interval != nil:
+		return check.Interval.Duration
+	}
+
+	return DefaultConsistencyCheckInterval
+}
+
+// NextConsistencyCheck returns the time until the next consistency check
+func NextConsistencyCheck(cluster *apiv1.EtcdCluster) time.Duration {
+	status := cluster.Status.ConsistencyCheck
+	if status == nil || status.LastCheckTime == nil {
+		return ConsistencyPollInterval
+	}
+
+	return max(ConsistencyCheckInterval(cluster)-time.Since(status.LastCheckTime.Time), ConsistencyPollInterval)
+}
+
+// ReconcileConsistency compares hashes of member data at a common revision once per interval and marks members
+// diverging from the majority for replacement. Returns true while marked members wait for replacement.
+func (r *Reconciler) ReconcileConsistency(ctx context.Context, cluster *apiv1.EtcdCluster) (bool, error) {
+	if cluster.Status.Phase != apiv1.ClusterRunning {
+		return false, nil
+	}
+
+	// members marked manually are replaced even when consistency check is disabled
+	replacing, err := r.ReplaceMembers(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	interval := ConsistencyCheckInterval(cluster)
+	switch status := cluster.Status.ConsistencyCheck; {
+	case interval == 0:
+		conditions.Clear(&cluster.Status.Conditions, apiv1.ClusterConsistent)
+		cluster.Status.ConsistencyCheck = nil
+		return replacing, nil
+	case status != nil && status.LastCheckTime != nil && time.Since(status.LastCheckTime.Time) < interval:
+		return replacing, nil
+	// hashes are compared while all members are available and none is being replaced
+	case replacing || cluster.Status.AvailableReplicas < cluster.Status.Replicas:
+		return replacing, nil
+	}
+
+	return false, r.checkConsistency(ctx, cluster)
+}
+
+func (r *Reconciler) checkConsistency(ctx context.Context, cluster *apiv1.EtcdCluster) (err error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, ConsistencyCheckTimeout, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	var endpoints []string
+	for _, member := range cluster.Status.Members {
+		if member.Endpoint != "" && member.Available {
+			endpoints = append(endpoints, member.Endpoint)
+		}
+	}
+
+	var (
+		hashes          map[string]uint32
+		rev, compactRev int64
+	)
+	for range ConsistencyCheckAttempts {
+		hashes, rev, compactRev, err = hashMembers(ctx, ecl, endpoints)
+		if !errors.Is(err, errCompactRevisionChanged) && !errors.Is(err, rpctypes.ErrCompacted) {
+			break
+		}
+	}
+
+	status := &apiv1.ConsistencyCheckStatus{
+		LastCheckTime:   ptr.To(metav1.Now()),
+		Revision:        rev,
+		CompactRevision: compactRev,
+	}
+	cluster.Status.ConsistencyCheck = status
+
+	if err != nil {
+		r.consistentCondition(cluster, corev1.ConditionUnknown, "CheckFailed", fmt.Sprintf("hash members: %v", rpctypes.ErrorDesc(err)))
+		return nil
+	}
+
+	// hashes are reported by member names
+	names := make(map[string]string, len(cluster.Status.Members))
+	for _, member := range cluster.Status.Members {
+		names[member.Endpoint] = member.Name
+	}
+	named := make(map[string]uint32, len(hashes))
+	for endpoint, hash := range hashes {
+		named[names[endpoint]] = hash
+	}
+
+	diverged, ok := Diverged(named)
+	status.Diverged = diverged
+	switch {
+	case !ok:
+		r.consistentCondition(cluster, corev1.ConditionFalse, "NoMajority", "hashes of member data differ without majority, members have to be replaced manually")
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "Inconsistent", "Hashes of member data differ without majority at revision %d", rev)
+		return nil
+	case len(diverged) != 0:
+		r.consistentCondition(cluster, corev1.ConditionFalse, "Diverged", fmt.Sprintf("members %s diverged from majority and are replaced", strings.Join(diverged, ", ")))
+	default:
+		r.consistentCondition(cluster, corev1.ConditionTrue, "Consistent", "hashes of member data match")
+		return nil
+	}
+
+	for _, name := range diverged {
+		pod := &corev1.Pod{}
+		err = r.kcl.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, pod)
+		switch {
+		case apierrors.IsNotFound(err):
+			continue
+		case err != nil:
+			return fmt.Errorf("get member pod: %w", err)
+		}
+
+		base := pod.DeepCopy()
+		metav1.SetMetaDataAnnotation(&pod.ObjectMeta, apiv1.ReplaceAnnotation, time.Now().UTC().Format(time.RFC3339))
+		err = r.kcl.Patch(ctx, pod, client.MergeFrom(base))
+		if err != nil {
+			return fmt.Errorf("mark member for replacement: %w", err)
+		}
+
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "MemberDiverged", "Member %q diverged from majority at revision %d, marked for replacement", name, rev)
+	}
+
+	return nil
+}
+
+// ReplaceMembers deletes a single member pod marked for replacement once all members are available, member is removed
+// from cluster when its pod is deleted and joins again without data. Volume claim of persistent member is deleted
+// first, otherwise recreated pod restarts with diverged data. Returns true while marked members are pending.
+func (r *Reconciler) ReplaceMembers(ctx context.Context, cluster *apiv1.EtcdCluster) (bool, error) {
+	pods := &corev1.PodList{}
+	err := r.kcl.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+	})
+	if err != nil {
+		return false, fmt.Errorf("list cluster pods: %w", err)
+	}
+
+	var marked []*corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if _, ok := pod.Annotations[apiv1.ReplaceAnnotation]; !ok {
+			continue
+		}
+		// wait for replaced member to be deleted
+		if !pod.DeletionTimestamp.IsZero() {
+			return true, nil
+		}
+		// replacing a member of cluster with less than 3 members would lose quorum
+		if !IsReadReplica(pod) && cluster.Status.Replicas < 3 {
+			continue
+		}
+		marked = append(marked, pod)
+	}
+
+	switch {
+	case len(marked) == 0:
+		return false, nil
+	case cluster.Status.AvailableReplicas < cluster.Status.Replicas:
+		return true, nil
+	}
+
+	pod := slices.MinFunc(marked, func(l, r *corev1.Pod) int {
+		return strings.Compare(l.Name, r.Name)
+	})
+
+	// claim is protected until its pod is deleted, statefulset recreates pod with a new claim
+	if cluster.Spec.Storage != nil && !IsReadReplica(pod) {
+		claim := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      "data-" + pod.Name,
+			},
+		}
+		err = r.kcl.Delete(ctx, claim)
+		if client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("delete member volume claim: %w", err)
+		}
+	}
+
+	err = r.kcl.Delete(ctx, pod, client.Preconditions{UID: &pod.UID})
+	if client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("delete member pod: %w", err)
+	}
+
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "ReplacingMember", "Replacing member %q marked for replacement", pod.Name)
+
+	return true, nil
+}
+
+func (r *Reconciler) consistentCondition(cluster *apiv1.EtcdCluster, status corev1.ConditionStatus, reason, message string) {
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterConsistent,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// hashMembers returns hashes of member data by endpoint at the lowest current revision of members,
+// hashes are only comparable when members have the same compact revision
+func hashMembers(ctx context.Context, ecl *etcdv3.Client, endpoints []string) (map[string]uint32, int64, int64, error) {
+	var rev int64
+	for _, endpoint := range endpoints {
+		resp, err := ecl.Status(ctx, endpoint)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("status of %s: %w", endpoint, err)
+		}
+
+		if rev == 0 || resp.Header.Revision < rev {
+			rev = resp.Header.Revision
+		}
+	}
+
+	hashes := make(map[string]uint32, len(endpoints))
+	compactRev := int64(-1)
+	for _, endpoint := range endpoints {
+		resp, err := ecl.HashKV(ctx, endpoint, rev)
+		if err != nil {
+			return nil, rev, 0, fmt.Errorf("hash of %s: %w", endpoint, err)
+		}
+
+		if compactRev != -1 && resp.CompactRevision != compactRev {
+			return nil, rev, 0, errCompactRevisionChanged
+		}
+		compactRev = resp.CompactRevision
+		hashes[endpoint] = resp.Hash
+	}
+
+	return hashes, rev, compactRev, nil
+}
+
+// Diverged returns sorted names of members which hashes differ from the majority, ok is false without majority
+func Diverged(hashes map[string]uint32) (diverged []string, ok bool) {
+	counts := map[uint32]int{}
+	for _, hash := range hashes {
+		counts[hash]++
+	}
+
+	var majority uint32
+	for hash, count := range counts {
+		if count*2 > len(hashes) {
+			majority, ok = hash, true
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(hashes)) {
+		if hashes[name] != majority {
+			diverged = append(diverged, name)
+		}
+	}
+
+	return diverged, true
+}
@@ -0,0 +1,153 @@
+package cluster
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+func TestDiverged(t *testing.T) {
+	tests := []struct {
+		name     string
+		hashes   map[string]uint32
+		diverged []string
+		ok       bool
+	}{
+		{
+			name: "none",
+			ok:   false,
+		},
+		{
+			name:   "single member",
+			hashes: map[string]uint32{"test-cluster-a": 1},
+			ok:     true,
+		},
+		{
+			name:   "consistent",
+			hashes: map[string]uint32{"test-cluster-a": 1, "test-cluster-b": 1, "test-cluster-c": 1},
+			ok:     true,
+		},
+		{
+			name:     "diverged",
+			hashes:   map[string]uint32{"test-cluster-a": 1, "test-cluster-b": 2, "test-cluster-c": 1},
+			diverged: []string{"test-cluster-b"},
+			ok:       true,
+		},
+		{
+			name:     "diverged minority",
+			hashes:   map[string]uint32{"test-cluster-a": 3, "test-cluster-b": 2, "test-cluster-c": 1, "test-cluster-d": 1, "test-cluster-e": 1},
+			diverged: []string{"test-cluster-a", "test-cluster-b"},
+			ok:       true,
+		},
+		{
+			name:   "no majority",
+			hashes: map[string]uint32{"test-cluster-a": 1, "test-cluster-b": 2},
+			ok:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diverged, ok := Diverged(tt.hashes)
+			if ok != tt.ok {
+				t.Errorf("expected ok %t, got %t", tt.ok, ok)
+			}
+			if !slices.Equal(diverged, tt.diverged) {
+				t.Errorf("expected diverged %v, got %v", tt.diverged, diverged)
+			}
+		})
+	}
+}
+
+func TestReplaceMembers(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage *apiv1.StorageSpec
+		claims  []string
+	}{
+		{
+			name:   "ephemeral",
+			claims: []string{"data-test-cluster-0", "data-test-cluster-1", "data-test-cluster-2"},
+		},
+		// diverged data of persistent member is not reused by recreated pod
+		{
+			name:    "storage",
+			storage: &apiv1.StorageSpec{},
+			claims:  []string{"data-test-cluster-0", "data-test-cluster-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Storage = tt.storage
+			cluster.Status.Replicas = 3
+			cluster.Status.AvailableReplicas = 3
+
+			var objects []client.Object
+			for i := range 3 {
+				name := fmt.Sprintf("test-cluster-%d", i)
+				pod := createTestMemberPod(cluster, name, time.Hour, corev1.PodRunning)
+				pod.UID = types.UID(name)
+				if i == 1 {
+					pod.Annotations = map[string]string{apiv1.ReplaceAnnotation: "2025-01-01T00:00:00Z"}
+				}
+
+				claim := &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "data-" + name,
+						Namespace: cluster.Namespace,
+					},
+				}
+				objects = append(objects, pod, claim)
+			}
+
+			kcl := createTestClient(t, objects...)
+			r := &Reconciler{
+				kcl:      kcl,
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			replacing, err := r.ReplaceMembers(t.Context(), cluster)
+			switch {
+			case err != nil:
+				t.Fatal(err)
+			case !replacing:
+				t.Error("expected member to be replaced")
+			}
+
+			pods := &corev1.PodList{}
+			err = kcl.List(t.Context(), pods)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if slices.ContainsFunc(pods.Items, func(pod corev1.Pod) bool { return pod.Name == "test-cluster-1" }) {
+				t.Error("expected marked member pod to be deleted")
+			}
+
+			claims := &corev1.PersistentVolumeClaimList{}
+			err = kcl.List(t.Context(), claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for _, claim := range claims.Items {
+				names = append(names, claim.Name)
+			}
+			if !slices.Equal(names, tt.claims) {
+				t.Errorf("expected volume claims %v, got %v", tt.claims, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/defrag"
+)
+
+// DefragPollInterval of scheduled defrag waiting for members to be available and defragmented
+const DefragPollInterval = 10 * time.Second
+
+// DefragSuspended returns true when scheduled defrag is suspended
+func DefragSuspended(cluster *apiv1.EtcdCluster) bool {
+	return cluster.Spec.Defrag != nil && cluster.Spec.Defrag.Suspend != nil && *cluster.Spec.Defrag.Suspend
+}
+
+// DefragInProgress returns true from schedule time until all members exceeding thresholds are defragmented
+func DefragInProgress(cluster *apiv1.EtcdCluster) bool {
+	status := cluster.Status.Defrag
+	return status != nil && status.LastScheduleTime != nil && status.CompletionTime == nil
+}
+
+// DefragParams returns thresholds of unused space of member database
+func DefragParams(cluster *apiv1.EtcdCluster) defrag.Params {
+	params := defrag.Params{
+		Ratio: defrag.DefaultRatio,
+		Size:  defrag.DefaultSize,
+	}
+
+	spec := cluster.Spec.Defrag
+	if spec == nil {
+		return params
+	}
+	if spec.Size != nil {
+		params.Size = spec.Size.Value()
+	}
+	// ratio is validated by crd pattern
+	if spec.Ratio != nil {
+		ratio, err := strconv.ParseFloat(*spec.Ratio, 64)
+		if err == nil {
+			params.Ratio = ratio
+		}
+	}
+
+	return params
+}
+
+// NextDefragSchedule returns the next schedule time after the latest defrag was started,
+// the first defrag is scheduled after the schedule was first observed so that existing clusters are not
+// defragmented right after operator upgrade
+func NextDefragSchedule(cluster *apiv1.EtcdCluster) (time.Time, error) {
+	spec := DefragSchedule
+	if cluster.Spec.Defrag != nil && cluster.Spec.Defrag.Schedule != nil {
+		spec = *cluster.Spec.Defrag.Schedule
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse defrag schedule: %w", err)
+	}
+
+	last := time.Now()
+	switch status := cluster.Status.Defrag; {
+	case status == nil:
+	case status.LastScheduleTime != nil:
+		last = status.LastScheduleTime.Time
+	case status.ObservedTime != nil:
+		last = status.ObservedTime.Time
+	}
+
+	return schedule.Next(last), nil
+}
+
+// ReconcileDefrag starts defrag on schedule and defragments members exceeding thresholds once per schedule,
+// one member at a time while all members are available. Followers are defragmented first, leader is defragmented
+// after leadership is moved to another member. Returns true while defrag is in progress.
+func (r *Reconciler) ReconcileDefrag(ctx context.Context, cluster *apiv1.EtcdCluster) (defragging bool, err error) {
+	if cluster.Status.Phase != apiv1.ClusterRunning || DefragSuspended(cluster) {
+		return false, nil
+	}
+
+	if cluster.Status.Defrag == nil {
+		cluster.Status.Defrag = &apiv1.DefragStatus{
+			ObservedTime: ptr.To(metav1.Now()),
+		}
+	}
+
+	if !DefragInProgress(cluster) {
+		next, err := NextDefragSchedule(cluster)
+		if err != nil || time.Now().Before(next) {
+			return false, err
+		}
+
+		cluster.Status.Defrag = &apiv1.DefragStatus{
+			ObservedTime:     cluster.Status.Defrag.ObservedTime,
+			LastScheduleTime: ptr.To(metav1.Now()),
+		}
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "DefragStarted", "Started defrag of members exceeding thresholds")
+	}
+
+	switch {
+	// NOSPACE alarm remediation defragments members on its own
+	case conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterAlarm):
+		return true, nil
+	// defrag blocks member requests, wait for rollouts to complete and all members to be available
+	case conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterUpgrading),
+		conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterDowngrading),
+		conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterCARotation),
+		cluster.Status.AvailableReplicas < cluster.Status.Replicas:
+		return true, nil
+	}
+
+	status := cluster.Status.Defrag
+	params := DefragParams(cluster)
+	member := NextDefrag(cluster.Status.Members, func(member *apiv1.MemberStatus) bool {
+		// members are defragmented once per schedule, failed members are retried on the next schedule
+		if member.LastDefragTime != nil && !member.LastDefragTime.Before(status.LastScheduleTime) {
+			return false
+		}
+		if slices.Contains(status.Failed, member.Name) {
+			return false
+		}
+
+		return defrag.Needed(member.Size.Value(), member.SizeInUse.Value(), params)
+	})
+	switch {
+	case member == nil && len(status.Failed) != 0:
+		status.CompletionTime = ptr.To(metav1.Now())
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "DefragCompleted", "Completed defrag of members exceeding thresholds, failed members: %s", strings.Join(status.Failed, ", "))
+		return false, nil
+	case member == nil:
+		status.CompletionTime = ptr.To(metav1.Now())
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "DefragCompleted", "Completed defrag of members exceeding thresholds")
+		return false, nil
+	}
+
+	// leadership is moved to an available follower, the member is defragmented once status reports it as follower
+	if member.Role == apiv1.MemberRoleLeader {
+		i := slices.IndexFunc(cluster.Status.Members, func(follower apiv1.MemberStatus) bool {
+			return follower.Role == apiv1.MemberRoleMember && follower.Available
+		})
+		if i != -1 {
+			return true, r.MoveLeader(ctx, cluster, member, cluster.Status.Members[i].Name)
+		}
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, DefragTimeout, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	_, err = ecl.Defragment(ctx, member.Endpoint)
+	if err != nil {
+		status.Failed = append(status.Failed, member.Name)
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "DefragFailed", "Defragment member %q: %v, retrying on the next schedule", member.Name, rpctypes.ErrorDesc(err))
+		return true, nil
+	}
+
+	unused := resource.NewQuantity(member.Size.Value()-member.SizeInUse.Value(), resource.DecimalSI)
+	member.LastDefragTime = ptr.To(metav1.Now())
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "Defragmented", "Defragmented member %q reclaiming %s of %s", member.Name, unused, member.Size)
+
+	return true, nil
+}
+
+// nextSchedule returns the time until the next consistency check or defrag, zero when neither is scheduled
+func nextSchedule(cluster *apiv1.EtcdCluster) time.Duration {
+	var next time.Duration
+	if ConsistencyCheckInterval(cluster) != 0 {
+		next = NextConsistencyCheck(cluster)
+	}
+
+	if DefragSuspended(cluster) {
+		return next
+	}
+
+	schedule, err := NextDefragSchedule(cluster)
+	if err != nil {
+		return next
+	}
+
+	if until := max(time.Until(schedule), DefragPollInterval); next == 0 || until < next {
+		next = until
+	}
+
+	return next
+}
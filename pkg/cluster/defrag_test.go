@@ -0,0 +1,210 @@
+package cluster
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/defrag"
+)
+
+func TestDefragParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *apiv1.DefragSpec
+		expected defrag.Params
+	}{
+		{
+			name:     "default",
+			expected: defrag.Params{Ratio: 0.7, Size: 128_000_000},
+		},
+		{
+			name: "threshold",
+			spec: &apiv1.DefragSpec{
+				Size:  ptr.To(resource.MustParse("1G")),
+				Ratio: ptr.To("0.1"),
+			},
+			expected: defrag.Params{Ratio: 0.1, Size: 1_000_000_000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Defrag = tt.spec
+
+			params := DefragParams(cluster)
+			if params != tt.expected {
+				t.Errorf("expected params %+v, got %+v", tt.expected, params)
+			}
+		})
+	}
+}
+
+func TestNextDefragSchedule(t *testing.T) {
+	observed := time.Date(2024, 5, 9, 7, 52, 48, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		spec     *apiv1.DefragSpec
+		status   *apiv1.DefragStatus
+		expected time.Time
+	}{
+		{
+			name:     "observed",
+			status:   &apiv1.DefragStatus{ObservedTime: ptr.To(metav1.NewTime(observed))},
+			expected: time.Date(2024, 5, 10, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "schedule",
+			spec:     &apiv1.DefragSpec{Schedule: ptr.To("@hourly")},
+			status:   &apiv1.DefragStatus{ObservedTime: ptr.To(metav1.NewTime(observed))},
+			expected: time.Date(2024, 5, 9, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "last schedule",
+			status: &apiv1.DefragStatus{
+				ObservedTime:     ptr.To(metav1.NewTime(observed)),
+				LastScheduleTime: ptr.To(metav1.NewTime(time.Date(2024, 5, 12, 1, 0, 0, 0, time.UTC))),
+			},
+			expected: time.Date(2024, 5, 13, 1, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			// cluster created long before schedule was observed
+			cluster.CreationTimestamp = metav1.NewTime(observed.AddDate(-1, 0, 0))
+			cluster.Spec.Defrag = tt.spec
+			cluster.Status.Defrag = tt.status
+
+			next, err := NextDefragSchedule(cluster)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if !next.Equal(tt.expected) {
+				t.Errorf("expected next schedule %s, got %s", tt.expected, next)
+			}
+		})
+	}
+
+	// schedule not observed yet is counted from now
+	cluster := createTestCluster()
+	cluster.CreationTimestamp = metav1.NewTime(observed)
+	next, err := NextDefragSchedule(cluster)
+	switch {
+	case err != nil:
+		t.Fatal("unexpected error:", err)
+	case !next.After(time.Now()):
+		t.Errorf("expected next schedule in the future, got %s", next)
+	}
+}
+
+func TestReconcileDefragObserved(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.CreationTimestamp = metav1.NewTime(time.Now().AddDate(-1, 0, 0))
+	cluster.Spec.Defrag = &apiv1.DefragSpec{Schedule: ptr.To("@hourly")}
+
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		recorder: recorder,
+	}
+
+	// first reconcile after upgrade only observes the schedule
+	defragging, err := r.ReconcileDefrag(t.Context(), cluster)
+	switch status := cluster.Status.Defrag; {
+	case err != nil:
+		t.Fatal(err)
+	case defragging:
+		t.Error("expected defrag not to start")
+	case status == nil || status.ObservedTime == nil:
+		t.Fatalf("expected observed time, got %+v", status)
+	case status.LastScheduleTime != nil:
+		t.Errorf("expected no schedule time, got %s", status.LastScheduleTime)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("expected no event, got %q", event)
+	default:
+	}
+}
+
+func TestReconcileDefragFailed(t *testing.T) {
+	member := func(name string, role apiv1.MemberRole, size, inUse string) apiv1.MemberStatus {
+		return apiv1.MemberStatus{
+			Name:      name,
+			Endpoint:  "https://" + name + ":2379",
+			Available: true,
+			Role:      role,
+			Size:      ptr.To(resource.MustParse(size)),
+			SizeInUse: ptr.To(resource.MustParse(inUse)),
+		}
+	}
+
+	cluster := createTestCluster()
+	cluster.Spec.Defrag = &apiv1.DefragSpec{Schedule: ptr.To("@hourly")}
+	cluster.Status.Replicas = 3
+	cluster.Status.AvailableReplicas = 3
+	cluster.Status.Defrag = &apiv1.DefragStatus{
+		LastScheduleTime: ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Hour))),
+	}
+	cluster.Status.Members = []apiv1.MemberStatus{
+		member("test-cluster-a", apiv1.MemberRoleLeader, "1G", "950M"),
+		member("test-cluster-b", apiv1.MemberRoleMember, "1G", "100M"),
+		member("test-cluster-c", apiv1.MemberRoleMember, "1G", "950M"),
+	}
+
+	request := "Defragment https://test-cluster-b:2379"
+	fake := &fakeEtcd{errors: map[string]error{request: errors.New("timeout")}}
+	fakeConnect(t, fake)
+
+	r := &Reconciler{
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	// failed member is not retried within the same schedule
+	for _, expected := range []bool{true, false} {
+		defragging, err := r.ReconcileDefrag(t.Context(), cluster)
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case defragging != expected:
+			t.Errorf("expected defragging %v, got %v", expected, defragging)
+		}
+	}
+
+	status := cluster.Status.Defrag
+	switch {
+	case !slices.Equal(fake.requests, []string{request}):
+		t.Errorf("expected single defrag request, got %v", fake.requests)
+	case !slices.Equal(status.Failed, []string{"test-cluster-b"}):
+		t.Errorf("expected failed member test-cluster-b, got %v", status.Failed)
+	case status.CompletionTime == nil:
+		t.Error("expected defrag to be completed")
+	case cluster.Status.Members[1].LastDefragTime != nil:
+		t.Error("expected failed member not to be reported as defragmented")
+	}
+
+	// failed member is retried on the next schedule
+	delete(fake.errors, request)
+	defragging, err := r.ReconcileDefrag(t.Context(), cluster)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case !defragging:
+		t.Error("expected defrag to be started")
+	case len(cluster.Status.Defrag.Failed) != 0:
+		t.Errorf("expected failed members to be reset, got %v", cluster.Status.Defrag.Failed)
+	case cluster.Status.Members[1].LastDefragTime == nil:
+		t.Errorf("expected member to be defragmented, got requests %v", fake.requests)
+	}
+}
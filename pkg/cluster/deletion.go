@@ -0,0 +1,127 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/backup"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+// ReconcileFinalizer keeps deletion policy finalizer when cluster data has to be handled before deletion
+func (r *Reconciler) ReconcileFinalizer(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	policy := cluster.Spec.DeletionPolicy
+	required := policy == apiv1.DeletionPolicyDelete || policy == apiv1.DeletionPolicyBackupThenDelete
+	if required == controllerutil.ContainsFinalizer(cluster, apiv1.DeletionPolicyFinalizer) {
+		return nil
+	}
+
+	base := cluster.DeepCopy()
+	if required {
+		controllerutil.AddFinalizer(cluster, apiv1.DeletionPolicyFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(cluster, apiv1.DeletionPolicyFinalizer)
+	}
+
+	err := r.kcl.Patch(ctx, cluster, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	if err != nil {
+		return fmt.Errorf("patch finalizer: %w", err)
+	}
+
+	return nil
+}
+
+// ReconcileDeletion applies deletion policy to cluster data before finalizer is removed.
+// Members are kept running until then as dependents are garbage collected after the cluster is gone.
+func (r *Reconciler) ReconcileDeletion(ctx context.Context, cluster *apiv1.EtcdCluster) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(cluster, apiv1.DeletionPolicyFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	// prefix of backup objects of this cluster only
+	prefix := path.Join(cluster.Namespace, cluster.Name) + "/"
+	bucket := r.config.BackupEnv["AWS_BUCKET_NAME"]
+
+	switch cluster.Spec.DeletionPolicy {
+	case apiv1.DeletionPolicyBackupThenDelete:
+		if len(r.config.BackupEnv) == 0 {
+			r.recorder.Event(cluster, corev1.EventTypeWarning, "BackupNotConfigured", "final backup is not possible as backup is not configured, change deletionPolicy to Retain to proceed with deletion")
+			return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+		}
+
+		key := path.Join(prefix, time.Now().UTC().Format(backup.DateFormat))
+		result, err := r.FinalBackup(ctx, cluster, backup.Location{Bucket: bucket, Key: key})
+		if err != nil {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "FinalBackupFailed", "final backup failed: %v", err)
+			return reconcile.Result{}, fmt.Errorf("final backup: %w", err)
+		}
+
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "FinalBackup", "Uploaded final backup %q at revision %d", result.Key, result.Revision)
+	case apiv1.DeletionPolicyDelete:
+		if len(r.config.BackupEnv) == 0 {
+			break
+		}
+
+		scl, err := backup.NewClient(ctx)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("backup client: %w", err)
+		}
+
+		deleted, err := backup.DeleteObjects(ctx, scl, bucket, prefix)
+		if err != nil {
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "DeleteBackupsFailed", "deleted %d backup objects with prefix %q: %v", deleted, prefix, err)
+			return reconcile.Result{}, fmt.Errorf("delete backups: %w", err)
+		}
+
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "BackupsDeleted", "Deleted %d backup objects with prefix %q", deleted, prefix)
+	}
+
+	base := cluster.DeepCopy()
+	controllerutil.RemoveFinalizer(cluster, apiv1.DeletionPolicyFinalizer)
+	err := r.kcl.Patch(ctx, cluster, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	if client.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, fmt.Errorf("remove finalizer: %w", err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// FinalBackup uploads snapshot of the cluster to the backup location
+func (r *Reconciler) FinalBackup(ctx context.Context, cluster *apiv1.EtcdCluster, location backup.Location) (_ *backup.Result, err error) {
+	key := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Status.SecretName,
+	}
+	tlsConfig, err := r.tlsCache.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("tls config: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, ActiveDeadline, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := etcd.Connect(ctx, tlsConfig, cluster.Status.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("connect to cluster %v: %w", key, err)
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	scl, err := backup.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("backup client: %w", err)
+	}
+
+	return backup.Backup(ctx, ecl, scl, location)
+}
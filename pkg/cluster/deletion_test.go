@@ -0,0 +1,188 @@
+package cluster
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+// fakeS3 serves list and delete requests for objects of a single bucket,
+// deletion of keys in denied fails with AccessDenied
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects []string
+	denied  []string
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/xml")
+
+	switch {
+	case req.Method == http.MethodGet && req.URL.Query().Get("list-type") == "2":
+		prefix := req.URL.Query().Get("prefix")
+		fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated>`)
+		for _, key := range s.objects {
+			if strings.HasPrefix(key, prefix) {
+				fmt.Fprintf(w, `<Contents><Key>%s</Key><LastModified>2025-01-01T00:00:00.000Z</LastModified><Size>1</Size></Contents>`, key)
+			}
+		}
+		fmt.Fprint(w, `</ListBucketResult>`)
+	case req.Method == http.MethodPost && req.URL.Query().Has("delete"):
+		body, _ := io.ReadAll(req.Body)
+		input := struct {
+			Objects []struct {
+				Key string
+			} `xml:"Object"`
+		}{}
+		err := xml.Unmarshal(body, &input)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fmt.Fprint(w, `<DeleteResult>`)
+		for _, obj := range input.Objects {
+			if slices.Contains(s.denied, obj.Key) {
+				fmt.Fprintf(w, `<Error><Key>%s</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`, obj.Key)
+				continue
+			}
+			s.objects = slices.DeleteFunc(s.objects, func(key string) bool {
+				return key == obj.Key
+			})
+		}
+		fmt.Fprint(w, `</DeleteResult>`)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func TestReconcileDeletion(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    apiv1.DeletionPolicy
+		config    Config
+		denied    []string
+		err       bool
+		requeue   time.Duration
+		finalizer bool
+		remaining []string
+		event     string
+	}{
+		{
+			name:      "final backup not configured",
+			policy:    apiv1.DeletionPolicyBackupThenDelete,
+			requeue:   5 * time.Minute,
+			finalizer: true,
+			remaining: []string{"default/test-cluster/1", "default/test-cluster/2", "default/test-cluster-2/1"},
+			event:     "BackupNotConfigured",
+		},
+		{
+			name:      "retain",
+			policy:    apiv1.DeletionPolicyRetain,
+			config:    createTestConfig(),
+			remaining: []string{"default/test-cluster/1", "default/test-cluster/2", "default/test-cluster-2/1"},
+		},
+		{
+			name:      "delete",
+			policy:    apiv1.DeletionPolicyDelete,
+			config:    createTestConfig(),
+			remaining: []string{"default/test-cluster-2/1"},
+			event:     "BackupsDeleted",
+		},
+		{
+			name:      "delete partial failure",
+			policy:    apiv1.DeletionPolicyDelete,
+			config:    createTestConfig(),
+			denied:    []string{"default/test-cluster/2"},
+			err:       true,
+			finalizer: true,
+			remaining: []string{"default/test-cluster/2", "default/test-cluster-2/1"},
+			event:     "DeleteBackupsFailed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s3 := &fakeS3{
+				objects: []string{"default/test-cluster/1", "default/test-cluster/2", "default/test-cluster-2/1"},
+				denied:  tt.denied,
+			}
+			srv := httptest.NewServer(s3)
+			t.Cleanup(srv.Close)
+
+			t.Setenv("AWS_ENDPOINT_URL", srv.URL)
+			t.Setenv("AWS_REGION", "us-east-1")
+			t.Setenv("AWS_ACCESS_KEY_ID", "example")
+			t.Setenv("AWS_SECRET_ACCESS_KEY", "example")
+
+			cluster := createTestCluster()
+			cluster.Spec.DeletionPolicy = tt.policy
+			cluster.Finalizers = []string{apiv1.DeletionPolicyFinalizer}
+
+			kcl := createTestClient(t, cluster)
+			err := kcl.Get(t.Context(), client.ObjectKeyFromObject(cluster), cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				kcl:      kcl,
+				recorder: recorder,
+				config:   tt.config,
+			}
+
+			result, err := r.ReconcileDeletion(t.Context(), cluster)
+			switch {
+			case tt.err && err == nil:
+				t.Error("expected error")
+			case !tt.err && err != nil:
+				t.Fatal(err)
+			case result.RequeueAfter != tt.requeue:
+				t.Errorf("expected requeue after %s, got %s", tt.requeue, result.RequeueAfter)
+			}
+
+			current := &apiv1.EtcdCluster{}
+			err = kcl.Get(t.Context(), client.ObjectKeyFromObject(cluster), current)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if finalizer := controllerutil.ContainsFinalizer(current, apiv1.DeletionPolicyFinalizer); finalizer != tt.finalizer {
+				t.Errorf("expected finalizer %t, got %t", tt.finalizer, finalizer)
+			}
+
+			if !slices.Equal(s3.objects, tt.remaining) {
+				t.Errorf("expected remaining objects %v, got %v", tt.remaining, s3.objects)
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, tt.event) || tt.event == "" {
+					t.Errorf("expected %q event, got %q", tt.event, event)
+				}
+			default:
+				if tt.event != "" {
+					t.Errorf("expected %q event", tt.event)
+				}
+			}
+		})
+	}
+}
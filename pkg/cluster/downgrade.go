@@ -0,0 +1,177 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/coreos/go-semver/semver"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+// maintenanceClient returns client of maintenance API as downgrade is not exposed by etcd v3.5 client, replaced by tests
+var maintenanceClient = func(ecl *etcdv3.Client) etcdserverpb.MaintenanceClient {
+	return etcdserverpb.NewMaintenanceClient(ecl.ActiveConnection())
+}
+
+// queryClusterVersion returns cluster version reported by /version endpoint of the cluster, replaced by tests
+var queryClusterVersion = func(ctx context.Context, tlsCache *etcd.TLSCache, cluster *apiv1.EtcdCluster) (string, error) {
+	key := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Status.SecretName,
+	}
+	tlsConfig, err := tlsCache.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("tls config: %v", err)
+	}
+
+	versions, err := etcd.Versions(ctx, tlsConfig, cluster.Status.Endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	return versions.Cluster, nil
+}
+
+// ReconcileDowngrade downgrades cluster by one minor version using etcd downgrade API.
+// Downgrade is validated and enabled first, older image is rolled out once cluster version is lowered.
+// Downgrade is cancelled when spec version is changed to a different version while in progress.
+func (r *Reconciler) ReconcileDowngrade(ctx context.Context, cluster *apiv1.EtcdCluster, target *semver.Version) (err error) {
+	clusterVersion := fmt.Sprintf("%d.%d", target.Major, target.Minor)
+	cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterDowngrading)
+
+	// older image is being rolled out, downgrade API is no longer involved
+	if cluster.Status.DowngradeVersion == clusterVersion && cond.Reason == "Rolling" {
+		r.RollDowngrade(cluster, target)
+		return nil
+	}
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	maintenance := maintenanceClient(ecl)
+
+	switch cluster.Status.DowngradeVersion {
+	// start downgrade
+	case "":
+		_, err = maintenance.Downgrade(ctx, &etcdserverpb.DowngradeRequest{
+			Action:  etcdserverpb.DowngradeRequest_VALIDATE,
+			Version: clusterVersion + ".0",
+		})
+		if err != nil {
+			r.downgradeFailed(cluster, "DowngradeInvalid", fmt.Sprintf("validate downgrade to %s: %v", clusterVersion, rpctypes.ErrorDesc(err)))
+			return nil
+		}
+
+		_, err = maintenance.Downgrade(ctx, &etcdserverpb.DowngradeRequest{
+			Action:  etcdserverpb.DowngradeRequest_ENABLE,
+			Version: clusterVersion + ".0",
+		})
+		if err != nil {
+			return fmt.Errorf("enable downgrade: %w", rpctypes.Error(err))
+		}
+
+		cluster.Status.DowngradeVersion = clusterVersion
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "DowngradeEnabled", "Enabled downgrade of cluster version to %s", clusterVersion)
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterDowngrading,
+			Status:  corev1.ConditionTrue,
+			Reason:  "Enabled",
+			Message: fmt.Sprintf("waiting for cluster version to be lowered to %s", clusterVersion),
+		})
+	// wait for cluster version to be lowered before older image is rolled out
+	case clusterVersion:
+		version, err := queryClusterVersion(ctx, r.tlsCache, cluster)
+		if err != nil {
+			return fmt.Errorf("query cluster version: %w", err)
+		}
+
+		lowered, err := semver.NewVersion(version)
+		if err != nil || lowered.Major != target.Major || lowered.Minor != target.Minor {
+			return nil
+		}
+
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "Downgrading", "Cluster version lowered to %s, rolling out %s", clusterVersion, target)
+		cluster.Status.ObservedGeneration = 0
+		r.RollDowngrade(cluster, target)
+	// spec version changed - cancel downgrade
+	default:
+		_, err = maintenance.Downgrade(ctx, &etcdserverpb.DowngradeRequest{
+			Action: etcdserverpb.DowngradeRequest_CANCEL,
+		})
+		if err != nil && !errors.Is(rpctypes.Error(err), rpctypes.ErrNoInflightDowngrade) {
+			return fmt.Errorf("cancel downgrade: %w", rpctypes.Error(err))
+		}
+
+		message := fmt.Sprintf("downgrade to %s was cancelled", cluster.Status.DowngradeVersion)
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "DowngradeCancelled", message)
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterDowngrading,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Cancelled",
+			Message: message,
+		})
+		cluster.Status.DowngradeVersion = ""
+		cluster.Status.ObservedGeneration = 0
+	}
+
+	return nil
+}
+
+// RollDowngrade reports progress of older image rollout and completes downgrade once all members run target version
+func (r *Reconciler) RollDowngrade(cluster *apiv1.EtcdCluster, target *semver.Version) {
+	downgraded := 0
+	for _, member := range cluster.Status.Members {
+		if member.Version == target.String() {
+			downgraded++
+		}
+	}
+
+	if downgraded == len(cluster.Status.Members) && cluster.Status.UpdatedReplicas >= cluster.Spec.Replicas {
+		message := fmt.Sprintf("cluster downgraded to %s", target)
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "Downgraded", message)
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterDowngrading,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Downgraded",
+			Message: message,
+		})
+		cluster.Status.DowngradeVersion = ""
+		return
+	}
+
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterDowngrading,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Rolling",
+		Message: fmt.Sprintf("%d of %d members downgraded to %s", downgraded, len(cluster.Status.Members), target),
+	})
+}
+
+func (r *Reconciler) downgradeFailed(cluster *apiv1.EtcdCluster, reason, message string) {
+	changed := conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterDowngrading,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if changed {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, reason, message)
+	}
+}
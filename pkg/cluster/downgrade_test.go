@@ -0,0 +1,221 @@
+package cluster
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+// fakeMaintenanceClient serves downgrade requests of fake etcd
+type fakeMaintenanceClient struct {
+	etcdserverpb.MaintenanceClient
+	fake *fakeEtcd
+}
+
+func (c fakeMaintenanceClient) Downgrade(_ context.Context, req *etcdserverpb.DowngradeRequest, _ ...grpc.CallOption) (*etcdserverpb.DowngradeResponse, error) {
+	return &etcdserverpb.DowngradeResponse{}, c.fake.request("Downgrade %s %s", req.Action, req.Version)
+}
+
+// fakeDowngrade replaces downgrade API and cluster version with fake until the test is finished
+func fakeDowngrade(t *testing.T, fake *fakeEtcd, version *string) {
+	t.Helper()
+
+	fakeConnect(t, fake)
+
+	origClient, origVersion := maintenanceClient, queryClusterVersion
+	t.Cleanup(func() {
+		maintenanceClient, queryClusterVersion = origClient, origVersion
+	})
+
+	maintenanceClient = func(*etcdv3.Client) etcdserverpb.MaintenanceClient {
+		return fakeMaintenanceClient{fake: fake}
+	}
+	queryClusterVersion = func(context.Context, *etcd.TLSCache, *apiv1.EtcdCluster) (string, error) {
+		return *version, nil
+	}
+}
+
+func TestReconcileDowngrade(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Spec.Version = "v3.4.27"
+	cluster.Status.Version = "3.5.7"
+	cluster.Status.ObservedGeneration = 1
+	cluster.Status.Members = []apiv1.MemberStatus{
+		{Name: "test-cluster-a", Version: "3.5.7"},
+		{Name: "test-cluster-b", Version: "3.5.7"},
+		{Name: "test-cluster-c", Version: "3.5.7"},
+	}
+
+	version := "3.5.0"
+	fake := &fakeEtcd{}
+	fakeDowngrade(t, fake, &version)
+
+	r := &Reconciler{
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	steps := []struct {
+		name     string
+		update   func()
+		requests []string
+		status   corev1.ConditionStatus
+		reason   string
+	}{
+		{
+			name:     "enabled",
+			requests: []string{"Downgrade VALIDATE 3.4.0", "Downgrade ENABLE 3.4.0"},
+			status:   corev1.ConditionTrue,
+			reason:   "Enabled",
+		},
+		{
+			name:   "cluster version not lowered",
+			status: corev1.ConditionTrue,
+			reason: "Enabled",
+		},
+		{
+			name: "rolling",
+			update: func() {
+				version = "3.4.0"
+			},
+			status: corev1.ConditionTrue,
+			reason: "Rolling",
+		},
+		{
+			name: "partially rolled",
+			update: func() {
+				cluster.Status.Version = "3.4.27"
+				cluster.Status.Members[0].Version = "3.4.27"
+				cluster.Status.UpdatedReplicas = 1
+			},
+			status: corev1.ConditionTrue,
+			reason: "Rolling",
+		},
+		{
+			name: "downgraded",
+			update: func() {
+				cluster.Status.Members[1].Version = "3.4.27"
+				cluster.Status.Members[2].Version = "3.4.27"
+				cluster.Status.UpdatedReplicas = 3
+			},
+			status: corev1.ConditionFalse,
+			reason: "Downgraded",
+		},
+	}
+
+	for _, step := range steps {
+		fake.requests = nil
+		if step.update != nil {
+			step.update()
+		}
+
+		err := r.ReconcileUpgrade(t.Context(), cluster)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterDowngrading)
+		switch {
+		case !slices.Equal(fake.requests, step.requests):
+			t.Errorf("%s: expected requests %v, got %v", step.name, step.requests, fake.requests)
+		case cond.Status != step.status || cond.Reason != step.reason:
+			t.Errorf("%s: expected condition %s/%s, got %s/%s: %s", step.name, step.status, step.reason, cond.Status, cond.Reason, cond.Message)
+		}
+
+		// older image is applied once cluster version is lowered
+		if step.name == "rolling" && (cluster.Status.ObservedGeneration != 0 || MemberVersion(cluster) != "v3.4.27") {
+			t.Errorf("expected v3.4.27 to be rolled out, got member version %s", MemberVersion(cluster))
+		}
+	}
+
+	if cluster.Status.DowngradeVersion != "" {
+		t.Errorf("expected downgrade version to be reset, got %q", cluster.Status.DowngradeVersion)
+	}
+}
+
+func TestReconcileDowngradeFailed(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             string
+		downgradeVersion string
+		errors           map[string]error
+		requests         []string
+		reason           string
+	}{
+		{
+			name:     "invalid",
+			spec:     "v3.4.27",
+			errors:   map[string]error{"Downgrade VALIDATE 3.4.0": rpctypes.ErrGRPCInvalidDowngradeTargetVersion},
+			requests: []string{"Downgrade VALIDATE 3.4.0"},
+			reason:   "DowngradeInvalid",
+		},
+		{
+			name:             "cancelled",
+			spec:             "v3.5.7",
+			downgradeVersion: "3.4",
+			requests:         []string{"Downgrade CANCEL "},
+			reason:           "Cancelled",
+		},
+		{
+			name:             "cancelled without downgrade in progress",
+			spec:             "v3.5.8",
+			downgradeVersion: "3.4",
+			errors:           map[string]error{"Downgrade CANCEL ": rpctypes.ErrGRPCNoInflightDowngrade},
+			requests:         []string{"Downgrade CANCEL "},
+			reason:           "Cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Version = tt.spec
+			cluster.Status.Version = "3.5.7"
+			cluster.Status.DowngradeVersion = tt.downgradeVersion
+			cluster.Status.ObservedGeneration = 1
+			if tt.downgradeVersion != "" {
+				conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+					Type:   apiv1.ClusterDowngrading,
+					Status: corev1.ConditionTrue,
+					Reason: "Enabled",
+				})
+			}
+
+			version := "3.5.0"
+			fake := &fakeEtcd{errors: tt.errors}
+			fakeDowngrade(t, fake, &version)
+
+			r := &Reconciler{
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			err := r.ReconcileUpgrade(t.Context(), cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterDowngrading)
+			switch {
+			case !slices.Equal(fake.requests, tt.requests):
+				t.Errorf("expected requests %v, got %v", tt.requests, fake.requests)
+			case cond.Status != corev1.ConditionFalse || cond.Reason != tt.reason:
+				t.Errorf("expected condition False/%s, got %s/%s: %s", tt.reason, cond.Status, cond.Reason, cond.Message)
+			case cluster.Status.DowngradeVersion != "":
+				t.Errorf("expected no downgrade version, got %q", cluster.Status.DowngradeVersion)
+			case MemberVersion(cluster) != "v3.5.7":
+				t.Errorf("expected members to keep v3.5.7, got %s", MemberVersion(cluster))
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+	"github.com/agoda-com/etcd-operator/pkg/mirror"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+const (
+	MirrorCredentialsDir = "/etc/etcd/mirror"
+	MirrorTimeout        = 10 * time.Second
+	MirrorPollInterval   = 30 * time.Second
+)
+
+// Mirroring returns true when cluster is standby of the source cluster
+func Mirroring(cluster *apiv1.EtcdCluster) bool {
+	return cluster.Spec.Mirror != nil && !cluster.Spec.Mirror.Promote
+}
+
+// MirrorDeployment builds the worker mirroring keys of the source cluster into running standby cluster,
+// worker is deployed once auth is enabled so that standby cluster is read-only for other clients
+func MirrorDeployment(builder *resources.Builder, cluster *apiv1.EtcdCluster, config Config) *appsv1.Deployment {
+	if !Mirroring(cluster) || cluster.Status.Phase != apiv1.ClusterRunning || !conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterAuth) {
+		return nil
+	}
+
+	// mirror pods are not selected as members
+	deployment := builder.Deployment("mirror").
+		Replicas(1).
+		Selector(apiv1.MirrorLabel, apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster))).
+		PodSpec(MirrorPodSpec(cluster, config))
+
+	if cluster.Spec.PodTemplate != nil {
+		deployment.
+			PodLabels(cluster.Spec.PodTemplate.Labels).
+			PodAnnotations(cluster.Spec.PodTemplate.Annotations)
+	}
+
+	// single worker at a time applies source changes
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type: appsv1.RecreateDeploymentStrategyType,
+	}
+
+	return deployment.Deployment
+}
+
+// MirrorPodSpec runs mirror worker with credentials of both source and standby cluster
+func MirrorPodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
+	credentials := CredentialsSecretVolume(cluster)
+	source := corev1.Volume{
+		Name: "mirror-source",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: cluster.Spec.Mirror.SecretName,
+			},
+		},
+	}
+
+	container := corev1.Container{
+		Name:    "mirror",
+		Image:   config.ControllerImage,
+		Command: []string{"etcd-tools"},
+		Args: []string{
+			"mirror",
+			"--endpoint=" + cluster.Status.Endpoint,
+			"--credentials-dir=" + CredentialsDir,
+			"--source-endpoint=" + cluster.Spec.Mirror.Endpoint,
+			"--source-credentials-dir=" + MirrorCredentialsDir,
+			"--prefix=" + cluster.Spec.Mirror.Prefix,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      credentials.Name,
+				MountPath: CredentialsDir,
+				ReadOnly:  true,
+			},
+			{
+				Name:      source.Name,
+				MountPath: MirrorCredentialsDir,
+				ReadOnly:  true,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: InitResources,
+			Limits:   InitResources,
+		},
+	}
+
+	return JobPodTemplate(cluster, corev1.PodSpec{
+		Containers:        []corev1.Container{container},
+		Volumes:           []corev1.Volume{credentials, source},
+		PriorityClassName: config.PriorityClassName,
+	})
+}
+
+// ReconcileMirror reports mirror lag of standby cluster and stops mirroring once cluster is promoted
+func (r *Reconciler) ReconcileMirror(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	spec := cluster.Spec.Mirror
+	switch {
+	case spec == nil && cluster.Status.Mirror == nil:
+		return nil
+	// mirroring was removed from spec, synced revision is reset so that mirroring can be set up again
+	case spec == nil:
+		err := r.deleteMirror(ctx, cluster)
+		if err != nil {
+			return err
+		}
+
+		err = r.resetMirror(ctx, cluster)
+		if err != nil {
+			return err
+		}
+
+		cluster.Status.Mirror = nil
+		conditions.Clear(&cluster.Status.Conditions, apiv1.ClusterMirroring)
+		return nil
+	case spec.Promote:
+		if cluster.Status.Mirror != nil && cluster.Status.Mirror.PromotedTime != nil {
+			return nil
+		}
+
+		err := r.deleteMirror(ctx, cluster)
+		if err != nil {
+			return err
+		}
+
+		if cluster.Status.Mirror == nil {
+			cluster.Status.Mirror = &apiv1.MirrorStatus{}
+		}
+		cluster.Status.Mirror.PromotedTime = ptr.To(metav1.Now())
+
+		message := fmt.Sprintf("promoted at source revision %d", cluster.Status.Mirror.Revision)
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "Promoted", message)
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterMirroring,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Promoted",
+			Message: message,
+		})
+		return nil
+	case cluster.Status.Phase != apiv1.ClusterRunning:
+		return nil
+	// standby cluster is read-only through etcd auth
+	case !conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterAuth):
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterMirroring,
+			Status:  corev1.ConditionFalse,
+			Reason:  "AuthPending",
+			Message: "waiting for authentication to be enabled before mirroring",
+		})
+		return nil
+	// lag is observed at most once per poll interval
+	case cluster.Status.Mirror != nil && cluster.Status.Mirror.ObservedTime != nil && time.Since(cluster.Status.Mirror.ObservedTime.Time) < MirrorPollInterval:
+		return nil
+	}
+
+	status, err := r.MirrorStatus(ctx, cluster)
+	if err != nil {
+		changed := conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterMirroring,
+			Status:  corev1.ConditionFalse,
+			Reason:  "SourceUnavailable",
+			Message: err.Error(),
+		})
+		if changed {
+			r.recorder.Event(cluster, corev1.EventTypeWarning, "SourceUnavailable", err.Error())
+		}
+		return nil
+	}
+
+	cluster.Status.Mirror = status
+
+	cond := apiv1.ClusterCondition{
+		Type:    apiv1.ClusterMirroring,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Mirroring",
+		Message: fmt.Sprintf("%d revisions behind %s", status.Lag, spec.Endpoint),
+	}
+	if status.Revision == 0 {
+		cond.Reason = "Syncing"
+		cond.Message = fmt.Sprintf("copying keys from %s", spec.Endpoint)
+	}
+	conditions.Upsert(&cluster.Status.Conditions, cond)
+
+	return nil
+}
+
+// MirrorStatus observes current revision of source cluster and the last source revision applied to standby cluster
+func (r *Reconciler) MirrorStatus(ctx context.Context, cluster *apiv1.EtcdCluster) (status *apiv1.MirrorStatus, err error) {
+	ctx, cancel := context.WithTimeout(ctx, MirrorTimeout)
+	defer cancel()
+
+	tlsConfig, err := r.tlsCache.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Status.SecretName})
+	if err != nil {
+		return nil, fmt.Errorf("tls config: %w", err)
+	}
+
+	target, err := etcd.Connect(ctx, tlsConfig, cluster.Status.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("connect to cluster: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, target.Close())
+	}()
+
+	synced, err := mirror.Revision(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("synced revision: %w", err)
+	}
+
+	sourceTLSConfig, err := r.tlsCache.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.Mirror.SecretName})
+	if err != nil {
+		return nil, fmt.Errorf("source tls config: %w", err)
+	}
+
+	source, err := etcd.Connect(ctx, sourceTLSConfig, cluster.Spec.Mirror.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("connect to source: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, source.Close())
+	}()
+
+	resp, err := source.Get(ctx, mirror.RevisionKey)
+	if err != nil {
+		return nil, fmt.Errorf("source revision: %w", err)
+	}
+
+	status = &apiv1.MirrorStatus{
+		Revision:       synced,
+		SourceRevision: resp.Header.Revision,
+		ObservedTime:   ptr.To(metav1.Now()),
+	}
+	if synced != 0 {
+		status.Lag = max(resp.Header.Revision-synced, 0)
+	}
+
+	return status, nil
+}
+
+func (r *Reconciler) deleteMirror(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	deployment := &appsv1.Deployment{}
+	deployment.Namespace = cluster.Namespace
+	deployment.Name = cluster.Name + "-mirror"
+
+	err := r.kcl.Delete(ctx, deployment)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete mirror deployment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) resetMirror(ctx context.Context, cluster *apiv1.EtcdCluster) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, MirrorTimeout)
+	defer cancel()
+
+	tlsConfig, err := r.tlsCache.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Status.SecretName})
+	if err != nil {
+		return fmt.Errorf("tls config: %w", err)
+	}
+
+	ecl, err := etcd.Connect(ctx, tlsConfig, cluster.Status.Endpoint)
+	if err != nil {
+		return fmt.Errorf("connect to cluster: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	_, err = ecl.Delete(ctx, mirror.RevisionKey)
+	if err != nil {
+		return fmt.Errorf("reset synced revision: %w", err)
+	}
+
+	return nil
+}
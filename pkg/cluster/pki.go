@@ -0,0 +1,248 @@
+package cluster
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+// DefaultCADuration is the lifetime of self-signed CAs
+const DefaultCADuration = 5 * 365 * 24 * time.Hour
+
+// PKI builds self-signed CAs and their issuers unless cluster spec refers to existing issuers.
+// Both generations of CAs are kept while CAs are rotated, the previous generation is removed afterwards.
+func PKI(builder *resources.Builder, cluster *apiv1.EtcdCluster) {
+	generations := CAGenerations(cluster)
+	for _, ca := range SelfSignedCAs(cluster) {
+		for _, generation := range generations {
+			cert := builder.CA(CADuration(cluster), caNames(ca, generation)...)
+			if algorithm, size := PrivateKey(cluster); algorithm != "" {
+				cert.PrivateKey(algorithm, size)
+			}
+		}
+
+		if len(generations) != 1 || generations[0] == 0 {
+			continue
+		}
+
+		// cert-manager keeps secrets of deleted certificates
+		meta := metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      CAName(cluster, ca, generations[0]-1),
+		}
+		builder.Delete(&cmv1.Certificate{ObjectMeta: meta})
+		builder.Delete(&cmv1.Issuer{ObjectMeta: meta})
+		builder.Delete(&corev1.Secret{ObjectMeta: meta})
+	}
+
+	if CARotationStep(cluster) == "" {
+		builder.Delete(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      cluster.Name + "-ca-bundle",
+			},
+		})
+	}
+}
+
+// SelfSignedCAs returns names of CAs created by operator
+func SelfSignedCAs(cluster *apiv1.EtcdCluster) []string {
+	pki := cluster.Spec.PKI
+
+	var cas []string
+	if pki == nil || pki.ServerIssuerRef == nil {
+		cas = append(cas, "server-ca")
+	}
+	if pki == nil || pki.PeerIssuerRef == nil {
+		cas = append(cas, "peer-ca")
+	}
+
+	return cas
+}
+
+// CAName returns name of CA certificate, issuer and secret of given generation,
+// the first generation is not suffixed
+func CAName(cluster *apiv1.EtcdCluster, ca string, generation int32) string {
+	return strings.Join(append([]string{cluster.Name}, caNames(ca, generation)...), "-")
+}
+
+// CAGenerations returns generation of CAs signing certificates followed by generation of CAs
+// which are also trusted while CAs are rotated
+func CAGenerations(cluster *apiv1.EtcdCluster) []int32 {
+	signing := int32(0)
+	if cluster.Status.PKI != nil {
+		signing = cluster.Status.PKI.Generation
+	}
+
+	switch CARotationStep(cluster) {
+	case "Issuing", "Trusting":
+		return []int32{signing, signing + 1}
+	case "Reissuing", "Dropping":
+		return []int32{signing, signing - 1}
+	default:
+		return []int32{signing}
+	}
+}
+
+// CARotationStep returns the step of CA rotation in progress
+func CARotationStep(cluster *apiv1.EtcdCluster) string {
+	cond, ok := conditions.Get(cluster.Status.Conditions, apiv1.ClusterCARotation)
+	if !ok || cond.Status != corev1.ConditionTrue {
+		return ""
+	}
+
+	return cond.Reason
+}
+
+// TrustCABundle returns true while members trust both generations of CAs
+func TrustCABundle(cluster *apiv1.EtcdCluster) bool {
+	step := CARotationStep(cluster)
+	return step == "Trusting" || step == "Reissuing"
+}
+
+// ServerIssuer returns issuer of server and client certificates
+func ServerIssuer(cluster *apiv1.EtcdCluster) cmmeta.ObjectReference {
+	var ref *apiv1.IssuerReference
+	if cluster.Spec.PKI != nil {
+		ref = cluster.Spec.PKI.ServerIssuerRef
+	}
+
+	return issuerRef(ref, CAName(cluster, "server-ca", CAGenerations(cluster)[0]))
+}
+
+// PeerIssuer returns issuer of peer certificates
+func PeerIssuer(cluster *apiv1.EtcdCluster) cmmeta.ObjectReference {
+	var ref *apiv1.IssuerReference
+	if cluster.Spec.PKI != nil {
+		ref = cluster.Spec.PKI.PeerIssuerRef
+	}
+
+	return issuerRef(ref, CAName(cluster, "peer-ca", CAGenerations(cluster)[0]))
+}
+
+// PrivateKey returns algorithm and size of private keys, empty algorithm keeps RSA default of certificates
+func PrivateKey(cluster *apiv1.EtcdCluster) (cmv1.PrivateKeyAlgorithm, int) {
+	pki := cluster.Spec.PKI
+	if pki == nil || pki.KeyAlgorithm == "" && pki.KeySize == 0 {
+		return "", 0
+	}
+
+	algorithm := cmv1.PrivateKeyAlgorithm(pki.KeyAlgorithm)
+	if algorithm == "" {
+		algorithm = cmv1.RSAKeyAlgorithm
+	}
+
+	return algorithm, pki.KeySize
+}
+
+// CADuration returns lifetime of self-signed CAs
+func CADuration(cluster *apiv1.EtcdCluster) time.Duration {
+	if pki := cluster.Spec.PKI; pki != nil && pki.CADuration != nil {
+		return pki.CADuration.Duration
+	}
+
+	return DefaultCADuration
+}
+
+// ClientCertificate applies key and lifetime of client certificates issued by operator
+func ClientCertificate(cert resources.CertificateBuilder, cluster *apiv1.EtcdCluster) {
+	if algorithm, size := PrivateKey(cluster); algorithm != "" {
+		cert.PrivateKey(algorithm, size)
+	}
+	if pki := cluster.Spec.PKI; pki != nil && pki.ClientDuration != nil {
+		cert.Duration(pki.ClientDuration.Duration)
+	}
+}
+
+// FormatIssuer formats issuer reference as sidecar flag value
+func FormatIssuer(ref cmmeta.ObjectReference) string {
+	return ref.Kind + "/" + ref.Name
+}
+
+// sidecarPKIFlags are sidecar flags returned by SidecarPKIArgs
+var sidecarPKIFlags = []string{
+	"--key-algorithm",
+	"--key-size",
+	"--duration",
+	"--renew-before",
+	"--server-issuer",
+	"--peer-issuer",
+	"--ca-bundle",
+	"--common-name",
+}
+
+// SidecarPKIArgs returns sidecar arguments selecting issuers, keys, lifetime, trusted CAs and etcd user of members,
+// issuers are only passed when they differ from the first generation of self-signed CAs
+func SidecarPKIArgs(cluster *apiv1.EtcdCluster) []string {
+	var args []string
+	if algorithm, size := PrivateKey(cluster); algorithm != "" {
+		args = append(args, "--key-algorithm="+string(algorithm))
+		if size != 0 {
+			args = append(args, "--key-size="+strconv.Itoa(size))
+		}
+	}
+	if pki := cluster.Spec.PKI; pki != nil && pki.Duration != nil {
+		args = append(args, "--duration="+pki.Duration.Duration.String())
+	}
+	if pki := cluster.Spec.PKI; pki != nil && pki.RenewBefore != nil {
+		args = append(args, "--renew-before="+pki.RenewBefore.Duration.String())
+	}
+
+	if issuer := ServerIssuer(cluster); issuer != issuerRef(nil, cluster.Name+"-server-ca") {
+		args = append(args, "--server-issuer="+FormatIssuer(issuer))
+	}
+	if issuer := PeerIssuer(cluster); issuer != issuerRef(nil, cluster.Name+"-peer-ca") {
+		args = append(args, "--peer-issuer="+FormatIssuer(issuer))
+	}
+
+	// both generations of CAs are trusted while members are restarted
+	if TrustCABundle(cluster) {
+		args = append(args, "--ca-bundle="+cluster.Name+"-ca-bundle")
+	}
+
+	// members authenticate as root user while auth is enabled
+	if AuthEnabled(cluster) {
+		args = append(args, "--common-name="+RootUser)
+	}
+
+	return args
+}
+
+func caNames(ca string, generation int32) []string {
+	if generation == 0 {
+		return []string{ca}
+	}
+
+	return []string{ca, strconv.Itoa(int(generation))}
+}
+
+func issuerRef(ref *apiv1.IssuerReference, ca string) cmmeta.ObjectReference {
+	if ref == nil {
+		return cmmeta.ObjectReference{
+			Name:  ca,
+			Kind:  cmv1.IssuerKind,
+			Group: cmv1.SchemeGroupVersion.Group,
+		}
+	}
+
+	kind := ref.Kind
+	if kind == "" {
+		kind = cmv1.IssuerKind
+	}
+
+	return cmmeta.ObjectReference{
+		Name:  ref.Name,
+		Kind:  kind,
+		Group: cmv1.SchemeGroupVersion.Group,
+	}
+}
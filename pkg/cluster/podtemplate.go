@@ -0,0 +1,168 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+// MemberPodTemplate merges pod spec overlay of cluster pod template into generated member pod spec
+func MemberPodTemplate(cluster *apiv1.EtcdCluster, spec corev1.PodSpec) corev1.PodSpec {
+	if cluster.Spec.PodTemplate == nil || cluster.Spec.PodTemplate.Spec == nil {
+		return spec
+	}
+
+	// overlay is validated by admission webhook, generated spec is kept when it can not be merged
+	merged, _ := MergePodSpec(spec, *cluster.Spec.PodTemplate.Spec)
+	return merged
+}
+
+// JobPodTemplate merges pod spec overlay of cluster pod template into generated job pod spec,
+// containers of the overlay are only added to members as they would keep job pods running
+func JobPodTemplate(cluster *apiv1.EtcdCluster, spec corev1.PodSpec) corev1.PodSpec {
+	if cluster.Spec.PodTemplate == nil || cluster.Spec.PodTemplate.Spec == nil {
+		return spec
+	}
+
+	overlay := cluster.Spec.PodTemplate.Spec.DeepCopy()
+	overlay.Containers = nil
+	overlay.InitContainers = nil
+
+	merged, _ := MergePodSpec(spec, *overlay)
+	return merged
+}
+
+// ProtectPodSpec removes fields of the overlay overriding fields of generated pod spec, paths of removed fields are returned
+func ProtectPodSpec(spec corev1.PodSpec, overlay *corev1.PodSpec) []string {
+	var protected []string
+
+	containers := map[string]bool{}
+	for _, container := range slices.Concat(spec.InitContainers, spec.Containers) {
+		containers[container.Name] = true
+	}
+
+	overlay.InitContainers = slices.DeleteFunc(overlay.InitContainers, func(container corev1.Container) bool {
+		if containers[container.Name] {
+			protected = append(protected, fmt.Sprintf("initContainers[%s]", container.Name))
+		}
+		return containers[container.Name]
+	})
+
+	overlay.Containers = slices.DeleteFunc(overlay.Containers, func(container corev1.Container) bool {
+		if containers[container.Name] {
+			protected = append(protected, fmt.Sprintf("containers[%s]", container.Name))
+		}
+		return containers[container.Name]
+	})
+
+	volumes := map[string]bool{}
+	for _, volume := range spec.Volumes {
+		volumes[volume.Name] = true
+	}
+
+	overlay.Volumes = slices.DeleteFunc(overlay.Volumes, func(volume corev1.Volume) bool {
+		if volumes[volume.Name] {
+			protected = append(protected, fmt.Sprintf("volumes[%s]", volume.Name))
+		}
+		return volumes[volume.Name]
+	})
+
+	if overlay.RestartPolicy != "" {
+		protected = append(protected, "restartPolicy")
+		overlay.RestartPolicy = ""
+	}
+
+	if overlay.ServiceAccountName != "" {
+		protected = append(protected, "serviceAccountName")
+		overlay.ServiceAccountName = ""
+	}
+
+	// sidecar signals etcd process
+	if overlay.ShareProcessNamespace != nil {
+		protected = append(protected, "shareProcessNamespace")
+		overlay.ShareProcessNamespace = nil
+	}
+
+	return protected
+}
+
+// MergePodSpec applies overlay as strategic merge patch, protected fields of the overlay are ignored
+func MergePodSpec(spec corev1.PodSpec, overlay corev1.PodSpec) (corev1.PodSpec, error) {
+	overlay = *overlay.DeepCopy()
+	ProtectPodSpec(spec, &overlay)
+
+	patch, err := PodSpecPatch(overlay)
+	if err != nil {
+		return spec, err
+	}
+
+	original, err := json.Marshal(spec)
+	if err != nil {
+		return spec, fmt.Errorf("marshal pod spec: %w", err)
+	}
+
+	data, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodSpec{})
+	if err != nil {
+		return spec, fmt.Errorf("merge pod spec: %w", err)
+	}
+
+	merged := corev1.PodSpec{}
+	err = json.Unmarshal(data, &merged)
+	if err != nil {
+		return spec, fmt.Errorf("unmarshal pod spec: %w", err)
+	}
+
+	// generated containers are kept first, etcd remains the default container
+	merged.InitContainers = generatedFirst(spec.InitContainers, merged.InitContainers)
+	merged.Containers = generatedFirst(spec.Containers, merged.Containers)
+
+	return merged, nil
+}
+
+func generatedFirst(generated, merged []corev1.Container) []corev1.Container {
+	names := map[string]bool{}
+	for _, container := range generated {
+		names[container.Name] = true
+	}
+
+	slices.SortStableFunc(merged, func(l, r corev1.Container) int {
+		switch {
+		case names[l.Name] == names[r.Name]:
+			return 0
+		case names[l.Name]:
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	return merged
+}
+
+// PodSpecPatch returns strategic merge patch of pod spec overlay,
+// unset fields that are not omitted by marshalling would otherwise delete generated fields
+func PodSpecPatch(overlay corev1.PodSpec) ([]byte, error) {
+	data, err := json.Marshal(overlay)
+	if err != nil {
+		return nil, fmt.Errorf("marshal pod spec: %w", err)
+	}
+
+	patch := map[string]any{}
+	err = json.Unmarshal(data, &patch)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal pod spec: %w", err)
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(patch, key)
+		}
+	}
+
+	return json.Marshal(patch)
+}
@@ -16,18 +16,23 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
 	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
 	"github.com/agoda-com/etcd-operator/pkg/conditions"
 	"github.com/agoda-com/etcd-operator/pkg/etcd"
@@ -52,11 +57,44 @@ func SetupWithManager(mgr manager.Manager, tlsCache *etcd.TLSCache, config Confi
 		tlsCache: tlsCache,
 		config:   config,
 	}
+
+	// index backups by cluster name
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1.EtcdBackup{}, ClusterNameField, func(obj client.Object) []string {
+		return []string{obj.(*apiv1.EtcdBackup).Spec.ClusterName}
+	})
+	if err != nil {
+		return fmt.Errorf("index backups: %w", err)
+	}
+
+	// backup status changes are reflected in cluster status
+	backupHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		return []reconcile.Request{{
+			NamespacedName: client.ObjectKey{
+				Namespace: obj.GetNamespace(),
+				Name:      obj.(*apiv1.EtcdBackup).Spec.ClusterName,
+			},
+		}}
+	})
+
+	// members request restart slots through pod annotations
+	podHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		key, ok := apiv1.ParseCluster(obj.GetLabels())
+		if !ok {
+			return nil
+		}
+
+		return []reconcile.Request{{NamespacedName: key}}
+	})
+
 	return builder.ControllerManagedBy(mgr).
 		For(&apiv1.EtcdCluster{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
 		Owns(&cmv1.Certificate{}).
 		Owns(&batchv1.CronJob{}).
+		Owns(&corev1.Service{}).
+		Watches(&apiv1.EtcdBackup{}, backupHandler).
+		Watches(&corev1.Pod{}, podHandler, builder.WithPredicates(restartPredicate)).
 		WithOptions(controller.Options{
 			CacheSyncTimeout: 1 * time.Minute,
 			RateLimiter:      rateLimiter,
@@ -67,8 +105,8 @@ func SetupWithManager(mgr manager.Manager, tlsCache *etcd.TLSCache, config Confi
 //+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdclusters/finalizers,verbs=update
-//+kubebuilder:rbac:groups=core,resources=services;configmaps;pods;serviceaccounts;events;secrets,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=create;get;list;patch;update;watch;delete
+//+kubebuilder:rbac:groups=core,resources=services;configmaps;pods;serviceaccounts;events;secrets;persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=create;get;list;patch;update;watch;delete
 //+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=create;get;list;patch;update;watch;delete
 //+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
@@ -79,8 +117,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, cluster *apiv1.EtcdCluster)
 	logger := log.FromContext(ctx)
 	logger.V(3).Info("Reconciling cluster", "name", cluster.Name)
 
-	if cluster.Status.Phase == apiv1.ClusterFailed {
-		return reconcile.Result{}, nil
+	// deletion policy is applied before cluster is garbage collected
+	if !cluster.DeletionTimestamp.IsZero() {
+		return r.ReconcileDeletion(ctx, cluster)
+	}
+
+	err := r.ReconcileFinalizer(ctx, cluster)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile finalizer: %v", err)
 	}
 
 	base := cluster.DeepCopy()
@@ -94,24 +138,118 @@ func (r *Reconciler) Reconcile(ctx context.Context, cluster *apiv1.EtcdCluster)
 	}
 
 	if cluster.Status.Endpoint == "" {
-		cluster.Status.Endpoint = fmt.Sprintf("https://%s.%s.svc.cluster.local:2379", cluster.Name, cluster.Namespace)
+		cluster.Status.Endpoint = fmt.Sprintf("https://%s.%s.svc.%s:2379", cluster.Name, cluster.Namespace, r.config.Domain())
 	}
 
-	err := r.ReconcileResources(ctx, cluster)
+	result, err := r.ReconcileRecovery(ctx, cluster)
 	if err != nil {
-		logger.V(3).Error(err, "reconcile resources")
-		return reconcile.Result{}, fmt.Errorf("reconcile resources: %v", err)
+		logger.V(3).Error(err, "reconcile recovery")
+		return reconcile.Result{}, fmt.Errorf("reconcile recovery: %v", err)
 	}
 
-	err = r.ReconcileStatus(ctx, cluster)
-	if err != nil {
-		logger.V(3).Error(err, "reconcile status")
-		return reconcile.Result{}, fmt.Errorf("reconcile status: %v", err)
+	// failed cluster is left as is until recovery is started
+	restarting, replacing, defragging := false, false, false
+	if cluster.Status.Phase != apiv1.ClusterFailed {
+		err = r.ReconcileUpgrade(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile upgrade")
+			return reconcile.Result{}, fmt.Errorf("reconcile upgrade: %v", err)
+		}
+
+		err = r.ReconcileCARotation(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile ca rotation")
+			return reconcile.Result{}, fmt.Errorf("reconcile ca rotation: %v", err)
+		}
+
+		err = r.ReconcileResources(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile resources")
+			return reconcile.Result{}, fmt.Errorf("reconcile resources: %v", err)
+		}
+
+		err = r.ReconcileStatus(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile status")
+			return reconcile.Result{}, fmt.Errorf("reconcile status: %v", err)
+		}
+
+		restarting, err = r.ReconcileRestarts(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile restarts")
+			return reconcile.Result{}, fmt.Errorf("reconcile restarts: %v", err)
+		}
+
+		err = r.ReconcileAlarms(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile alarms")
+			return reconcile.Result{}, fmt.Errorf("reconcile alarms: %v", err)
+		}
+
+		defragging, err = r.ReconcileDefrag(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile defrag")
+			return reconcile.Result{}, fmt.Errorf("reconcile defrag: %v", err)
+		}
+
+		replacing, err = r.ReconcileConsistency(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile consistency")
+			return reconcile.Result{}, fmt.Errorf("reconcile consistency: %v", err)
+		}
+
+		err = r.ReconcileAuth(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile auth")
+			return reconcile.Result{}, fmt.Errorf("reconcile auth: %v", err)
+		}
+
+		err = r.ReconcileMirror(ctx, cluster)
+		if err != nil {
+			logger.V(3).Error(err, "reconcile mirror")
+			return reconcile.Result{}, fmt.Errorf("reconcile mirror: %v", err)
+		}
+	}
+
+	switch {
+	// poll until members are stopped
+	case cluster.Status.Phase == apiv1.ClusterRestoring:
+		result.RequeueAfter = 5 * time.Second
+	// poll until source cluster can be cloned
+	case CloneSource(cluster) != nil && !conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterRestore):
+		result.RequeueAfter = 30 * time.Second
+	// poll mirror lag of standby cluster
+	case Mirroring(cluster) && cluster.Status.Phase == apiv1.ClusterRunning && result.RequeueAfter == 0:
+		result.RequeueAfter = MirrorPollInterval
+	// poll upgrade and downgrade progress
+	case (conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterUpgrading) || conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterDowngrading)) && result.RequeueAfter == 0:
+		result.RequeueAfter = 30 * time.Second
+	// poll CA rotation progress
+	case conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterCARotation) && result.RequeueAfter == 0:
+		result.RequeueAfter = CARotationPollInterval
+	// poll members waiting for restart slot
+	case restarting && result.RequeueAfter == 0:
+		result.RequeueAfter = RestartPollInterval
+	// poll alarm remediation progress
+	case cluster.Status.Phase == apiv1.ClusterRunning && conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterAlarm) && result.RequeueAfter == 0:
+		result.RequeueAfter = AlarmPollInterval
+	// poll scheduled defrag progress
+	case defragging && result.RequeueAfter == 0:
+		result.RequeueAfter = DefragPollInterval
+	// poll members marked for replacement
+	case replacing && result.RequeueAfter == 0:
+		result.RequeueAfter = ConsistencyPollInterval
+	// poll members and root credentials until auth can be enabled
+	case cluster.Status.Phase == apiv1.ClusterRunning && AuthPending(cluster) && result.RequeueAfter == 0:
+		result.RequeueAfter = AuthPollInterval
+	// schedule the next consistency check or defrag
+	case cluster.Status.Phase == apiv1.ClusterRunning && result.RequeueAfter == 0:
+		result.RequeueAfter = nextSchedule(cluster)
 	}
 
 	// bail if status did not change
 	if reflect.DeepEqual(base.Status, cluster.Status) {
-		return reconcile.Result{}, nil
+		return result, nil
 	}
 
 	patch := client.MergeFrom(base)
@@ -123,7 +261,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, cluster *apiv1.EtcdCluster)
 		logger.V(3).Info("patched cluster status")
 	}
 
-	return reconcile.Result{}, nil
+	return result, nil
 }
 
 func (r *Reconciler) ReconcileResources(ctx context.Context, cluster *apiv1.EtcdCluster) error {
@@ -140,25 +278,51 @@ func (r *Reconciler) ReconcileResources(ctx context.Context, cluster *apiv1.Etcd
 		Label(apiv1.ClusterLabel, clusterLabel)
 
 	// pki
-	b.CA("peer-ca")
-	b.CA("server-ca")
+	PKI(b, cluster)
 
 	secretLabels := map[string]string{
 		apiv1.ClusterLabel: clusterLabel,
 	}
 
-	b.Certificate("user-root").
-		Issuer(cluster.Name, "server-ca").
+	userRoot := b.Certificate("user-root").
+		IssuerRef(ServerIssuer(cluster)).
 		Usages(cmv1.UsageClientAuth).
 		SecretLabels(secretLabels)
+	ClientCertificate(userRoot, cluster)
+	if AuthEnabled(cluster) {
+		userRoot.CommonName(RootUser)
+	}
 
-	deployment, err := Deployment(ctx, b, cluster, r.config)
-	if deployment == nil {
+	// bootstrap waits for the source cluster to be cloned
+	ready, err := r.ReconcileCloneSource(ctx, b, cluster)
+	if !ready || err != nil {
 		return err
 	}
 
-	DefragCronJob(b, cluster, r.config)
+	workload, err := Workload(ctx, b, cluster, r.config)
+	if workload == nil {
+		return err
+	}
+
+	// members deployment is recreated when its selector changed
+	if deployment, ok := workload.(*appsv1.Deployment); ok {
+		ready, err := r.ReconcileMembersSelector(ctx, deployment)
+		if !ready || err != nil {
+			return err
+		}
+	}
+
+	ExternalService(b, cluster)
+	ReadReplicas(b, cluster, r.config)
+	// members are defragmented by operator, defrag cronjob of older versions is deleted
+	b.Delete(&batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name + "-defrag",
+		},
+	})
 	BackupCronJob(b, cluster, r.config)
+	MirrorDeployment(b, cluster, r.config)
 
 	err = b.Apply(ctx, r.kcl)
 	if err != nil {
@@ -170,17 +334,56 @@ func (r *Reconciler) ReconcileResources(ctx context.Context, cluster *apiv1.Etcd
 	return nil
 }
 
-func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdCluster) error {
-	key := client.ObjectKeyFromObject(cluster)
+// ReconcileMembersSelector deletes members deployment with a selector different from desired one, e.g. created
+// before read replicas were excluded. Selector is immutable, deployment is deleted orphaning its replica sets
+// which are adopted by recreated deployment without restarting members. Returns true once selector matches.
+func (r *Reconciler) ReconcileMembersSelector(ctx context.Context, desired *appsv1.Deployment) (bool, error) {
 	deployment := &appsv1.Deployment{}
-	err := r.kcl.Get(ctx, key, deployment)
+	err := r.kcl.Get(ctx, client.ObjectKeyFromObject(desired), deployment)
 	switch {
-	// ignore deployment not found
 	case apierrors.IsNotFound(err):
+		return true, nil
 	case err != nil:
-		return fmt.Errorf("get cluster deployment: %w", err)
-	default:
-		cluster.Status.UpdatedReplicas = deployment.Status.UpdatedReplicas
+		return false, err
+	// deletion event of owned deployment triggers reconcile
+	case !deployment.DeletionTimestamp.IsZero():
+		return false, nil
+	case reflect.DeepEqual(deployment.Spec.Selector, desired.Spec.Selector):
+		return true, nil
+	}
+
+	log.FromContext(ctx).Info("recreate members deployment with new selector", "deployment", deployment.Name)
+
+	err = r.kcl.Delete(ctx, deployment,
+		client.PropagationPolicy(metav1.DeletePropagationOrphan),
+		client.Preconditions{UID: &deployment.UID})
+	return false, client.IgnoreNotFound(err)
+}
+
+func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	key := client.ObjectKeyFromObject(cluster)
+	if cluster.Spec.Storage != nil {
+		statefulSet := &appsv1.StatefulSet{}
+		err := r.kcl.Get(ctx, key, statefulSet)
+		switch {
+		// ignore statefulset not found
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("get cluster statefulset: %w", err)
+		default:
+			cluster.Status.UpdatedReplicas = statefulSet.Status.UpdatedReplicas
+		}
+	} else {
+		deployment := &appsv1.Deployment{}
+		err := r.kcl.Get(ctx, key, deployment)
+		switch {
+		// ignore deployment not found
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("get cluster deployment: %w", err)
+		default:
+			cluster.Status.UpdatedReplicas = deployment.Status.UpdatedReplicas
+		}
 	}
 
 	key = client.ObjectKey{
@@ -188,7 +391,7 @@ func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdClu
 		Name:      cluster.Name + "-backup",
 	}
 	cronJob := &batchv1.CronJob{}
-	err = r.kcl.Get(ctx, key, cronJob)
+	err := r.kcl.Get(ctx, key, cronJob)
 	switch {
 	// cronjob not found - reset backup status
 	case apierrors.IsNotFound(err):
@@ -197,8 +400,42 @@ func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdClu
 		return fmt.Errorf("get backup cronjob: %w", err)
 	default:
 		cluster.Status.Backup = &apiv1.BackupStatus{
-			LastScheduleTime:   cronJob.Status.LastScheduleTime,
-			LastSuccessfulTime: cronJob.Status.LastSuccessfulTime,
+			LastScheduleTime: cronJob.Status.LastScheduleTime,
+		}
+
+		// last successful backup is the latest completed EtcdBackup
+		backups := &apiv1.EtcdBackupList{}
+		err = r.kcl.List(ctx, backups, client.InNamespace(cluster.Namespace), client.MatchingFields{
+			ClusterNameField: cluster.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("list backups: %w", err)
+		}
+
+		for _, backup := range backups.Items {
+			completed := backup.Status.CompletionTime
+			if backup.Status.Phase != apiv1.BackupSucceeded || completed == nil {
+				continue
+			}
+
+			last := cluster.Status.Backup.LastSuccessfulTime
+			if last == nil || last.Before(completed) {
+				cluster.Status.Backup.LastSuccessfulTime = completed
+			}
+		}
+	}
+
+	// external endpoint is known once load balancer is provisioned
+	cluster.Status.ExternalEndpoint = ""
+	if cluster.Spec.Service != nil {
+		service := &corev1.Service{}
+		err = r.kcl.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Name + "-external"}, service)
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("get external service: %w", err)
+		default:
+			cluster.Status.ExternalEndpoint = ExternalEndpoint(cluster, service)
 		}
 	}
 
@@ -210,10 +447,18 @@ func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdClu
 		return fmt.Errorf("get cluster pods: %w", err)
 	}
 
-	cluster.Status.Replicas = int32(len(pods.Items))
+	cluster.Status.Replicas = 0
 	cluster.Status.ReadyReplicas = 0
 
+	// read replicas are reported separately and do not count towards quorum
+	readReplicas := map[string]bool{}
 	for _, pod := range pods.Items {
+		if IsReadReplica(&pod) {
+			readReplicas[pod.Name] = true
+			continue
+		}
+
+		cluster.Status.Replicas++
 		ready := slices.ContainsFunc(pod.Status.Conditions, func(cond corev1.PodCondition) bool {
 			return cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue
 		})
@@ -223,9 +468,15 @@ func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdClu
 	}
 
 	switch {
+	// stop members before bootstrapping from backup
+	case cluster.Status.Phase == apiv1.ClusterRestoring:
+		return r.ReconcileRestore(ctx, cluster)
 	// wait for bootstrap replica to be available
 	case cluster.Status.Phase == apiv1.ClusterBootstrap && cluster.Status.ReadyReplicas == 0:
 		return nil
+	// wait for surviving member to restart as new cluster
+	case cluster.Status.Phase == apiv1.ClusterRunning && cluster.Status.ReadyReplicas == 0 && conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterRecovering):
+		return nil
 	case cluster.Status.Phase == apiv1.ClusterRunning && cluster.Status.ReadyReplicas == 0:
 		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
 			Type:    apiv1.ClusterAvailable,
@@ -269,19 +520,49 @@ func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdClu
 		return nil
 	}
 
+	// last successful and defrag time are carried over, other fields are reported from current responses
+	previous := make(map[string]apiv1.MemberStatus, len(cluster.Status.Members))
+	for _, member := range cluster.Status.Members {
+		previous[member.ID] = member
+	}
+
+	// alarms of all members are listed by any member, previous alarms are kept if listing fails
+	alarms, err := ecl.AlarmList(ctx)
+	if err == nil {
+		cluster.Status.Alarms = MemberAlarms(resp.Members, alarms.Alarms)
+	}
+
 	cluster.Status.LearnerReplicas = 0
+	cluster.Status.ReadReplicas = 0
 	cluster.Status.AvailableReplicas = 0
 
 	// map member list response to status
 	var wg sync.WaitGroup
 	cluster.Status.Members = make([]apiv1.MemberStatus, len(resp.Members))
 	for i, member := range resp.Members {
+		id := apiv1.FormatMemberID(member.ID)
 		status := apiv1.MemberStatus{
-			ID:   apiv1.FormatMemberID(member.ID),
-			Name: member.Name,
+			ID:                 id,
+			Name:               member.Name,
+			IsLearner:          member.IsLearner,
+			LastSuccessfulTime: previous[id].LastSuccessfulTime,
+			LastDefragTime:     previous[id].LastDefragTime,
+		}
+
+		for _, alarm := range cluster.Status.Alarms {
+			if alarm.MemberID == status.ID {
+				status.Alarms = append(status.Alarms, alarm.Alarm)
+			}
 		}
 
 		switch {
+		// read replica serves serializable reads
+		case member.IsLearner && readReplicas[member.Name]:
+			status.Role = apiv1.MemberRoleReadReplica
+			cluster.Status.ReadReplicas++
+			if len(member.ClientURLs) != 0 {
+				status.Endpoint = member.ClientURLs[0]
+			}
 		case member.IsLearner:
 			status.Role = apiv1.MemberRoleLearner
 			cluster.Status.LearnerReplicas++
@@ -302,34 +583,22 @@ func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdClu
 			defer cancel()
 
 			resp, err := ecl.Status(ctx, status.Endpoint)
-			if err != nil {
-				return
-			}
-
-			switch resp.Leader {
-			case resp.Header.MemberId:
-				status.Role = apiv1.MemberRoleLeader
-			default:
-				status.Role = apiv1.MemberRoleMember
-			}
-
-			status.Version = resp.Version
-			status.Available = len(status.Errors) == 0
-			status.Errors = resp.Errors
-
-			status.Size = resource.NewQuantity(resp.DbSize, resource.DecimalSI)
-
-			cluster.Status.Members[i] = status
+			cluster.Status.Members[i] = MemberStatus(status, resp, err)
 		}()
 	}
 	wg.Wait()
 
 	for _, member := range cluster.Status.Members {
-		if member.Available {
+		if member.Available && member.Role != apiv1.MemberRoleReadReplica {
 			cluster.Status.AvailableReplicas++
 		}
 	}
 
+	// cluster runs the lowest version of its members
+	if version := ClusterVersion(cluster.Status.Members); version != "" {
+		cluster.Status.Version = version
+	}
+
 	// sort members by role and name
 	slices.SortFunc(cluster.Status.Members, func(l, r apiv1.MemberStatus) int {
 		if l.Role == r.Role {
@@ -344,6 +613,15 @@ func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdClu
 
 	// bootstrap completed, reconcile resources
 	if cluster.Status.Phase == apiv1.ClusterBootstrap && cluster.Status.AvailableReplicas >= 1 {
+		err = r.CompleteClone(ctx, cluster, pods.Items)
+		if err != nil {
+			return err
+		}
+
+		if cluster.Status.Restore != nil && cluster.Status.Restore.CompletionTime == nil {
+			cluster.Status.Restore.CompletionTime = ptr.To(metav1.Now())
+		}
+
 		cluster.Status.ObservedGeneration = 0
 		r.transition(cluster, apiv1.ClusterRunning)
 		return nil
@@ -372,9 +650,49 @@ func (r *Reconciler) ReconcileStatus(ctx context.Context, cluster *apiv1.EtcdClu
 			Reason: "ClusterAvailable",
 		})
 	}
+
+	// recovery is completed once quorum is available
+	if conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterRecovering) && cluster.Status.AvailableReplicas >= quorum {
+		return r.CompleteRecovery(ctx, cluster)
+	}
+
 	return nil
 }
 
+// MemberStatus fills member status from its status response. Member which does not respond is unavailable
+// with the error and keeps only last successful and defrag time of its previous status.
+func MemberStatus(status apiv1.MemberStatus, resp *etcdv3.StatusResponse, err error) apiv1.MemberStatus {
+	if err != nil {
+		status.Errors = []string{err.Error()}
+		return status
+	}
+
+	switch {
+	case status.Role == apiv1.MemberRoleReadReplica:
+	case resp.Leader == resp.Header.MemberId:
+		status.Role = apiv1.MemberRoleLeader
+	default:
+		status.Role = apiv1.MemberRoleMember
+	}
+
+	status.Version = resp.Version
+	status.Errors = resp.Errors
+	status.Available = len(status.Errors) == 0
+
+	status.Size = resource.NewQuantity(resp.DbSize, resource.DecimalSI)
+	status.SizeInUse = resource.NewQuantity(resp.DbSizeInUse, resource.DecimalSI)
+	status.RaftTerm = resp.RaftTerm
+	status.RaftIndex = resp.RaftIndex
+	status.RaftAppliedIndex = resp.RaftAppliedIndex
+
+	// status is not patched on every reconcile just to refresh the time
+	if last := status.LastSuccessfulTime; last == nil || time.Since(last.Time) >= MemberRefreshInterval {
+		status.LastSuccessfulTime = ptr.To(metav1.Now())
+	}
+
+	return status
+}
+
 func (r *Reconciler) transition(cluster *apiv1.EtcdCluster, phase apiv1.ClusterPhase) {
 	r.recorder.Eventf(cluster, corev1.EventTypeNormal, string(phase), fmt.Sprintf("Transition from %s to %s", cluster.Status.Phase, phase))
 	cluster.Status.Phase = phase
@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+func TestMemberStatus(t *testing.T) {
+	lastSuccessful := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	lastDefrag := metav1.NewTime(time.Now().Add(-2 * time.Hour).Truncate(time.Second))
+	member := apiv1.MemberStatus{
+		ID:                 "1",
+		Name:               "test-cluster-0",
+		Endpoint:           "https://test-cluster-0:2379",
+		LastSuccessfulTime: &lastSuccessful,
+		LastDefragTime:     &lastDefrag,
+	}
+
+	// member which does not respond keeps only times of previous status
+	status := MemberStatus(member, nil, errors.New("context deadline exceeded"))
+	switch {
+	case status.Available:
+		t.Error("expected member not to be available")
+	case len(status.Errors) != 1 || status.Errors[0] != "context deadline exceeded":
+		t.Errorf("expected status error, got %v", status.Errors)
+	case status.Role != "" || status.Version != "" || status.Size != nil || status.RaftIndex != 0:
+		t.Errorf("expected no reported fields, got role %q, version %q, size %v, raft index %d", status.Role, status.Version, status.Size, status.RaftIndex)
+	case !status.LastSuccessfulTime.Equal(&lastSuccessful):
+		t.Errorf("expected last successful time %s, got %s", lastSuccessful, status.LastSuccessfulTime)
+	case !status.LastDefragTime.Equal(&lastDefrag):
+		t.Errorf("expected last defrag time %s, got %s", lastDefrag, status.LastDefragTime)
+	}
+
+	resp := &etcdv3.StatusResponse{
+		Header:    &etcdserverpb.ResponseHeader{MemberId: 1},
+		Leader:    1,
+		Version:   "3.5.7",
+		DbSize:    2048,
+		RaftIndex: 42,
+	}
+
+	status = MemberStatus(member, resp, nil)
+	switch {
+	case !status.Available:
+		t.Errorf("expected member to be available, got errors %v", status.Errors)
+	case status.Role != apiv1.MemberRoleLeader:
+		t.Errorf("expected role %s, got %s", apiv1.MemberRoleLeader, status.Role)
+	case status.Version != "3.5.7" || status.RaftIndex != 42 || status.Size.Value() != 2048:
+		t.Errorf("expected reported fields, got version %q, raft index %d, size %v", status.Version, status.RaftIndex, status.Size)
+	case !status.LastSuccessfulTime.After(lastSuccessful.Time):
+		t.Errorf("expected last successful time to be refreshed, got %s", status.LastSuccessfulTime)
+	}
+
+	// member with active alarm responds with errors and is not available
+	resp.Errors = []string{"memberID:1 alarm:NOSPACE"}
+	status = MemberStatus(member, resp, nil)
+	switch {
+	case status.Available:
+		t.Error("expected member with errors not to be available")
+	case len(status.Errors) != 1:
+		t.Errorf("expected response errors, got %v", status.Errors)
+	case status.Version != "3.5.7":
+		t.Errorf("expected version of responding member, got %q", status.Version)
+	}
+	resp.Errors = nil
+
+	// read replica keeps its role
+	member.Role = apiv1.MemberRoleReadReplica
+	member.LastSuccessfulTime = ptr.To(metav1.Now())
+	status = MemberStatus(member, resp, nil)
+	if status.Role != apiv1.MemberRoleReadReplica {
+		t.Errorf("expected role %s, got %s", apiv1.MemberRoleReadReplica, status.Role)
+	}
+}
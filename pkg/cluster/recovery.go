@@ -0,0 +1,314 @@
+package cluster
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/backup"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+)
+
+// ReconcileRecovery starts recovery of failed cluster, or cluster which lost quorum, according to recovery policy
+// and fails the cluster again when recovery does not complete in time.
+// ForceNewCluster attempts are only started once approved through cluster annotation.
+func (r *Reconciler) ReconcileRecovery(ctx context.Context, cluster *apiv1.EtcdCluster) (reconcile.Result, error) {
+	// recovery in progress
+	cond, ok := conditions.Get(cluster.Status.Conditions, apiv1.ClusterRecovering)
+	if ok && cond.Status == corev1.ConditionTrue {
+		if time.Since(cond.LastTransitionTime.Time) < RecoveryTimeout {
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+
+		err := r.clearForceNewCluster(ctx, cluster)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		message := fmt.Sprintf("cluster did not recover in %s", RecoveryTimeout)
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "RecoveryTimeout", message)
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterRecovering,
+			Status:  corev1.ConditionFalse,
+			Reason:  "RecoveryTimeout",
+			Message: message,
+		})
+		r.transition(cluster, apiv1.ClusterFailed)
+
+		return reconcile.Result{}, nil
+	}
+
+	recovery := cluster.Spec.Recovery
+	if recovery == nil || recovery.Policy == "" || recovery.Policy == apiv1.RecoveryNone {
+		return reconcile.Result{}, nil
+	}
+
+	// surviving member can restore quorum of running cluster
+	if cluster.Status.Phase == apiv1.ClusterRunning && recovery.Policy == apiv1.RecoveryForceNewCluster {
+		cond, ok := conditions.Get(cluster.Status.Conditions, apiv1.ClusterAvailable)
+		if !ok || cond.Status != corev1.ConditionFalse || cond.Reason != "NoQuorum" {
+			recovering, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterRecovering)
+			if recovering.Reason == "ApprovalRequired" {
+				conditions.Clear(&cluster.Status.Conditions, apiv1.ClusterRecovering)
+			}
+
+			// approval is not kept for the next quorum loss
+			return reconcile.Result{}, r.clearApproval(ctx, cluster)
+		}
+	} else if cluster.Status.Phase != apiv1.ClusterFailed {
+		return reconcile.Result{}, nil
+	}
+
+	maxAttempts := RecoveryMaxAttempts
+	if recovery.MaxAttempts != nil {
+		maxAttempts = *recovery.MaxAttempts
+	}
+
+	if cluster.Status.RecoveryAttempts >= maxAttempts {
+		r.recoveryFailed(cluster, "MaxAttemptsExceeded", fmt.Sprintf("cluster was not recovered after %d attempts", cluster.Status.RecoveryAttempts))
+		return reconcile.Result{}, nil
+	}
+
+	attempt := cluster.Status.RecoveryAttempts + 1
+
+	switch recovery.Policy {
+	case apiv1.RecoveryRestoreLatestBackup:
+		if len(r.config.BackupEnv) == 0 {
+			r.recoveryFailed(cluster, "BackupNotConfigured", "backup is not configured")
+			return reconcile.Result{}, nil
+		}
+
+		scl, err := backup.NewClient(ctx)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		prefix := path.Join(cluster.Namespace, cluster.Name)
+		obj, err := backup.LatestBackup(ctx, scl, r.config.BackupEnv["AWS_BUCKET_NAME"], prefix)
+		switch {
+		case err != nil:
+			return reconcile.Result{}, fmt.Errorf("latest backup: %w", err)
+		case obj == nil || obj.Key == nil:
+			r.recoveryFailed(cluster, "BackupNotFound", fmt.Sprintf("no backup object with prefix %q", prefix))
+			return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+		}
+
+		// stop members and bootstrap from latest backup
+		cluster.Status.Restore = &apiv1.RestoreStatus{
+			Key:       *obj.Key,
+			StartTime: ptr.To(metav1.Now()),
+		}
+		r.recovering(cluster, attempt, maxAttempts, fmt.Sprintf("restoring from backup %q", *obj.Key))
+		r.transition(cluster, apiv1.ClusterRestoring)
+	case apiv1.RecoveryForceNewCluster:
+		// writes which were not replicated to the surviving member are lost,
+		// partitioned cluster could also regain quorum on its own
+		if !metav1.HasAnnotation(cluster.ObjectMeta, apiv1.ForceNewClusterApprovedAnnotation) {
+			r.recoveryFailed(cluster, "ApprovalRequired", fmt.Sprintf("cluster has no quorum, annotate cluster with %s to restart surviving member as new cluster", apiv1.ForceNewClusterApprovedAnnotation))
+			return reconcile.Result{}, nil
+		}
+
+		pod, err := r.survivingMember(ctx, cluster)
+		switch {
+		case err != nil:
+			return reconcile.Result{}, err
+		case pod == nil:
+			r.recoveryFailed(cluster, "NoSurvivingMember", "no running member pod found")
+			return reconcile.Result{RequeueAfter: time.Minute}, nil
+		}
+
+		// surviving member is restarted by sidecar as a new single member cluster
+		base := pod.DeepCopy()
+		metav1.SetMetaDataAnnotation(&pod.ObjectMeta, apiv1.ForceNewClusterAnnotation, strconv.Itoa(int(attempt)))
+		err = r.kcl.Patch(ctx, pod, client.MergeFrom(base))
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("annotate pod %q: %w", pod.Name, err)
+		}
+
+		// other members are recreated and join the new cluster as learners
+		err = r.deleteMembers(ctx, cluster, pod.Name)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// every attempt is approved separately
+		err = r.clearApproval(ctx, cluster)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		r.recovering(cluster, attempt, maxAttempts, fmt.Sprintf("restarting member %q as new cluster", pod.Name))
+		if cluster.Status.Phase != apiv1.ClusterRunning {
+			r.transition(cluster, apiv1.ClusterRunning)
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// CompleteRecovery marks recovery as completed once cluster regained quorum
+func (r *Reconciler) CompleteRecovery(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	err := r.clearForceNewCluster(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("cluster recovered after %d attempts", cluster.Status.RecoveryAttempts)
+	r.recorder.Event(cluster, corev1.EventTypeNormal, "Recovered", message)
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterRecovering,
+		Status:  corev1.ConditionFalse,
+		Reason:  "Recovered",
+		Message: message,
+	})
+	cluster.Status.RecoveryAttempts = 0
+
+	return nil
+}
+
+func (r *Reconciler) recovering(cluster *apiv1.EtcdCluster, attempt, maxAttempts int32, message string) {
+	message = fmt.Sprintf("attempt %d of %d: %s", attempt, maxAttempts, message)
+	r.recorder.Event(cluster, corev1.EventTypeNormal, "Recovering", message)
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterRecovering,
+		Status:  corev1.ConditionTrue,
+		Reason:  string(cluster.Spec.Recovery.Policy),
+		Message: message,
+	})
+	cluster.Status.RecoveryAttempts = attempt
+	cluster.Status.ObservedGeneration = 0
+}
+
+func (r *Reconciler) recoveryFailed(cluster *apiv1.EtcdCluster, reason, message string) {
+	// event is recorded only when condition changes
+	changed := conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterRecovering,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if changed {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// survivingMember returns running member pod with the highest observed raft index,
+// the oldest running pod is returned when raft index of none of the members is known
+func (r *Reconciler) survivingMember(ctx context.Context, cluster *apiv1.EtcdCluster) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	err := r.kcl.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list cluster pods: %w", err)
+	}
+
+	// read replicas are learners and can not restart as new cluster
+	pods.Items = slices.DeleteFunc(pods.Items, func(pod corev1.Pod) bool {
+		return pod.Status.Phase != corev1.PodRunning || !pod.DeletionTimestamp.IsZero() || IsReadReplica(&pod)
+	})
+	if len(pods.Items) == 0 {
+		return nil, nil
+	}
+
+	raftIndex := func(pod corev1.Pod) uint64 {
+		i := slices.IndexFunc(cluster.Status.Members, func(member apiv1.MemberStatus) bool {
+			return member.Name == pod.Name
+		})
+		if i == -1 {
+			return 0
+		}
+		return cluster.Status.Members[i].RaftIndex
+	}
+
+	slices.SortFunc(pods.Items, func(l, r corev1.Pod) int {
+		return cmp.Or(
+			cmp.Compare(raftIndex(r), raftIndex(l)),
+			l.CreationTimestamp.Compare(r.CreationTimestamp.Time),
+		)
+	})
+
+	return &pods.Items[0], nil
+}
+
+// deleteMembers deletes member pods except the surviving one
+func (r *Reconciler) deleteMembers(ctx context.Context, cluster *apiv1.EtcdCluster, survivor string) error {
+	pods := &corev1.PodList{}
+	err := r.kcl.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+	})
+	if err != nil {
+		return fmt.Errorf("list cluster pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Name == survivor || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		err = r.kcl.Delete(ctx, &pod)
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete pod %q: %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// clearForceNewCluster removes force new cluster annotation from member pods
+func (r *Reconciler) clearForceNewCluster(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	pods := &corev1.PodList{}
+	err := r.kcl.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+	})
+	if err != nil {
+		return fmt.Errorf("list cluster pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		if _, ok := pod.Annotations[apiv1.ForceNewClusterAnnotation]; !ok {
+			continue
+		}
+
+		base := pod.DeepCopy()
+		delete(pod.Annotations, apiv1.ForceNewClusterAnnotation)
+		err = r.kcl.Patch(ctx, &pod, client.MergeFrom(base))
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("patch pod %q: %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// clearApproval removes force new cluster approval from the cluster
+func (r *Reconciler) clearApproval(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	if !metav1.HasAnnotation(cluster.ObjectMeta, apiv1.ForceNewClusterApprovedAnnotation) {
+		return nil
+	}
+
+	// metadata is patched on a copy so that pending status changes are kept
+	obj := cluster.DeepCopy()
+	base := obj.DeepCopy()
+	delete(obj.Annotations, apiv1.ForceNewClusterApprovedAnnotation)
+	err := r.kcl.Patch(ctx, obj, client.MergeFrom(base))
+	if err != nil {
+		return fmt.Errorf("patch cluster annotations: %w", err)
+	}
+
+	delete(cluster.Annotations, apiv1.ForceNewClusterApprovedAnnotation)
+
+	return nil
+}
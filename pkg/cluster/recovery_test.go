@@ -0,0 +1,417 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+)
+
+func TestReconcileRecovery(t *testing.T) {
+	recovering := func(since time.Duration) apiv1.ClusterCondition {
+		return apiv1.ClusterCondition{
+			Type:               apiv1.ClusterRecovering,
+			Status:             corev1.ConditionTrue,
+			Reason:             string(apiv1.RecoveryRestoreLatestBackup),
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		phase      apiv1.ClusterPhase
+		recovery   *apiv1.RecoverySpec
+		attempts   int32
+		conditions []apiv1.ClusterCondition
+		config     Config
+		expected   apiv1.ClusterPhase
+		status     corev1.ConditionStatus
+		reason     string
+	}{
+		{
+			name:     "no policy",
+			phase:    apiv1.ClusterFailed,
+			expected: apiv1.ClusterFailed,
+		},
+		{
+			name:     "none",
+			phase:    apiv1.ClusterFailed,
+			recovery: &apiv1.RecoverySpec{Policy: apiv1.RecoveryNone},
+			expected: apiv1.ClusterFailed,
+		},
+		{
+			name:     "running",
+			phase:    apiv1.ClusterRunning,
+			recovery: &apiv1.RecoverySpec{Policy: apiv1.RecoveryRestoreLatestBackup},
+			config:   createTestConfig(),
+			expected: apiv1.ClusterRunning,
+		},
+		{
+			name:     "backup not configured",
+			phase:    apiv1.ClusterFailed,
+			recovery: &apiv1.RecoverySpec{Policy: apiv1.RecoveryRestoreLatestBackup},
+			expected: apiv1.ClusterFailed,
+			status:   corev1.ConditionFalse,
+			reason:   "BackupNotConfigured",
+		},
+		{
+			name:     "max attempts exceeded",
+			phase:    apiv1.ClusterFailed,
+			recovery: &apiv1.RecoverySpec{Policy: apiv1.RecoveryRestoreLatestBackup, MaxAttempts: ptr.To(int32(2))},
+			attempts: 2,
+			config:   createTestConfig(),
+			expected: apiv1.ClusterFailed,
+			status:   corev1.ConditionFalse,
+			reason:   "MaxAttemptsExceeded",
+		},
+		{
+			name:       "in progress",
+			phase:      apiv1.ClusterBootstrap,
+			recovery:   &apiv1.RecoverySpec{Policy: apiv1.RecoveryRestoreLatestBackup},
+			attempts:   1,
+			conditions: []apiv1.ClusterCondition{recovering(time.Minute)},
+			expected:   apiv1.ClusterBootstrap,
+			status:     corev1.ConditionTrue,
+			reason:     string(apiv1.RecoveryRestoreLatestBackup),
+		},
+		{
+			name:       "timeout",
+			phase:      apiv1.ClusterBootstrap,
+			recovery:   &apiv1.RecoverySpec{Policy: apiv1.RecoveryRestoreLatestBackup},
+			attempts:   1,
+			conditions: []apiv1.ClusterCondition{recovering(RecoveryTimeout)},
+			expected:   apiv1.ClusterFailed,
+			status:     corev1.ConditionFalse,
+			reason:     "RecoveryTimeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Recovery = tt.recovery
+			cluster.Status.Phase = tt.phase
+			cluster.Status.RecoveryAttempts = tt.attempts
+			cluster.Status.Conditions = tt.conditions
+
+			r := &Reconciler{
+				kcl:      createTestClient(t),
+				recorder: record.NewFakeRecorder(10),
+				config:   tt.config,
+			}
+
+			_, err := r.ReconcileRecovery(t.Context(), cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cluster.Status.Phase != tt.expected {
+				t.Errorf("expected phase %s, got %s", tt.expected, cluster.Status.Phase)
+			}
+
+			cond, ok := conditions.Get(cluster.Status.Conditions, apiv1.ClusterRecovering)
+			switch {
+			case tt.status == "" && ok:
+				t.Errorf("expected no Recovering condition, got %s %s", cond.Status, cond.Reason)
+			case tt.status == "":
+			case !ok:
+				t.Errorf("expected Recovering condition %s %s", tt.status, tt.reason)
+			case cond.Status != tt.status || cond.Reason != tt.reason:
+				t.Errorf("expected Recovering condition %s %s, got %s %s", tt.status, tt.reason, cond.Status, cond.Reason)
+			}
+
+			if cluster.Status.RecoveryAttempts != tt.attempts {
+				t.Errorf("expected %d attempts, got %d", tt.attempts, cluster.Status.RecoveryAttempts)
+			}
+		})
+	}
+}
+
+func TestCompleteRecovery(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Spec.Recovery = &apiv1.RecoverySpec{Policy: apiv1.RecoveryRestoreLatestBackup}
+	cluster.Status.RecoveryAttempts = 2
+	cluster.Status.Conditions = []apiv1.ClusterCondition{{
+		Type:   apiv1.ClusterRecovering,
+		Status: corev1.ConditionTrue,
+		Reason: string(apiv1.RecoveryRestoreLatestBackup),
+	}}
+
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		kcl:      createTestClient(t),
+		recorder: recorder,
+	}
+
+	err := r.CompleteRecovery(t.Context(), cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cond, ok := conditions.Get(cluster.Status.Conditions, apiv1.ClusterRecovering)
+	switch {
+	case !ok:
+		t.Fatal("expected Recovering condition")
+	case cond.Status != corev1.ConditionFalse || cond.Reason != "Recovered":
+		t.Errorf("expected Recovering condition False Recovered, got %s %s", cond.Status, cond.Reason)
+	case cluster.Status.RecoveryAttempts != 0:
+		t.Errorf("expected attempts to be reset, got %d", cluster.Status.RecoveryAttempts)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		t.Log(event)
+	default:
+		t.Error("expected Recovered event")
+	}
+}
+
+func createTestMemberPod(cluster *apiv1.EtcdCluster, name string, age time.Duration, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         cluster.Namespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age).Truncate(time.Second)),
+			Labels: map[string]string{
+				apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func TestSurvivingMember(t *testing.T) {
+	cluster := createTestCluster()
+
+	replica := createTestMemberPod(cluster, "test-cluster-replica-abc", 4*time.Hour, corev1.PodRunning)
+	replica.Labels[apiv1.ReadReplicaLabel] = "true"
+
+	pods := []client.Object{
+		createTestMemberPod(cluster, "test-cluster-abc", 3*time.Hour, corev1.PodRunning),
+		createTestMemberPod(cluster, "test-cluster-def", 2*time.Hour, corev1.PodRunning),
+		createTestMemberPod(cluster, "test-cluster-ghi", 5*time.Hour, corev1.PodPending),
+		replica,
+	}
+
+	tests := []struct {
+		name     string
+		members  []apiv1.MemberStatus
+		expected string
+	}{
+		{
+			name:     "oldest running member",
+			expected: "test-cluster-abc",
+		},
+		{
+			name: "highest raft index",
+			members: []apiv1.MemberStatus{
+				{Name: "test-cluster-abc", RaftIndex: 100},
+				{Name: "test-cluster-def", RaftIndex: 200},
+				{Name: "test-cluster-ghi", RaftIndex: 300},
+				{Name: "test-cluster-replica-abc", RaftIndex: 400},
+			},
+			expected: "test-cluster-def",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster.Status.Members = tt.members
+
+			r := &Reconciler{
+				kcl: createTestClient(t, pods...),
+			}
+
+			pod, err := r.survivingMember(t.Context(), cluster)
+			switch {
+			case err != nil:
+				t.Fatal(err)
+			case pod == nil:
+				t.Fatalf("expected surviving member %s", tt.expected)
+			case pod.Name != tt.expected:
+				t.Errorf("expected surviving member %s, got %s", tt.expected, pod.Name)
+			}
+		})
+	}
+
+	r := &Reconciler{
+		kcl: createTestClient(t, pods[2], replica),
+	}
+
+	pod, err := r.survivingMember(t.Context(), cluster)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case pod != nil:
+		t.Errorf("expected no surviving member, got %s", pod.Name)
+	}
+}
+
+func TestReconcileForceNewCluster(t *testing.T) {
+	noQuorum := apiv1.ClusterCondition{
+		Type:   apiv1.ClusterAvailable,
+		Status: corev1.ConditionFalse,
+		Reason: "NoQuorum",
+	}
+
+	tests := []struct {
+		name      string
+		approved  bool
+		available apiv1.ClusterCondition
+		recovery  []apiv1.ClusterCondition
+		reason    string
+		survivor  string
+		deleted   []string
+	}{
+		{
+			name:      "approval required",
+			available: noQuorum,
+			reason:    "ApprovalRequired",
+		},
+		{
+			name:      "approved",
+			approved:  true,
+			available: noQuorum,
+			reason:    string(apiv1.RecoveryForceNewCluster),
+			survivor:  "test-cluster-abc",
+			deleted:   []string{"test-cluster-def", "test-cluster-ghi"},
+		},
+		{
+			name:     "quorum regained",
+			approved: true,
+			available: apiv1.ClusterCondition{
+				Type:   apiv1.ClusterAvailable,
+				Status: corev1.ConditionTrue,
+				Reason: "Degraded",
+			},
+			recovery: []apiv1.ClusterCondition{{
+				Type:   apiv1.ClusterRecovering,
+				Status: corev1.ConditionFalse,
+				Reason: "ApprovalRequired",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Recovery = &apiv1.RecoverySpec{Policy: apiv1.RecoveryForceNewCluster}
+			cluster.Status.Conditions = append([]apiv1.ClusterCondition{tt.available}, tt.recovery...)
+			cluster.Status.Members = []apiv1.MemberStatus{
+				{Name: "test-cluster-abc", RaftIndex: 200},
+				{Name: "test-cluster-def", RaftIndex: 100},
+			}
+			if tt.approved {
+				metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, apiv1.ForceNewClusterApprovedAnnotation, "true")
+			}
+
+			kcl := createTestClient(t,
+				cluster.DeepCopy(),
+				createTestMemberPod(cluster, "test-cluster-abc", time.Hour, corev1.PodRunning),
+				createTestMemberPod(cluster, "test-cluster-def", time.Hour, corev1.PodRunning),
+				createTestMemberPod(cluster, "test-cluster-ghi", time.Hour, corev1.PodPending),
+			)
+			r := &Reconciler{
+				kcl:      kcl,
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			_, err := r.ReconcileRecovery(t.Context(), cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cond, ok := conditions.Get(cluster.Status.Conditions, apiv1.ClusterRecovering)
+			switch {
+			case tt.reason == "" && ok:
+				t.Errorf("expected no Recovering condition, got %s %s", cond.Status, cond.Reason)
+			case tt.reason != "" && cond.Reason != tt.reason:
+				t.Errorf("expected Recovering reason %s, got %s", tt.reason, cond.Reason)
+			case cluster.Status.Phase != apiv1.ClusterRunning:
+				t.Errorf("expected phase %s, got %s", apiv1.ClusterRunning, cluster.Status.Phase)
+			}
+
+			// approval is consumed by the attempt or by regained quorum
+			current := &apiv1.EtcdCluster{}
+			err = kcl.Get(t.Context(), client.ObjectKeyFromObject(cluster), current)
+			switch {
+			case err != nil:
+				t.Fatal(err)
+			case metav1.HasAnnotation(current.ObjectMeta, apiv1.ForceNewClusterApprovedAnnotation):
+				t.Error("expected approval annotation to be removed")
+			}
+
+			pods := &corev1.PodList{}
+			err = kcl.List(t.Context(), pods)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			remaining := map[string]bool{}
+			for _, pod := range pods.Items {
+				remaining[pod.Name] = true
+
+				_, forced := pod.Annotations[apiv1.ForceNewClusterAnnotation]
+				if forced != (pod.Name == tt.survivor) {
+					t.Errorf("expected force new cluster annotation of %s to be %t", pod.Name, pod.Name == tt.survivor)
+				}
+			}
+
+			for _, name := range tt.deleted {
+				if remaining[name] {
+					t.Errorf("expected member %s to be deleted", name)
+				}
+			}
+
+			if len(remaining)+len(tt.deleted) != 3 {
+				t.Errorf("expected %d members to be deleted, got %d", len(tt.deleted), 3-len(remaining))
+			}
+		})
+	}
+}
+
+func TestRecoveryTimeoutClearsForceNewCluster(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Spec.Recovery = &apiv1.RecoverySpec{Policy: apiv1.RecoveryForceNewCluster}
+	cluster.Status.RecoveryAttempts = 1
+	cluster.Status.Conditions = []apiv1.ClusterCondition{{
+		Type:               apiv1.ClusterRecovering,
+		Status:             corev1.ConditionTrue,
+		Reason:             string(apiv1.RecoveryForceNewCluster),
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-RecoveryTimeout)),
+	}}
+
+	pod := createTestMemberPod(cluster, "test-cluster-abc", time.Hour, corev1.PodRunning)
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, apiv1.ForceNewClusterAnnotation, "1")
+
+	kcl := createTestClient(t, pod)
+	r := &Reconciler{
+		kcl:      kcl,
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	_, err := r.ReconcileRecovery(t.Context(), cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = kcl.Get(t.Context(), client.ObjectKeyFromObject(pod), pod)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case metav1.HasAnnotation(pod.ObjectMeta, apiv1.ForceNewClusterAnnotation):
+		t.Error("expected force new cluster annotation to be removed")
+	case cluster.Status.Phase != apiv1.ClusterFailed:
+		t.Errorf("expected phase %s, got %s", apiv1.ClusterFailed, cluster.Status.Phase)
+	}
+}
@@ -0,0 +1,107 @@
+package cluster
+
+import (
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+const (
+	// MaxReadReplicas is the maximum number of read replicas of cluster
+	MaxReadReplicas = 5
+
+	// MaxLearners leaves one learner slot for members joining next to read replicas
+	MaxLearners = MaxReadReplicas + 1
+)
+
+// ReadReplicaVersion is the first etcd version with configurable learner limit,
+// earlier versions allow a single learner which would block members from joining
+var ReadReplicaVersion = semver.Version{Major: 3, Minor: 6}
+
+// ReadReplicasSupported returns true when etcd version allows learners next to read replicas
+func ReadReplicasSupported(version string) bool {
+	v, err := semver.NewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return false
+	}
+
+	return !v.LessThan(ReadReplicaVersion)
+}
+
+// ReadReplicas builds read replica members and their client service. Read replicas join running cluster
+// as learners and are never promoted, they are not selected by the cluster service.
+func ReadReplicas(builder *resources.Builder, cluster *apiv1.EtcdCluster, config Config) *appsv1.Deployment {
+	if cluster.Spec.ReadReplicas == 0 {
+		builder.Delete(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      cluster.Name + "-replica",
+			},
+		})
+		builder.Delete(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      cluster.Name + "-replica",
+			},
+		})
+
+		return nil
+	}
+
+	clusterLabel := apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster))
+
+	builder.Service("replica").
+		Selector(apiv1.ClusterLabel, clusterLabel).
+		Selector(apiv1.ReadReplicaLabel, "true").
+		Port("etcd-client-ssl", 2379, 2379).
+		Headless(true)
+
+	// learners can only join running cluster
+	replicas := cluster.Spec.ReadReplicas
+	if cluster.Status.Phase != apiv1.ClusterRunning {
+		replicas = 0
+	}
+
+	deployment := builder.Deployment("replica").
+		Replicas(replicas).
+		MaxUnavailable(0).
+		MaxSurge(1).
+		Selector(apiv1.ClusterLabel, clusterLabel).
+		Selector(apiv1.ReadReplicaLabel, "true").
+		PodSpec(ReadReplicaPodSpec(cluster, config))
+
+	if cluster.Spec.PodTemplate != nil {
+		deployment.
+			PodLabels(cluster.Spec.PodTemplate.Labels).
+			PodAnnotations(cluster.Spec.PodTemplate.Annotations)
+	}
+
+	if hash := ConfigHash(cluster); hash != "" {
+		deployment.PodAnnotations(map[string]string{apiv1.ConfigHashAnnotation: hash})
+	}
+
+	return deployment.Deployment
+}
+
+// ReadReplicaPodSpec runs member with ephemeral data regardless of cluster storage,
+// restarted read replica joins as a new learner and catches up from the leader
+func ReadReplicaPodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
+	ephemeral := cluster.DeepCopy()
+	ephemeral.Spec.Storage = nil
+
+	return PodSpec(ephemeral, config)
+}
+
+// IsReadReplica returns true for pods of read replica members
+func IsReadReplica(pod *corev1.Pod) bool {
+	return pod.Labels[apiv1.ReadReplicaLabel] == "true"
+}
@@ -0,0 +1,120 @@
+package cluster
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+const (
+	// RestartSlotTimeout revokes restart slot of member which did not restart etcd in time
+	RestartSlotTimeout = 5 * time.Minute
+	// RestartPollInterval is the interval of checks while members wait for restart slot
+	RestartPollInterval = 10 * time.Second
+)
+
+// restartPredicate passes changes of restart and replace annotations, pending restarts are polled otherwise
+var restartPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		before, after := e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()
+		for _, annotation := range []string{apiv1.RestartRequestedAnnotation, apiv1.RestartGrantedAnnotation, apiv1.ReplaceAnnotation} {
+			if before[annotation] != after[annotation] {
+				return true
+			}
+		}
+
+		return false
+	},
+}
+
+// ReconcileRestarts grants restart slot to a single member at a time once all members are available,
+// members request the slot to reload etcd after their CAs have changed. Returns true while restarts are pending.
+func (r *Reconciler) ReconcileRestarts(ctx context.Context, cluster *apiv1.EtcdCluster) (bool, error) {
+	if cluster.Status.Phase != apiv1.ClusterRunning {
+		return false, nil
+	}
+
+	pods := &corev1.PodList{}
+	err := r.kcl.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+	})
+	if err != nil {
+		return false, fmt.Errorf("list cluster pods: %w", err)
+	}
+
+	requested, granted := RestartRequests(pods.Items)
+	switch {
+	case granted != nil && time.Since(restartTime(granted, apiv1.RestartGrantedAnnotation)) < RestartSlotTimeout:
+		return true, nil
+	case granted != nil:
+		base := granted.DeepCopy()
+		delete(granted.Annotations, apiv1.RestartGrantedAnnotation)
+		err = r.kcl.Patch(ctx, granted, client.MergeFrom(base))
+		if err != nil {
+			return false, fmt.Errorf("revoke restart slot: %w", err)
+		}
+
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "RestartSlotExpired", "Revoked restart slot of member %q after %s", granted.Name, RestartSlotTimeout)
+		return true, nil
+	case len(requested) == 0:
+		return false, nil
+	// restarting a member of degraded cluster could lose quorum
+	case cluster.Status.AvailableReplicas < cluster.Status.Replicas:
+		return true, nil
+	}
+
+	pod := requested[0]
+	base := pod.DeepCopy()
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, apiv1.RestartGrantedAnnotation, time.Now().UTC().Format(time.RFC3339))
+	err = r.kcl.Patch(ctx, pod, client.MergeFrom(base))
+	if err != nil {
+		return false, fmt.Errorf("grant restart slot: %w", err)
+	}
+
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "RestartGranted", "Granted restart slot to member %q", pod.Name)
+
+	return true, nil
+}
+
+// RestartRequests returns pods waiting for restart slot ordered by request time and the pod holding the slot
+func RestartRequests(pods []corev1.Pod) (requested []*corev1.Pod, granted *corev1.Pod) {
+	for i := range pods {
+		pod := &pods[i]
+		switch {
+		case pod.Annotations[apiv1.RestartGrantedAnnotation] != "":
+			granted = pod
+		case pod.Annotations[apiv1.RestartRequestedAnnotation] != "" && pod.DeletionTimestamp.IsZero():
+			requested = append(requested, pod)
+		}
+	}
+
+	// the earliest request is granted first
+	slices.SortFunc(requested, func(l, r *corev1.Pod) int {
+		return cmp.Or(
+			restartTime(l, apiv1.RestartRequestedAnnotation).Compare(restartTime(r, apiv1.RestartRequestedAnnotation)),
+			strings.Compare(l.Name, r.Name),
+		)
+	})
+
+	return requested, granted
+}
+
+func restartTime(pod *corev1.Pod, annotation string) time.Time {
+	t, _ := time.Parse(time.RFC3339, pod.Annotations[annotation])
+	return t
+}
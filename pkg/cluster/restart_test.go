@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+func TestRestartRequests(t *testing.T) {
+	pod := func(name, requested, granted string) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if requested != "" {
+			metav1.SetMetaDataAnnotation(&pod.ObjectMeta, apiv1.RestartRequestedAnnotation, requested)
+		}
+		if granted != "" {
+			metav1.SetMetaDataAnnotation(&pod.ObjectMeta, apiv1.RestartGrantedAnnotation, granted)
+		}
+		return pod
+	}
+
+	deleted := pod("deleted", "2024-01-01T00:00:00Z", "")
+	deleted.DeletionTimestamp = ptr.To(metav1.Now())
+
+	tests := []struct {
+		name      string
+		pods      []corev1.Pod
+		requested []string
+		granted   string
+	}{
+		{
+			name: "none",
+			pods: []corev1.Pod{pod("a", "", ""), pod("b", "", "")},
+		},
+		{
+			name: "earliest first",
+			pods: []corev1.Pod{
+				pod("a", "2024-01-01T00:00:02Z", ""),
+				pod("b", "2024-01-01T00:00:01Z", ""),
+				pod("c", "", ""),
+				pod("d", "2024-01-01T00:00:01Z", ""),
+			},
+			requested: []string{"b", "d", "a"},
+		},
+		{
+			name: "granted",
+			pods: []corev1.Pod{
+				pod("a", "2024-01-01T00:00:02Z", ""),
+				pod("b", "2024-01-01T00:00:01Z", "2024-01-01T00:00:03Z"),
+			},
+			requested: []string{"a"},
+			granted:   "b",
+		},
+		{
+			name:      "deleted",
+			pods:      []corev1.Pod{deleted, pod("a", "2024-01-01T00:00:02Z", "")},
+			requested: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested, granted := RestartRequests(tt.pods)
+
+			var names []string
+			for _, pod := range requested {
+				names = append(names, pod.Name)
+			}
+			if !slices.Equal(names, tt.requested) {
+				t.Errorf("expected requests %v, got %v", tt.requested, names)
+			}
+
+			name := ""
+			if granted != nil {
+				name = granted.Name
+			}
+			if name != tt.granted {
+				t.Errorf("expected granted %q, got %q", tt.granted, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/backup"
+)
+
+var ErrBackupNotFound = errors.New("backup not found")
+
+type RestoreReconciler struct {
+	kcl      client.Client
+	recorder record.EventRecorder
+	config   Config
+}
+
+// SetupRestoreWithManager creates EtcdRestore controller
+func SetupRestoreWithManager(mgr manager.Manager, config Config) error {
+	reconciler := &RestoreReconciler{
+		kcl:      mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("etcdrestore"),
+		config:   config,
+	}
+
+	// index restores by cluster name
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1.EtcdRestore{}, ClusterNameField, func(obj client.Object) []string {
+		return []string{obj.(*apiv1.EtcdRestore).Spec.ClusterName}
+	})
+	if err != nil {
+		return fmt.Errorf("index restores: %w", err)
+	}
+
+	// cluster phase changes progress restores of the cluster
+	clusterHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		restores := &apiv1.EtcdRestoreList{}
+		err := reconciler.kcl.List(ctx, restores, client.InNamespace(obj.GetNamespace()), client.MatchingFields{
+			ClusterNameField: obj.GetName(),
+		})
+		if err != nil {
+			log.FromContext(ctx).Error(err, "list restores")
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(restores.Items))
+		for _, restore := range restores.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&restore),
+			})
+		}
+
+		return requests
+	})
+
+	return builder.ControllerManagedBy(mgr).
+		For(&apiv1.EtcdRestore{}).
+		Watches(&apiv1.EtcdCluster{}, clusterHandler).
+		Complete(reconcile.AsReconciler(mgr.GetClient(), reconciler))
+}
+
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdrestores,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdrestores/status,verbs=get;update;patch
+
+func (r *RestoreReconciler) Reconcile(ctx context.Context, restore *apiv1.EtcdRestore) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(3).Info("Reconciling restore", "name", restore.Name)
+
+	// bail if restore is finished
+	if restore.Status.Phase == apiv1.RestoreSucceeded || restore.Status.Phase == apiv1.RestoreFailed {
+		return reconcile.Result{}, nil
+	}
+
+	base := restore.DeepCopy()
+
+	result, err := r.ReconcileCluster(ctx, restore)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile cluster: %w", err)
+	}
+
+	// bail if status did not change
+	if reflect.DeepEqual(base.Status, restore.Status) {
+		return result, nil
+	}
+
+	patch := client.MergeFrom(base)
+	err = r.kcl.Status().Patch(ctx, restore, patch)
+	switch {
+	case client.IgnoreNotFound(err) != nil:
+		return reconcile.Result{}, fmt.Errorf("patch restore status: %v", err)
+	case err == nil:
+		logger.V(3).Info("patched restore status")
+	}
+
+	return result, nil
+}
+
+func (r *RestoreReconciler) ReconcileCluster(ctx context.Context, restore *apiv1.EtcdRestore) (reconcile.Result, error) {
+	if restore.Status.Phase == "" {
+		restore.Status.Phase = apiv1.RestorePending
+	}
+
+	if len(r.config.BackupEnv) == 0 {
+		r.fail(restore, "BackupNotConfigured", "backup is not configured")
+		return reconcile.Result{}, nil
+	}
+
+	key := client.ObjectKey{
+		Namespace: restore.Namespace,
+		Name:      restore.Spec.ClusterName,
+	}
+	cluster := &apiv1.EtcdCluster{}
+	err := r.kcl.Get(ctx, key, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+		r.fail(restore, "ClusterNotFound", fmt.Sprintf("cluster %q not found", key.Name))
+		return reconcile.Result{}, nil
+	case err != nil:
+		return reconcile.Result{}, fmt.Errorf("get cluster: %w", err)
+	}
+
+	// restore is in progress, wait for cluster to complete bootstrap
+	if restore.Status.Phase == apiv1.RestoreRunning {
+		status := cluster.Status.Restore
+		switch {
+		case status == nil || status.Name != restore.Name:
+			r.fail(restore, "Superseded", "cluster restore was superseded")
+		case status.CompletionTime != nil:
+			restore.Status.Phase = apiv1.RestoreSucceeded
+			restore.Status.CompletionTime = status.CompletionTime
+			r.recorder.Eventf(restore, corev1.EventTypeNormal, string(apiv1.RestoreSucceeded), "Restored cluster %q from %q", cluster.Name, restore.Status.Key)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// determine backup object before members are stopped
+	if restore.Status.Key == "" {
+		restore.Status.Key, err = r.BackupKey(ctx, restore, cluster)
+		switch {
+		case errors.Is(err, ErrBackupNotFound):
+			r.fail(restore, "BackupNotFound", err.Error())
+			return reconcile.Result{}, nil
+		case err != nil:
+			return reconcile.Result{}, err
+		// backup is not finished yet
+		case restore.Status.Key == "":
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+
+	// wait for other restore to complete and for cluster to leave bootstrap
+	inProgress := cluster.Status.Restore != nil && cluster.Status.Restore.CompletionTime == nil
+	if inProgress || (cluster.Status.Phase != apiv1.ClusterRunning && cluster.Status.Phase != apiv1.ClusterFailed) {
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// stop cluster members and bootstrap from backup, pki and services are kept
+	now := metav1.Now()
+	base := cluster.DeepCopy()
+	cluster.Status.Restore = &apiv1.RestoreStatus{
+		Name:      restore.Name,
+		Key:       restore.Status.Key,
+		StartTime: ptr.To(now),
+	}
+	cluster.Status.ObservedGeneration = 0
+	cluster.Status.Phase = apiv1.ClusterRestoring
+
+	err = r.kcl.Status().Patch(ctx, cluster, client.MergeFrom(base))
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("patch cluster status: %w", err)
+	}
+
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, string(apiv1.ClusterRestoring), "Transition from %s to %s", base.Status.Phase, apiv1.ClusterRestoring)
+	r.recorder.Eventf(restore, corev1.EventTypeNormal, string(apiv1.RestoreRunning), "Restoring cluster %q from %q", cluster.Name, restore.Status.Key)
+
+	restore.Status.Phase = apiv1.RestoreRunning
+	restore.Status.StartTime = ptr.To(now)
+
+	return reconcile.Result{}, nil
+}
+
+// BackupKey returns key of the backup object to restore from,
+// empty key is returned while referenced EtcdBackup is not finished
+func (r *RestoreReconciler) BackupKey(ctx context.Context, restore *apiv1.EtcdRestore, cluster *apiv1.EtcdCluster) (string, error) {
+	if restore.Spec.Key != "" {
+		return restore.Spec.Key, nil
+	}
+
+	if restore.Spec.BackupName != "" {
+		key := client.ObjectKey{
+			Namespace: restore.Namespace,
+			Name:      restore.Spec.BackupName,
+		}
+		etcdBackup := &apiv1.EtcdBackup{}
+		err := r.kcl.Get(ctx, key, etcdBackup)
+		switch {
+		case apierrors.IsNotFound(err):
+			return "", fmt.Errorf("%w: EtcdBackup %q does not exist", ErrBackupNotFound, key.Name)
+		case err != nil:
+			return "", fmt.Errorf("get backup: %w", err)
+		}
+
+		switch etcdBackup.Status.Phase {
+		case apiv1.BackupSucceeded:
+			return etcdBackup.Status.Key, nil
+		case apiv1.BackupFailed:
+			return "", fmt.Errorf("%w: EtcdBackup %q has failed", ErrBackupNotFound, key.Name)
+		default:
+			return "", nil
+		}
+	}
+
+	// latest backup of the cluster
+	scl, err := backup.NewClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := path.Join(cluster.Namespace, cluster.Name)
+	obj, err := backup.LatestBackup(ctx, scl, r.config.BackupEnv["AWS_BUCKET_NAME"], prefix)
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("latest backup: %w", err)
+	case obj == nil || obj.Key == nil:
+		return "", fmt.Errorf("%w: no backup object with prefix %q", ErrBackupNotFound, prefix)
+	}
+
+	return *obj.Key, nil
+}
+
+func (r *RestoreReconciler) fail(restore *apiv1.EtcdRestore, reason, message string) {
+	r.recorder.Event(restore, corev1.EventTypeWarning, reason, message)
+	restore.Status.Phase = apiv1.RestoreFailed
+	restore.Status.Reason = reason
+	restore.Status.Message = message
+}
+
+// ReconcileRestore waits for members to be stopped and their volume claims to be deleted,
+// then bootstraps the cluster from backup object
+func (r *Reconciler) ReconcileRestore(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	if cluster.Status.Replicas != 0 {
+		return nil
+	}
+
+	claims := &corev1.PersistentVolumeClaimList{}
+	err := r.kcl.List(ctx, claims, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+	})
+	if err != nil {
+		return fmt.Errorf("list volume claims: %w", err)
+	}
+
+	// member data is discarded
+	for _, claim := range claims.Items {
+		if !claim.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		err = r.kcl.Delete(ctx, &claim)
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete volume claim: %w", err)
+		}
+	}
+
+	if len(claims.Items) != 0 {
+		return nil
+	}
+
+	cluster.Status.Members = nil
+	cluster.Status.ObservedGeneration = 0
+	r.transition(cluster, apiv1.ClusterBootstrap)
+
+	return nil
+}
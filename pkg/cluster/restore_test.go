@@ -0,0 +1,341 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+func createTestRestore() *apiv1.EtcdRestore {
+	return &apiv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-restore",
+			Namespace: "default",
+		},
+		Spec: apiv1.EtcdRestoreSpec{
+			ClusterName: "test-cluster",
+			Key:         "default/test-cluster/backup-1",
+		},
+	}
+}
+
+func TestBackupKey(t *testing.T) {
+	etcdBackup := func(name string, phase apiv1.BackupPhase) *apiv1.EtcdBackup {
+		return &apiv1.EtcdBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Status: apiv1.EtcdBackupStatus{
+				Phase: phase,
+				Key:   "default/test-cluster/" + name,
+			},
+		}
+	}
+
+	kcl := createTestClient(t,
+		etcdBackup("succeeded", apiv1.BackupSucceeded),
+		etcdBackup("failed", apiv1.BackupFailed),
+		etcdBackup("running", apiv1.BackupRunning),
+	)
+
+	tests := []struct {
+		name       string
+		key        string
+		backupName string
+		expected   string
+		err        error
+	}{
+		{
+			name:     "key",
+			key:      "default/test-cluster/manual",
+			expected: "default/test-cluster/manual",
+		},
+		{
+			name:       "succeeded backup",
+			backupName: "succeeded",
+			expected:   "default/test-cluster/succeeded",
+		},
+		{
+			name:       "running backup",
+			backupName: "running",
+		},
+		{
+			name:       "failed backup",
+			backupName: "failed",
+			err:        ErrBackupNotFound,
+		},
+		{
+			name:       "missing backup",
+			backupName: "missing",
+			err:        ErrBackupNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RestoreReconciler{
+				kcl: kcl,
+			}
+
+			restore := createTestRestore()
+			restore.Spec.Key = tt.key
+			restore.Spec.BackupName = tt.backupName
+
+			key, err := r.BackupKey(t.Context(), restore, createTestCluster())
+			switch {
+			case !errors.Is(err, tt.err):
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			case key != tt.expected:
+				t.Errorf("expected key %q, got %q", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestRestoreReconcileCluster(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		objects      func(cluster *apiv1.EtcdCluster, restore *apiv1.EtcdRestore) []client.Object
+		phase        apiv1.RestorePhase
+		reason       string
+		clusterPhase apiv1.ClusterPhase
+	}{
+		{
+			name:   "backup not configured",
+			config: Config{},
+			objects: func(cluster *apiv1.EtcdCluster, _ *apiv1.EtcdRestore) []client.Object {
+				return []client.Object{cluster}
+			},
+			phase:        apiv1.RestoreFailed,
+			reason:       "BackupNotConfigured",
+			clusterPhase: apiv1.ClusterRunning,
+		},
+		{
+			name:   "cluster not found",
+			config: createTestConfig(),
+			objects: func(_ *apiv1.EtcdCluster, _ *apiv1.EtcdRestore) []client.Object {
+				return nil
+			},
+			phase:  apiv1.RestoreFailed,
+			reason: "ClusterNotFound",
+		},
+		{
+			name:   "backup not found",
+			config: createTestConfig(),
+			objects: func(cluster *apiv1.EtcdCluster, restore *apiv1.EtcdRestore) []client.Object {
+				restore.Spec.Key = ""
+				restore.Spec.BackupName = "missing"
+				return []client.Object{cluster}
+			},
+			phase:        apiv1.RestoreFailed,
+			reason:       "BackupNotFound",
+			clusterPhase: apiv1.ClusterRunning,
+		},
+		{
+			name:   "cluster bootstrap",
+			config: createTestConfig(),
+			objects: func(cluster *apiv1.EtcdCluster, _ *apiv1.EtcdRestore) []client.Object {
+				cluster.Status.Phase = apiv1.ClusterBootstrap
+				return []client.Object{cluster}
+			},
+			phase:        apiv1.RestorePending,
+			clusterPhase: apiv1.ClusterBootstrap,
+		},
+		{
+			name:   "start",
+			config: createTestConfig(),
+			objects: func(cluster *apiv1.EtcdCluster, _ *apiv1.EtcdRestore) []client.Object {
+				return []client.Object{cluster}
+			},
+			phase:        apiv1.RestoreRunning,
+			clusterPhase: apiv1.ClusterRestoring,
+		},
+		{
+			name:   "in progress",
+			config: createTestConfig(),
+			objects: func(cluster *apiv1.EtcdCluster, restore *apiv1.EtcdRestore) []client.Object {
+				restore.Status.Phase = apiv1.RestoreRunning
+				restore.Status.Key = restore.Spec.Key
+				cluster.Status.Phase = apiv1.ClusterBootstrap
+				cluster.Status.Restore = &apiv1.RestoreStatus{
+					Name: restore.Name,
+					Key:  restore.Spec.Key,
+				}
+				return []client.Object{cluster}
+			},
+			phase:        apiv1.RestoreRunning,
+			clusterPhase: apiv1.ClusterBootstrap,
+		},
+		{
+			name:   "succeeded",
+			config: createTestConfig(),
+			objects: func(cluster *apiv1.EtcdCluster, restore *apiv1.EtcdRestore) []client.Object {
+				restore.Status.Phase = apiv1.RestoreRunning
+				restore.Status.Key = restore.Spec.Key
+				cluster.Status.Restore = &apiv1.RestoreStatus{
+					Name:           restore.Name,
+					Key:            restore.Spec.Key,
+					CompletionTime: ptr.To(metav1.Now()),
+				}
+				return []client.Object{cluster}
+			},
+			phase:        apiv1.RestoreSucceeded,
+			clusterPhase: apiv1.ClusterRunning,
+		},
+		{
+			name:   "superseded",
+			config: createTestConfig(),
+			objects: func(cluster *apiv1.EtcdCluster, restore *apiv1.EtcdRestore) []client.Object {
+				restore.Status.Phase = apiv1.RestoreRunning
+				restore.Status.Key = restore.Spec.Key
+				cluster.Status.Restore = &apiv1.RestoreStatus{
+					Name: "other-restore",
+					Key:  restore.Spec.Key,
+				}
+				return []client.Object{cluster}
+			},
+			phase:        apiv1.RestoreFailed,
+			reason:       "Superseded",
+			clusterPhase: apiv1.ClusterRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			restore := createTestRestore()
+			kcl := createTestClient(t, tt.objects(cluster, restore)...)
+
+			r := &RestoreReconciler{
+				kcl:      kcl,
+				recorder: record.NewFakeRecorder(10),
+				config:   tt.config,
+			}
+
+			_, err := r.ReconcileCluster(t.Context(), restore)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			switch {
+			case restore.Status.Phase != tt.phase:
+				t.Errorf("expected restore phase %s, got %s", tt.phase, restore.Status.Phase)
+			case restore.Status.Reason != tt.reason:
+				t.Errorf("expected reason %q, got %q", tt.reason, restore.Status.Reason)
+			}
+
+			if tt.clusterPhase == "" {
+				return
+			}
+
+			current := &apiv1.EtcdCluster{}
+			err = kcl.Get(t.Context(), client.ObjectKeyFromObject(cluster), current)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if current.Status.Phase != tt.clusterPhase {
+				t.Errorf("expected cluster phase %s, got %s", tt.clusterPhase, current.Status.Phase)
+			}
+
+			if tt.phase == apiv1.RestoreRunning && tt.clusterPhase == apiv1.ClusterRestoring {
+				switch status := current.Status.Restore; {
+				case status == nil:
+					t.Error("expected cluster restore status")
+				case status.Name != restore.Name || status.Key != restore.Spec.Key:
+					t.Errorf("expected restore %s of %q, got %s of %q", restore.Name, restore.Spec.Key, status.Name, status.Key)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileRestore(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Status.Phase = apiv1.ClusterRestoring
+	cluster.Status.Replicas = 1
+	cluster.Status.Members = []apiv1.MemberStatus{{Name: "test-cluster-0"}}
+
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-test-cluster-0",
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+			},
+		},
+	}
+
+	kcl := createTestClient(t, claim)
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		kcl:      kcl,
+		recorder: recorder,
+	}
+
+	steps := []struct {
+		name     string
+		replicas int32
+		phase    apiv1.ClusterPhase
+		claims   int
+	}{
+		{
+			name:     "wait for members to stop",
+			replicas: 1,
+			phase:    apiv1.ClusterRestoring,
+			claims:   1,
+		},
+		{
+			name:   "delete volume claims",
+			phase:  apiv1.ClusterRestoring,
+			claims: 0,
+		},
+		{
+			name:  "bootstrap",
+			phase: apiv1.ClusterBootstrap,
+		},
+	}
+
+	for _, step := range steps {
+		cluster.Status.Replicas = step.replicas
+
+		err := r.ReconcileRestore(t.Context(), cluster)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		claims := &corev1.PersistentVolumeClaimList{}
+		err = kcl.List(t.Context(), claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case cluster.Status.Phase != step.phase:
+			t.Errorf("%s: expected phase %s, got %s", step.name, step.phase, cluster.Status.Phase)
+		case len(claims.Items) != step.claims:
+			t.Errorf("%s: expected %d volume claims, got %d", step.name, step.claims, len(claims.Items))
+		}
+	}
+
+	if cluster.Status.Members != nil {
+		t.Errorf("expected members to be reset, got %v", cluster.Status.Members)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		t.Log(event)
+	default:
+		t.Error("expected Bootstrap transition event")
+	}
+}
@@ -0,0 +1,446 @@
+package cluster
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+type RoleReconciler struct {
+	kcl      client.Client
+	recorder record.EventRecorder
+	tlsCache *etcd.TLSCache
+}
+
+// SetupRoleWithManager creates EtcdRole controller
+func SetupRoleWithManager(mgr manager.Manager, tlsCache *etcd.TLSCache) error {
+	reconciler := &RoleReconciler{
+		kcl:      mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("etcdrole"),
+		tlsCache: tlsCache,
+	}
+
+	// index roles by cluster name
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1.EtcdRole{}, ClusterNameField, func(obj client.Object) []string {
+		return []string{obj.(*apiv1.EtcdRole).Spec.ClusterName}
+	})
+	if err != nil {
+		return fmt.Errorf("index roles: %w", err)
+	}
+
+	// roles are applied once cluster is running
+	clusterHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		roles := &apiv1.EtcdRoleList{}
+		err := reconciler.kcl.List(ctx, roles, client.InNamespace(obj.GetNamespace()), client.MatchingFields{
+			ClusterNameField: obj.GetName(),
+		})
+		if err != nil {
+			log.FromContext(ctx).Error(err, "list roles")
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(roles.Items))
+		for _, role := range roles.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&role),
+			})
+		}
+
+		return requests
+	})
+
+	return builder.ControllerManagedBy(mgr).
+		For(&apiv1.EtcdRole{}).
+		Watches(&apiv1.EtcdCluster{}, clusterHandler).
+		Complete(reconcile.AsReconciler(mgr.GetClient(), reconciler))
+}
+
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdroles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdroles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdroles/finalizers,verbs=update
+
+func (r *RoleReconciler) Reconcile(ctx context.Context, role *apiv1.EtcdRole) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(3).Info("Reconciling role", "name", role.Name)
+
+	// role is deleted from etcd before finalizer is removed
+	if !role.DeletionTimestamp.IsZero() {
+		err := r.ReconcileDeletion(ctx, role)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("reconcile deletion: %w", err)
+		}
+
+		return reconcile.Result{}, nil
+	}
+
+	err := addAuthFinalizer(ctx, r.kcl, role)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	base := role.DeepCopy()
+
+	err = r.ReconcileRole(ctx, role)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile role: %w", err)
+	}
+
+	// permissions are compared with etcd periodically to revert drift
+	result := reconcile.Result{RequeueAfter: AuthPollInterval}
+	if role.Status.Phase == apiv1.AuthReady {
+		result.RequeueAfter = AuthDriftInterval
+	}
+
+	// bail if status did not change
+	if reflect.DeepEqual(base.Status, role.Status) {
+		return result, nil
+	}
+
+	patch := client.MergeFrom(base)
+	err = r.kcl.Status().Patch(ctx, role, patch)
+	switch {
+	case client.IgnoreNotFound(err) != nil:
+		return reconcile.Result{}, fmt.Errorf("patch role status: %v", err)
+	case err == nil:
+		logger.V(3).Info("patched role status")
+	}
+
+	return result, nil
+}
+
+// ReconcileRole creates etcd role and makes its permissions match the spec,
+// permissions changed outside of the operator are reported as drift
+func (r *RoleReconciler) ReconcileRole(ctx context.Context, role *apiv1.EtcdRole) (err error) {
+	status := &role.Status
+	if status.Phase == "" {
+		status.Phase = apiv1.AuthPending
+	}
+	status.RoleName = RoleName(role)
+
+	key := client.ObjectKey{
+		Namespace: role.Namespace,
+		Name:      role.Spec.ClusterName,
+	}
+	cluster := &apiv1.EtcdCluster{}
+	err = r.kcl.Get(ctx, key, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+		r.pending(role, "ClusterNotFound", fmt.Sprintf("cluster %q not found", key.Name))
+		return nil
+	case err != nil:
+		return fmt.Errorf("get cluster: %w", err)
+	case cluster.Status.Phase != apiv1.ClusterRunning:
+		r.pending(role, "ClusterPending", fmt.Sprintf("cluster %q is not running", key.Name))
+		return nil
+	}
+
+	// single EtcdRole manages etcd role
+	roles := &apiv1.EtcdRoleList{}
+	err = r.kcl.List(ctx, roles, client.InNamespace(role.Namespace), client.MatchingFields{
+		ClusterNameField: key.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("list roles: %w", err)
+	}
+
+	var managing []*apiv1.EtcdRole
+	for i := range roles.Items {
+		if RoleName(&roles.Items[i]) == status.RoleName {
+			managing = append(managing, &roles.Items[i])
+		}
+	}
+	if len(managing) != 0 {
+		if owner := authOwner(managing); owner.UID != role.UID {
+			r.pending(role, "Conflict", fmt.Sprintf("role %q is managed by EtcdRole %q", status.RoleName, owner.Name))
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, 30*time.Second, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		r.pending(role, "NoConnection", err.Error())
+		return nil
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	var current []*authpb.Permission
+	var changes []string
+	resp, err := ecl.RoleGet(ctx, status.RoleName)
+	switch {
+	case errors.Is(err, rpctypes.ErrRoleNotFound):
+		_, err = ecl.RoleAdd(ctx, status.RoleName)
+		if err != nil {
+			r.failed(role, fmt.Sprintf("add role: %v", rpctypes.ErrorDesc(err)))
+			return nil
+		}
+
+		changes = append(changes, "created role")
+		r.recorder.Eventf(role, corev1.EventTypeNormal, "Created", "Created role %q", status.RoleName)
+	case err != nil:
+		r.failed(role, fmt.Sprintf("get role: %v", rpctypes.ErrorDesc(err)))
+		return nil
+	default:
+		current = resp.Perm
+	}
+
+	// standby cluster is read-only, write permissions are granted once it is promoted
+	readOnly := Mirroring(cluster)
+	desired := RolePermissions(role)
+	if readOnly {
+		desired = ReadOnlyPermissions(desired)
+	}
+
+	grant, revoke := DiffPermissions(current, desired)
+	for _, perm := range revoke {
+		_, err = ecl.RoleRevokePermission(ctx, status.RoleName, string(perm.Key), string(perm.RangeEnd))
+		if err != nil {
+			r.failed(role, fmt.Sprintf("revoke %s: %v", FormatPermission(perm), rpctypes.ErrorDesc(err)))
+			return nil
+		}
+	}
+	for _, perm := range grant {
+		_, err = ecl.RoleGrantPermission(ctx, status.RoleName, string(perm.Key), string(perm.RangeEnd), etcdv3.PermissionType(perm.PermType))
+		if err != nil {
+			r.failed(role, fmt.Sprintf("grant %s: %v", FormatPermission(perm), rpctypes.ErrorDesc(err)))
+			return nil
+		}
+	}
+
+	// role applied from unchanged spec in the same mode was modified outside of the operator
+	changes = append(changes, permissionChanges(grant, revoke)...)
+	if len(changes) != 0 && status.Phase == apiv1.AuthReady && status.ObservedGeneration == role.Generation && status.ReadOnly == readOnly {
+		status.Drift = strings.Join(changes, ", ")
+		status.DriftTime = ptr.To(metav1.Now())
+		r.recorder.Eventf(role, corev1.EventTypeWarning, "Drift", "Reverted permissions of role %q: %s", status.RoleName, status.Drift)
+	}
+
+	status.ObservedGeneration = role.Generation
+	status.ReadOnly = readOnly
+	status.Phase = apiv1.AuthReady
+	status.Reason = ""
+	status.Message = ""
+
+	return nil
+}
+
+// ReconcileDeletion deletes etcd role managed by EtcdRole, roles of missing clusters are not deleted
+func (r *RoleReconciler) ReconcileDeletion(ctx context.Context, role *apiv1.EtcdRole) (err error) {
+	if !controllerutil.ContainsFinalizer(role, apiv1.AuthFinalizer) {
+		return nil
+	}
+
+	cluster := &apiv1.EtcdCluster{}
+	err = r.kcl.Get(ctx, client.ObjectKey{Namespace: role.Namespace, Name: role.Spec.ClusterName}, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("get cluster: %w", err)
+	// role of conflicting EtcdRole was never applied
+	case cluster.DeletionTimestamp.IsZero() && role.Status.ObservedGeneration != 0:
+		err = r.deleteRole(ctx, cluster, role)
+		if err != nil {
+			return err
+		}
+	}
+
+	return removeAuthFinalizer(ctx, r.kcl, role)
+}
+
+func (r *RoleReconciler) deleteRole(ctx context.Context, cluster *apiv1.EtcdCluster, role *apiv1.EtcdRole) (err error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, 30*time.Second, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	_, err = ecl.RoleDelete(ctx, RoleName(role))
+	if err != nil && !errors.Is(err, rpctypes.ErrRoleNotFound) {
+		return fmt.Errorf("delete role: %w", err)
+	}
+
+	return nil
+}
+
+// RoleName returns etcd role of EtcdRole
+func RoleName(role *apiv1.EtcdRole) string {
+	if role.Spec.RoleName != "" {
+		return role.Spec.RoleName
+	}
+
+	return role.Name
+}
+
+// RolePermissions returns etcd permissions of EtcdRole sorted by key range,
+// permissions of the same key range are merged
+func RolePermissions(role *apiv1.EtcdRole) []*authpb.Permission {
+	var perms []*authpb.Permission
+	for _, spec := range role.Spec.Permissions {
+		perm := &authpb.Permission{
+			PermType: permissionType(spec.Type),
+			Key:      []byte(spec.Key),
+		}
+
+		switch {
+		case spec.Prefix:
+			perm.RangeEnd = []byte(etcdv3.GetPrefixRangeEnd(spec.Key))
+		// escaped zero byte of plain YAML scalar
+		case spec.RangeEnd == `\0`:
+			perm.RangeEnd = []byte{0}
+		case spec.RangeEnd != "":
+			perm.RangeEnd = []byte(spec.RangeEnd)
+		}
+
+		i := slices.IndexFunc(perms, func(p *authpb.Permission) bool {
+			return sameRange(p, perm)
+		})
+		switch {
+		case i == -1:
+			perms = append(perms, perm)
+		case perms[i].PermType != perm.PermType:
+			perms[i].PermType = authpb.READWRITE
+		}
+	}
+
+	slices.SortFunc(perms, comparePermissions)
+
+	return perms
+}
+
+// ReadOnlyPermissions returns read permissions of standby cluster, write permissions are dropped
+// and read-write permissions are reduced to read
+func ReadOnlyPermissions(perms []*authpb.Permission) []*authpb.Permission {
+	var readOnly []*authpb.Permission
+	for _, perm := range perms {
+		if perm.PermType == authpb.WRITE {
+			continue
+		}
+
+		readOnly = append(readOnly, &authpb.Permission{
+			PermType: authpb.READ,
+			Key:      perm.Key,
+			RangeEnd: perm.RangeEnd,
+		})
+	}
+
+	return readOnly
+}
+
+// DiffPermissions returns permissions to grant and revoke so that current permissions match desired ones,
+// granting a permission replaces the type of existing permission of the same key range
+func DiffPermissions(current, desired []*authpb.Permission) (grant, revoke []*authpb.Permission) {
+	for _, perm := range current {
+		i := slices.IndexFunc(desired, func(p *authpb.Permission) bool {
+			return sameRange(p, perm)
+		})
+		if i == -1 {
+			revoke = append(revoke, perm)
+		}
+	}
+
+	for _, perm := range desired {
+		i := slices.IndexFunc(current, func(p *authpb.Permission) bool {
+			return sameRange(p, perm)
+		})
+		if i == -1 || current[i].PermType != perm.PermType {
+			grant = append(grant, perm)
+		}
+	}
+
+	slices.SortFunc(revoke, comparePermissions)
+
+	return grant, revoke
+}
+
+// FormatPermission formats permission type and key range
+func FormatPermission(perm *authpb.Permission) string {
+	switch {
+	case len(perm.RangeEnd) == 0:
+		return fmt.Sprintf("%s %q", perm.PermType, perm.Key)
+	case bytes.Equal(perm.RangeEnd, []byte{0}):
+		return fmt.Sprintf("%s [%q, end)", perm.PermType, perm.Key)
+	case string(perm.RangeEnd) == etcdv3.GetPrefixRangeEnd(string(perm.Key)):
+		return fmt.Sprintf("%s prefix %q", perm.PermType, perm.Key)
+	}
+
+	return fmt.Sprintf("%s [%q, %q)", perm.PermType, perm.Key, perm.RangeEnd)
+}
+
+func permissionChanges(grant, revoke []*authpb.Permission) []string {
+	var changes []string
+	for _, perm := range grant {
+		changes = append(changes, "granted "+FormatPermission(perm))
+	}
+	for _, perm := range revoke {
+		changes = append(changes, "revoked "+FormatPermission(perm))
+	}
+
+	return changes
+}
+
+func permissionType(tpe apiv1.PermissionType) authpb.Permission_Type {
+	switch tpe {
+	case apiv1.PermissionWrite:
+		return authpb.WRITE
+	case apiv1.PermissionReadWrite:
+		return authpb.READWRITE
+	}
+
+	return authpb.READ
+}
+
+func sameRange(l, r *authpb.Permission) bool {
+	return bytes.Equal(l.Key, r.Key) && bytes.Equal(l.RangeEnd, r.RangeEnd)
+}
+
+func comparePermissions(l, r *authpb.Permission) int {
+	return cmp.Or(bytes.Compare(l.Key, r.Key), bytes.Compare(l.RangeEnd, r.RangeEnd))
+}
+
+func (r *RoleReconciler) pending(role *apiv1.EtcdRole, reason, message string) {
+	role.Status.Phase = apiv1.AuthPending
+	role.Status.Reason = reason
+	role.Status.Message = message
+}
+
+func (r *RoleReconciler) failed(role *apiv1.EtcdRole, message string) {
+	role.Status.Phase = apiv1.AuthFailed
+	role.Status.Reason = "EtcdError"
+	role.Status.Message = message
+}
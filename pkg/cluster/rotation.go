@@ -0,0 +1,404 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+// CARotationPollInterval of rotation steps waiting for certificates and member restarts
+const CARotationPollInterval = 10 * time.Second
+
+// ReconcileCARotation rotates self-signed CAs when requested by spec.pki.rotate or once 4/5 of CA lifetime has passed.
+// New CAs are issued and trusted by all members first, then members are reissued certificates of new CAs
+// and finally previous CAs are dropped. Every step restarts members one at a time by rolling out member pod template.
+func (r *Reconciler) ReconcileCARotation(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	if cluster.Status.Phase != apiv1.ClusterRunning || len(SelfSignedCAs(cluster)) == 0 {
+		return nil
+	}
+
+	if cluster.Status.PKI == nil {
+		cluster.Status.PKI = &apiv1.PKIStatus{
+			Rotate: RotateTrigger(cluster),
+		}
+	}
+
+	generation := cluster.Status.PKI.Generation
+
+	switch CARotationStep(cluster) {
+	case "":
+		return r.startCARotation(ctx, cluster)
+	// members are restarted trusting both generations of CAs
+	case "Issuing":
+		bundle, err := r.caBundle(ctx, cluster, generation, generation+1)
+		if bundle == nil || err != nil {
+			return err
+		}
+
+		b := resources.NewBuilder(cluster).
+			Label("app.kubernetes.io/managed-by", "etcd-operator").
+			Label(apiv1.ClusterLabel, apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)))
+		configMap := b.ConfigMap("ca-bundle")
+		for key, data := range bundle {
+			configMap.Data(key, string(data))
+		}
+
+		err = b.Apply(ctx, r.kcl)
+		if err != nil {
+			return fmt.Errorf("apply ca bundle: %w", err)
+		}
+
+		err = r.trustClients(ctx, cluster, bundle[apiv1.ServerCABundleKey])
+		if err != nil {
+			return err
+		}
+
+		r.caRotation(cluster, "Trusting", fmt.Sprintf("restarting members to trust CAs of generation %d", generation+1))
+	// members are restarted with certificates of new CAs
+	case "Trusting":
+		bundle, err := r.caBundle(ctx, cluster, generation, generation+1)
+		if bundle == nil || err != nil {
+			return err
+		}
+
+		err = r.trustClients(ctx, cluster, bundle[apiv1.ServerCABundleKey])
+		if err != nil {
+			return err
+		}
+
+		restarted, err := r.membersRestarted(ctx, cluster)
+		if !restarted || err != nil {
+			return err
+		}
+
+		cluster.Status.PKI.Generation++
+		r.caRotation(cluster, "Reissuing", fmt.Sprintf("restarting members with certificates of CAs of generation %d", generation+1))
+	// members are restarted trusting only new CAs
+	case "Reissuing":
+		bundle, err := r.caBundle(ctx, cluster, generation-1, generation)
+		if bundle == nil || err != nil {
+			return err
+		}
+
+		// client certificates are reissued before previous CA is dropped
+		err = r.trustClients(ctx, cluster, bundle[apiv1.ServerCABundleKey])
+		if err != nil {
+			return err
+		}
+
+		reissued, err := r.clientsReissued(ctx, cluster)
+		if !reissued || err != nil {
+			return err
+		}
+
+		restarted, err := r.membersRestarted(ctx, cluster)
+		if !restarted || err != nil {
+			return err
+		}
+
+		r.caRotation(cluster, "Dropping", fmt.Sprintf("restarting members to drop CAs of generation %d", generation-1))
+	case "Dropping":
+		bundle, err := r.caBundle(ctx, cluster, generation)
+		if bundle == nil || err != nil {
+			return err
+		}
+
+		err = r.trustClients(ctx, cluster, bundle[apiv1.ServerCABundleKey])
+		if err != nil {
+			return err
+		}
+
+		restarted, err := r.membersRestarted(ctx, cluster)
+		if !restarted || err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("CAs rotated to generation %d", generation)
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "CARotated", message)
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterCARotation,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Rotated",
+			Message: message,
+		})
+
+		// previous CAs are deleted
+		cluster.Status.ObservedGeneration = 0
+	}
+
+	return nil
+}
+
+// RotateTrigger returns value of spec.pki.rotate
+func RotateTrigger(cluster *apiv1.EtcdCluster) string {
+	if cluster.Spec.PKI == nil {
+		return ""
+	}
+
+	return cluster.Spec.PKI.Rotate
+}
+
+// startCARotation observes expiration of signing CAs and starts rotation when requested or CAs are about to expire
+func (r *Reconciler) startCARotation(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	status := cluster.Status.PKI
+
+	// CAs are observed once they are issued
+	bundle, err := r.caBundle(ctx, cluster, status.Generation)
+	if bundle == nil || err != nil {
+		return err
+	}
+
+	var notBefore, notAfter time.Time
+	for _, data := range bundle {
+		cert, err := parseCertificate(data)
+		if err != nil {
+			return fmt.Errorf("parse ca: %w", err)
+		}
+
+		if notAfter.IsZero() || cert.NotAfter.Before(notAfter) {
+			notBefore, notAfter = cert.NotBefore, cert.NotAfter
+		}
+	}
+	status.NotAfter = &metav1.Time{Time: notAfter}
+
+	var message string
+	switch {
+	case RotateTrigger(cluster) != status.Rotate:
+		message = "rotation requested"
+	case time.Now().After(notAfter.Add(-notAfter.Sub(notBefore) / 5)):
+		message = fmt.Sprintf("CAs expire at %s", notAfter.Format(time.RFC3339))
+	default:
+		return nil
+	}
+
+	// rolling upgrade is completed first
+	if conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterUpgrading) || conditions.StatusTrue(cluster.Status.Conditions, apiv1.ClusterDowngrading) {
+		return nil
+	}
+
+	status.Rotate = RotateTrigger(cluster)
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "RotatingCA", "Rotating CAs to generation %d: %s", status.Generation+1, message)
+	r.caRotation(cluster, "Issuing", fmt.Sprintf("issuing CAs of generation %d", status.Generation+1))
+
+	return nil
+}
+
+// caRotation moves rotation to the next step, resources are applied for every step
+func (r *Reconciler) caRotation(cluster *apiv1.EtcdCluster, step, message string) {
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterCARotation,
+		Status:  corev1.ConditionTrue,
+		Reason:  step,
+		Message: message,
+	})
+	cluster.Status.ObservedGeneration = 0
+}
+
+// caBundle returns certificates of self-signed CAs of given generations by bundle key,
+// nil is returned until all CAs are issued
+func (r *Reconciler) caBundle(ctx context.Context, cluster *apiv1.EtcdCluster, generations ...int32) (map[string][]byte, error) {
+	keys := map[string]string{
+		"server-ca": apiv1.ServerCABundleKey,
+		"peer-ca":   apiv1.PeerCABundleKey,
+	}
+
+	bundle := map[string][]byte{}
+	for _, ca := range SelfSignedCAs(cluster) {
+		for _, generation := range generations {
+			secret := &corev1.Secret{}
+			err := r.kcl.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: CAName(cluster, ca, generation)}, secret)
+			switch {
+			case apierrors.IsNotFound(err):
+				return nil, nil
+			case err != nil:
+				return nil, fmt.Errorf("get ca secret: %w", err)
+			}
+
+			data := secret.Data[etcd.DefaultCertFile]
+			if len(data) == 0 {
+				return nil, nil
+			}
+
+			bundle[keys[ca]] = append(bundle[keys[ca]], data...)
+		}
+	}
+
+	return bundle, nil
+}
+
+// trustClients replaces CA of client certificate secrets issued by self-signed server CAs,
+// so that clients trust members signed by both generations of CAs
+func (r *Reconciler) trustClients(ctx context.Context, cluster *apiv1.EtcdCluster, bundle []byte) error {
+	if len(bundle) == 0 {
+		return nil
+	}
+
+	secrets, err := r.clientSecrets(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets {
+		if secret.Data == nil || bytes.Equal(secret.Data[etcd.DefaultCACertFile], bundle) {
+			continue
+		}
+
+		base := secret.DeepCopy()
+		secret.Data[etcd.DefaultCACertFile] = bundle
+		err = r.kcl.Patch(ctx, &secret, client.MergeFrom(base))
+		if err != nil {
+			return fmt.Errorf("patch client secret: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// clientsReissued returns true once root and EtcdClient certificates are issued by signing server CA
+func (r *Reconciler) clientsReissued(ctx context.Context, cluster *apiv1.EtcdCluster) (bool, error) {
+	if !slices.Contains(SelfSignedCAs(cluster), "server-ca") {
+		return true, nil
+	}
+
+	secrets, err := r.clientSecrets(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	issuer := ServerIssuer(cluster).Name
+	root := false
+	for _, secret := range secrets {
+		if secret.Annotations[cmv1.IssuerNameAnnotationKey] != issuer {
+			return false, nil
+		}
+		if secret.Name == cluster.Status.SecretName {
+			root = true
+		}
+	}
+
+	return root, nil
+}
+
+// clientSecrets returns secrets of client certificates issued by self-signed server CAs of any generation
+func (r *Reconciler) clientSecrets(ctx context.Context, cluster *apiv1.EtcdCluster) ([]corev1.Secret, error) {
+	secrets := &corev1.SecretList{}
+	err := r.kcl.List(ctx, secrets, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list client secrets: %w", err)
+	}
+
+	return slices.DeleteFunc(secrets.Items, func(secret corev1.Secret) bool {
+		issuer := secret.Annotations[cmv1.IssuerNameAnnotationKey]
+		return secret.Annotations[cmv1.IssuerKindAnnotationKey] != cmv1.IssuerKind ||
+			issuer != cluster.Name+"-server-ca" && !strings.HasPrefix(issuer, cluster.Name+"-server-ca-")
+	}), nil
+}
+
+// membersRestarted returns true once member workloads have rolled out current pki arguments of sidecar
+// and all members are available
+func (r *Reconciler) membersRestarted(ctx context.Context, cluster *apiv1.EtcdCluster) (bool, error) {
+	if cluster.Status.AvailableReplicas < cluster.Spec.Replicas {
+		return false, nil
+	}
+
+	args := SidecarPKIArgs(cluster)
+	key := client.ObjectKeyFromObject(cluster)
+
+	if cluster.Spec.Storage != nil {
+		statefulSet := &appsv1.StatefulSet{}
+		err := r.kcl.Get(ctx, key, statefulSet)
+		if err != nil {
+			return false, fmt.Errorf("get cluster statefulset: %w", err)
+		}
+
+		replicas := ptr.Deref(statefulSet.Spec.Replicas, 1)
+		rolled := slices.Equal(podTemplatePKIArgs(statefulSet.Spec.Template), args) &&
+			statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+			statefulSet.Status.CurrentRevision == statefulSet.Status.UpdateRevision &&
+			statefulSet.Status.UpdatedReplicas == replicas &&
+			statefulSet.Status.ReadyReplicas == replicas
+		if !rolled {
+			return false, nil
+		}
+	} else {
+		rolled, err := r.deploymentRolled(ctx, key, args)
+		if !rolled || err != nil {
+			return false, err
+		}
+	}
+
+	if cluster.Spec.ReadReplicas != 0 {
+		key.Name = cluster.Name + "-replica"
+		return r.deploymentRolled(ctx, key, args)
+	}
+
+	return true, nil
+}
+
+func (r *Reconciler) deploymentRolled(ctx context.Context, key client.ObjectKey, args []string) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	err := r.kcl.Get(ctx, key, deployment)
+	if err != nil {
+		return false, fmt.Errorf("get deployment %s: %w", key.Name, err)
+	}
+
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	rolled := slices.Equal(podTemplatePKIArgs(deployment.Spec.Template), args) &&
+		deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+
+	return rolled, nil
+}
+
+// podTemplatePKIArgs returns pki arguments of sidecar in pod template
+func podTemplatePKIArgs(template corev1.PodTemplateSpec) []string {
+	var args []string
+	for _, container := range template.Spec.InitContainers {
+		if container.Name != "sidecar" {
+			continue
+		}
+
+		for _, arg := range container.Args {
+			flag, _, _ := strings.Cut(arg, "=")
+			if slices.Contains(sidecarPKIFlags, flag) {
+				args = append(args, arg)
+			}
+		}
+	}
+
+	return args
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
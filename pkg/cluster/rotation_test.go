@@ -0,0 +1,290 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+// applyAsUpdate stores objects applied with server-side apply, which is not supported by fake client
+func applyAsUpdate(ctx context.Context, kcl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return kcl.Patch(ctx, obj, patch, opts...)
+	}
+
+	err := kcl.Create(ctx, obj)
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	current := obj.DeepCopyObject().(client.Object)
+	err = kcl.Get(ctx, client.ObjectKeyFromObject(obj), current)
+	if err != nil {
+		return err
+	}
+
+	obj.SetResourceVersion(current.GetResourceVersion())
+	return kcl.Update(ctx, obj)
+}
+
+func TestReconcileCARotation(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Spec.PKI = &apiv1.PKISpec{Rotate: "2024-05-10"}
+	cluster.Status.ObservedGeneration = 1
+	cluster.Status.AvailableReplicas = 3
+	cluster.Status.PKI = &apiv1.PKIStatus{}
+
+	// certificates of self-signed CAs by generation
+	cas := map[string][]byte{}
+	caSecret := func(ca string, generation int32) *corev1.Secret {
+		name := CAName(cluster, ca, generation)
+		cas[name] = createTestCertificate(t, name)
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: name},
+			Data:       map[string][]byte{etcd.DefaultCertFile: cas[name]},
+		}
+	}
+	clientSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      name,
+				Labels: map[string]string{
+					apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+				},
+				Annotations: map[string]string{
+					cmv1.IssuerKindAnnotationKey: cmv1.IssuerKind,
+					cmv1.IssuerNameAnnotationKey: "test-cluster-server-ca",
+				},
+			},
+			Data: map[string][]byte{etcd.DefaultCACertFile: cas["test-cluster-server-ca"]},
+		}
+	}
+
+	objects := []client.Object{
+		caSecret("server-ca", 0),
+		caSecret("peer-ca", 0),
+		clientSecret(cluster.Status.SecretName),
+		clientSecret("test-cluster-app"),
+		createTestDeployment(cluster.Name, cluster, SidecarPKIArgs(cluster)),
+	}
+	kcl := interceptor.NewClient(createTestClient(t, objects...).(client.WithWatch), interceptor.Funcs{
+		Patch: applyAsUpdate,
+	})
+
+	r := &Reconciler{
+		kcl:      kcl,
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	// deployment rolls out current sidecar arguments, available replicas are reported by member status
+	roll := func(rolled bool) {
+		deployment := &appsv1.Deployment{}
+		err := kcl.Get(t.Context(), client.ObjectKeyFromObject(cluster), deployment)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deployment.Spec.Template.Spec.InitContainers[0].Args = SidecarPKIArgs(cluster)
+		deployment.Generation++
+		err = kcl.Update(t.Context(), deployment)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rolled {
+			deployment.Status.ObservedGeneration = deployment.Generation
+			err = kcl.Status().Update(t.Context(), deployment)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	reissue := func(issuer string) {
+		for _, name := range []string{cluster.Status.SecretName, "test-cluster-app"} {
+			secret := &corev1.Secret{}
+			err := kcl.Get(t.Context(), client.ObjectKey{Namespace: cluster.Namespace, Name: name}, secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			secret.Annotations[cmv1.IssuerNameAnnotationKey] = issuer
+			err = kcl.Update(t.Context(), secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	issue := func() {
+		for _, secret := range []*corev1.Secret{caSecret("server-ca", 1), caSecret("peer-ca", 1)} {
+			err := kcl.Create(t.Context(), secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	concat := func(names ...string) []byte {
+		var bundle []byte
+		for _, name := range names {
+			bundle = append(bundle, cas[name]...)
+		}
+		return bundle
+	}
+
+	steps := []struct {
+		name        string
+		update      func()
+		step        string
+		generations []int32
+		clientCA    []string
+	}{
+		{
+			name:        "requested",
+			step:        "Issuing",
+			generations: []int32{0, 1},
+			clientCA:    []string{"test-cluster-server-ca"},
+		},
+		{
+			name:        "new CAs not issued",
+			step:        "Issuing",
+			generations: []int32{0, 1},
+			clientCA:    []string{"test-cluster-server-ca"},
+		},
+		{
+			name:        "new CAs issued",
+			update:      issue,
+			step:        "Trusting",
+			generations: []int32{0, 1},
+			clientCA:    []string{"test-cluster-server-ca", "test-cluster-server-ca-1"},
+		},
+		{
+			name:        "trusting rollout in progress",
+			update:      func() { roll(false) },
+			step:        "Trusting",
+			generations: []int32{0, 1},
+			clientCA:    []string{"test-cluster-server-ca", "test-cluster-server-ca-1"},
+		},
+		{
+			name: "member unavailable",
+			update: func() {
+				roll(true)
+				cluster.Status.AvailableReplicas = 2
+			},
+			step:        "Trusting",
+			generations: []int32{0, 1},
+			clientCA:    []string{"test-cluster-server-ca", "test-cluster-server-ca-1"},
+		},
+		{
+			name: "trusted",
+			update: func() {
+				cluster.Status.AvailableReplicas = 3
+			},
+			step:        "Reissuing",
+			generations: []int32{1, 0},
+			clientCA:    []string{"test-cluster-server-ca", "test-cluster-server-ca-1"},
+		},
+		{
+			name:        "clients not reissued",
+			update:      func() { roll(true) },
+			step:        "Reissuing",
+			generations: []int32{1, 0},
+			clientCA:    []string{"test-cluster-server-ca", "test-cluster-server-ca-1"},
+		},
+		{
+			name: "reissuing rollout in progress",
+			update: func() {
+				reissue("test-cluster-server-ca-1")
+				roll(false)
+			},
+			step:        "Reissuing",
+			generations: []int32{1, 0},
+			clientCA:    []string{"test-cluster-server-ca", "test-cluster-server-ca-1"},
+		},
+		{
+			name:        "reissued",
+			update:      func() { roll(true) },
+			step:        "Dropping",
+			generations: []int32{1, 0},
+			clientCA:    []string{"test-cluster-server-ca", "test-cluster-server-ca-1"},
+		},
+		{
+			name:        "dropping rollout in progress",
+			update:      func() { roll(false) },
+			step:        "Dropping",
+			generations: []int32{1, 0},
+			clientCA:    []string{"test-cluster-server-ca-1"},
+		},
+		{
+			name:        "rotated",
+			update:      func() { roll(true) },
+			generations: []int32{1},
+			clientCA:    []string{"test-cluster-server-ca-1"},
+		},
+	}
+
+	for _, step := range steps {
+		if step.update != nil {
+			step.update()
+		}
+
+		err := r.ReconcileCARotation(t.Context(), cluster)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		if got := CARotationStep(cluster); got != step.step {
+			t.Errorf("%s: expected step %q, got %q", step.name, step.step, got)
+		}
+		if got := CAGenerations(cluster); !slices.Equal(got, step.generations) {
+			t.Errorf("%s: expected CA generations %v, got %v", step.name, step.generations, got)
+		}
+
+		for _, name := range []string{cluster.Status.SecretName, "test-cluster-app"} {
+			secret := &corev1.Secret{}
+			err = kcl.Get(t.Context(), client.ObjectKey{Namespace: cluster.Namespace, Name: name}, secret)
+			switch {
+			case err != nil:
+				t.Fatal(err)
+			case !bytes.Equal(secret.Data[etcd.DefaultCACertFile], concat(step.clientCA...)):
+				t.Errorf("%s: expected client secret %s to trust %v", step.name, name, step.clientCA)
+			}
+		}
+	}
+
+	cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterCARotation)
+	switch {
+	case cond.Status != corev1.ConditionFalse || cond.Reason != "Rotated":
+		t.Errorf("expected rotated condition, got %s/%s: %s", cond.Status, cond.Reason, cond.Message)
+	case cluster.Status.PKI.Generation != 1:
+		t.Errorf("expected CA generation 1, got %d", cluster.Status.PKI.Generation)
+	case cluster.Status.ObservedGeneration != 0:
+		t.Error("expected resources to be reapplied to delete previous CAs")
+	}
+
+	// both generations of CAs are trusted by members through the bundle
+	configMap := &corev1.ConfigMap{}
+	err := kcl.Get(t.Context(), client.ObjectKey{Namespace: cluster.Namespace, Name: "test-cluster-ca-bundle"}, configMap)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case configMap.Data[apiv1.ServerCABundleKey] != string(concat("test-cluster-server-ca", "test-cluster-server-ca-1")):
+		t.Error("expected server CAs of both generations in bundle")
+	case configMap.Data[apiv1.PeerCABundleKey] != string(concat("test-cluster-peer-ca", "test-cluster-peer-ca-1")):
+		t.Error("expected peer CAs of both generations in bundle")
+	}
+}
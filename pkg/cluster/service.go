@@ -0,0 +1,79 @@
+package cluster
+
+import (
+	"net"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/resources"
+)
+
+// ExternalService builds the service publishing voting members to clients outside of Kubernetes cluster
+func ExternalService(builder *resources.Builder, cluster *apiv1.EtcdCluster) *corev1.Service {
+	spec := cluster.Spec.Service
+	if spec == nil {
+		builder.Delete(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      cluster.Name + "-external",
+			},
+		})
+
+		return nil
+	}
+
+	tpe := spec.Type
+	if tpe == "" {
+		tpe = corev1.ServiceTypeLoadBalancer
+	}
+
+	service := builder.Service("external").
+		Type(tpe).
+		Annotations(spec.Annotations).
+		Selector(apiv1.ClusterLabel, apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster))).
+		Selector(apiv1.LearnerLabel, "false").
+		Port("etcd-client-ssl", 2379, 2379)
+
+	return service.Service
+}
+
+// ExternalEndpoint returns client endpoint of the external service. The first external DNS name is preferred
+// over load balancer address, empty endpoint is returned until the service has an address.
+func ExternalEndpoint(cluster *apiv1.EtcdCluster, service *corev1.Service) string {
+	spec := cluster.Spec.Service
+	if spec == nil || service == nil {
+		return ""
+	}
+
+	host := ""
+	if len(spec.ExternalDNSNames) != 0 {
+		host = spec.ExternalDNSNames[0]
+	}
+
+	port := int32(2379)
+	switch service.Spec.Type {
+	case corev1.ServiceTypeNodePort:
+		port = 0
+		if len(service.Spec.Ports) != 0 {
+			port = service.Spec.Ports[0].NodePort
+		}
+	case corev1.ServiceTypeLoadBalancer:
+		if ingress := service.Status.LoadBalancer.Ingress; host == "" && len(ingress) != 0 {
+			host = ingress[0].Hostname
+			if host == "" {
+				host = ingress[0].IP
+			}
+		}
+	}
+
+	if host == "" || port == 0 {
+		return ""
+	}
+
+	return "https://" + net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
@@ -2,15 +2,20 @@ package cluster
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"maps"
 	"path"
+	"slices"
+	"strconv"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -32,11 +37,20 @@ const (
 	ServerCredentialsDir = "/etc/etcd/pki/server"
 	PeerCredentialsDir   = "/etc/etcd/pki/peer"
 	DataDir              = "/var/lib/etcd/data"
+	CloneCredentialsDir  = "/etc/etcd/clone"
 
 	DefragSchedule = "0 1 * * *" // 1:00 AM every day
 	BackupSchedule = "0 * * * *" // every hour
 	JobTTL         = 24 * time.Hour
 	ActiveDeadline = 5 * time.Minute
+
+	BackupHistoryLimit = int32(24)
+	BackupBackoffLimit = int32(2)
+
+	RecoveryMaxAttempts = int32(3)
+	RecoveryTimeout     = 10 * time.Minute
+
+	PeriodicCompactionRetention = "1h"
 )
 
 var (
@@ -51,9 +65,11 @@ var (
 	}
 )
 
-func Deployment(ctx context.Context, builder *resources.Builder, cluster *apiv1.EtcdCluster, config Config) (*appsv1.Deployment, error) {
+// Workload builds the member workload with its configuration and services,
+// members are run by StatefulSet when persistent storage is configured and by Deployment otherwise.
+func Workload(ctx context.Context, builder *resources.Builder, cluster *apiv1.EtcdCluster, config Config) (client.Object, error) {
 	// restore requested without key - determine latest backup
-	if cluster.Status.Phase == apiv1.ClusterBootstrap && cluster.Spec.Restore != nil && cluster.Spec.Restore.Key == nil {
+	if cluster.Status.Phase == apiv1.ClusterBootstrap && cluster.Status.Restore == nil && cluster.Spec.Restore != nil && cluster.Spec.Restore.Key == nil && cluster.Spec.Restore.FromCluster == nil {
 		prefix := path.Join(cluster.Namespace, cluster.Name)
 		if cluster.Spec.Restore.Prefix != nil {
 			prefix = *cluster.Spec.Restore.Prefix
@@ -97,14 +113,32 @@ func Deployment(ctx context.Context, builder *resources.Builder, cluster *apiv1.
 
 	clusterLabel := apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster))
 
+	// read replicas share cluster label, they are excluded from members disruption budget and selector
 	builder.PodDisruptionBudget().
 		Selector(apiv1.ClusterLabel, clusterLabel).
+		SelectorNotIn(apiv1.ReadReplicaLabel, "true").
 		MaxUnavailable(1)
 
-	// member deployment - bootstrap with single replica
+	// members - bootstrap with single replica
 	replicas := cluster.Spec.Replicas
-	if cluster.Status.Phase == apiv1.ClusterBootstrap {
+	switch cluster.Status.Phase {
+	case apiv1.ClusterBootstrap:
 		replicas = 1
+	// members are stopped before restore
+	case apiv1.ClusterRestoring:
+		replicas = 0
+	}
+
+	// cluster service
+	builder.Service().
+		Selector(apiv1.ClusterLabel, clusterLabel).
+		Selector(apiv1.LearnerLabel, "false").
+		Port("etcd-client-ssl", 2379, 2379).
+		Port("etcd-server-ssl", 2380, 2380).
+		Headless(true)
+
+	if cluster.Spec.Storage != nil {
+		return StatefulSet(builder, cluster, config, replicas), nil
 	}
 
 	deployment := builder.Deployment().
@@ -112,6 +146,7 @@ func Deployment(ctx context.Context, builder *resources.Builder, cluster *apiv1.
 		MaxUnavailable(0).
 		MaxSurge(1).
 		Selector(apiv1.ClusterLabel, clusterLabel).
+		SelectorNotIn(apiv1.ReadReplicaLabel, "true").
 		PodSpec(PodSpec(cluster, config))
 
 	if cluster.Spec.PodTemplate != nil {
@@ -120,15 +155,80 @@ func Deployment(ctx context.Context, builder *resources.Builder, cluster *apiv1.
 			PodAnnotations(cluster.Spec.PodTemplate.Annotations)
 	}
 
-	// cluster service
-	builder.Service().
+	// changes of etcd tuning parameters replace members one at a time
+	if hash := ConfigHash(cluster); hash != "" {
+		deployment.PodAnnotations(map[string]string{apiv1.ConfigHashAnnotation: hash})
+	}
+
+	return deployment.Deployment, nil
+}
+
+// StatefulSet builds members with stable names and a volume claim per member.
+// Members are resolved through the peer service so their identity survives pod recreation.
+func StatefulSet(builder *resources.Builder, cluster *apiv1.EtcdCluster, config Config, replicas int32) *appsv1.StatefulSet {
+	clusterLabel := apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster))
+
+	// peer service publishing every member including learners and not ready pods
+	peerService := builder.Service("peer").
 		Selector(apiv1.ClusterLabel, clusterLabel).
-		Selector(apiv1.LearnerLabel, "false").
-		Port("etcd-client-ssl", 2379, 2379).
 		Port("etcd-server-ssl", 2380, 2380).
 		Headless(true)
 
-	return deployment.Deployment, nil
+	// all members have to be started at once to restore quorum after full cluster restart,
+	// claims of removed members are deleted so that scaled up members join with empty data dir
+	statefulSet := builder.StatefulSet().
+		Replicas(replicas).
+		ServiceName(peerService.Name).
+		PodManagementPolicy(appsv1.ParallelPodManagement).
+		RetentionPolicy(appsv1.RetainPersistentVolumeClaimRetentionPolicyType, appsv1.DeletePersistentVolumeClaimRetentionPolicyType).
+		Selector(apiv1.ClusterLabel, clusterLabel).
+		PodSpec(PodSpec(cluster, config)).
+		VolumeClaim(DataVolumeClaim(cluster))
+
+	if cluster.Spec.PodTemplate != nil {
+		statefulSet.
+			PodLabels(cluster.Spec.PodTemplate.Labels).
+			PodAnnotations(cluster.Spec.PodTemplate.Annotations)
+	}
+
+	// changes of etcd tuning parameters replace members one at a time
+	if hash := ConfigHash(cluster); hash != "" {
+		statefulSet.PodAnnotations(map[string]string{apiv1.ConfigHashAnnotation: hash})
+	}
+
+	return statefulSet.StatefulSet
+}
+
+func DataVolumeClaim(cluster *apiv1.EtcdCluster) corev1.PersistentVolumeClaim {
+	storage := cluster.Spec.Storage
+
+	size := StorageQuota(cluster)
+	if storage.Size != nil {
+		size = *storage.Size
+	}
+
+	accessModes := storage.AccessModes
+	if len(accessModes) == 0 {
+		accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	}
+
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "data",
+			Labels: map[string]string{
+				apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: storage.StorageClassName,
+			AccessModes:      accessModes,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
 }
 
 func PodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
@@ -184,14 +284,18 @@ func PodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
 		},
-		{
+	}
+
+	// persistent data volume is provided by statefulset volume claim
+	if cluster.Spec.Storage == nil {
+		volumes = append(volumes, corev1.Volume{
 			Name: "data",
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{
 					SizeLimit: ptr.To(storageQuota),
 				},
 			},
-		},
+		})
 	}
 
 	initContainters := []corev1.Container{
@@ -200,7 +304,7 @@ func PodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
 
 	containers := []corev1.Container{{
 		Name:  "etcd",
-		Image: config.Image + ":" + cluster.Spec.Version,
+		Image: config.Image + ":" + MemberVersion(cluster),
 		Command: []string{
 			"etcd",
 			"--config-file=" + ConfigFile,
@@ -273,14 +377,28 @@ func PodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
 		initContainters = append(initContainters, *container)
 	}
 
-	return corev1.PodSpec{
-		InitContainers:     initContainters,
-		Containers:         containers,
-		Affinity:           affinity,
-		Volumes:            volumes,
-		ServiceAccountName: cluster.Name,
-		PriorityClassName:  config.PriorityClassName,
+	// credentials of the source cluster copied by the controller
+	if CloneSource(cluster) != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: "clone-source",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cluster.Name + "-clone-source",
+				},
+			},
+		})
 	}
+
+	return MemberPodTemplate(cluster, corev1.PodSpec{
+		// sidecar restarts etcd process
+		ShareProcessNamespace: ptr.To(true),
+		InitContainers:        initContainters,
+		Containers:            containers,
+		Affinity:              affinity,
+		Volumes:               volumes,
+		ServiceAccountName:    cluster.Name,
+		PriorityClassName:     config.PriorityClassName,
+	})
 }
 
 func ETCDConfig(cluster *apiv1.EtcdCluster, c Config) ([]byte, error) {
@@ -320,6 +438,15 @@ func ETCDConfig(cluster *apiv1.EtcdCluster, c Config) ([]byte, error) {
 		ExpWatchProgressNotifyInterval: 5 * time.Second,
 	}
 
+	// learner limit leaves room for read replicas
+	if ReadReplicasSupported(MemberVersion(cluster)) {
+		etcdConfig.MaxLearners = MaxLearners
+	}
+
+	if spec := cluster.Spec.Etcd; spec != nil {
+		TuneConfig(&etcdConfig, spec)
+	}
+
 	data, err := json.MarshalIndent(etcdConfig, "", "\t")
 	if err != nil {
 		return nil, fmt.Errorf("marshal config: %w", err)
@@ -328,18 +455,98 @@ func ETCDConfig(cluster *apiv1.EtcdCluster, c Config) ([]byte, error) {
 	return data, nil
 }
 
+// TuneConfig overrides etcd config defaults with tuning parameters of cluster spec
+func TuneConfig(etcdConfig *etcd.Config, spec *apiv1.EtcdSpec) {
+	if spec.HeartbeatInterval != nil {
+		etcdConfig.HeartbeatInterval = spec.HeartbeatInterval.Milliseconds()
+	}
+	if spec.ElectionTimeout != nil {
+		etcdConfig.ElectionTimeout = spec.ElectionTimeout.Milliseconds()
+	}
+	if spec.SnapshotCount != nil {
+		etcdConfig.SnapshotCount = *spec.SnapshotCount
+	}
+	if spec.AutoCompactionMode != nil {
+		etcdConfig.AutoCompactionMode = string(*spec.AutoCompactionMode)
+		// revision retention default does not apply to periodic mode
+		if *spec.AutoCompactionMode == apiv1.CompactionPeriodic {
+			etcdConfig.AutoCompactionRetention = PeriodicCompactionRetention
+		}
+	}
+	if spec.AutoCompactionRetention != nil {
+		etcdConfig.AutoCompactionRetention = *spec.AutoCompactionRetention
+	}
+	if spec.MaxRequestBytes != nil {
+		etcdConfig.MaxRequestBytes = spec.MaxRequestBytes.Value()
+	}
+	if keepAlive := spec.GRPCKeepAlive; keepAlive != nil {
+		if keepAlive.MinTime != nil {
+			etcdConfig.GRPCKeepAliveMinTime = keepAlive.MinTime.Duration
+		}
+		if keepAlive.Interval != nil {
+			etcdConfig.GRPCKeepAliveInterval = keepAlive.Interval.Duration
+		}
+		if keepAlive.Timeout != nil {
+			etcdConfig.GRPCKeepAliveTimeout = keepAlive.Timeout.Duration
+		}
+	}
+	if spec.LogLevel != nil {
+		etcdConfig.LogLevel = etcd.LogLevel(*spec.LogLevel)
+	}
+	etcdConfig.EnablePProf = spec.EnablePProf
+}
+
+// ConfigHash returns hash of etcd tuning parameters, empty when cluster spec does not tune etcd
+func ConfigHash(cluster *apiv1.EtcdCluster) string {
+	if cluster.Spec.Etcd == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(cluster.Spec.Etcd)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
 func SidecarContainer(cluster *apiv1.EtcdCluster, config Config) corev1.Container {
+	args := []string{
+		"--base-config=" + BaseConfigFile,
+		"--config=" + ConfigFile,
+		"--endpoint=" + cluster.Status.Endpoint,
+		"--health-address=:8081",
+	}
+
+	// member data survives pod restarts
+	if cluster.Spec.Storage != nil {
+		args = append(args, "--persistent")
+	}
+
+	// certificates are signed by issuers referenced in cluster spec or by rotated CAs
+	args = append(args, SidecarPKIArgs(cluster)...)
+
+	if config.Domain() != DefaultClusterDomain {
+		args = append(args, "--cluster-domain="+config.Domain())
+	}
+
+	// additional names of server certificate
+	if service := cluster.Spec.Service; service != nil {
+		for _, name := range slices.Concat(service.ExternalDNSNames, service.DNSNames) {
+			args = append(args, "--dns-name="+name)
+		}
+		for _, ip := range service.IPAddresses {
+			args = append(args, "--ip-address="+ip)
+		}
+	}
+
 	return corev1.Container{
 		Name:          "sidecar",
 		Image:         config.ControllerImage,
 		RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways),
 		Command:       []string{"etcd-sidecar"},
-		Args: []string{
-			"--base-config=" + BaseConfigFile,
-			"--config=" + ConfigFile,
-			"--endpoint=" + cluster.Status.Endpoint,
-			"--health-address=:8081",
-		},
+		Args:          args,
 		Env: []corev1.EnvVar{
 			{
 				Name: "POD_NAMESPACE",
@@ -374,6 +581,11 @@ func SidecarContainer(cluster *apiv1.EtcdCluster, config Config) corev1.Containe
 				MountPath: CredentialsDir,
 				ReadOnly:  false,
 			},
+			{
+				Name:      "data",
+				MountPath: path.Dir(DataDir),
+				ReadOnly:  false,
+			},
 		},
 		Resources: corev1.ResourceRequirements{
 			Requests: InitResources,
@@ -394,8 +606,21 @@ func SidecarContainer(cluster *apiv1.EtcdCluster, config Config) corev1.Containe
 }
 
 func RestoreContainer(cluster *apiv1.EtcdCluster, config Config) *corev1.Container {
+	if source := CloneSource(cluster); source != nil {
+		return CloneContainer(cluster, config, *source)
+	}
+
+	restore := cluster.Spec.Restore
+
+	// in-place restore requested by EtcdRestore
+	if cluster.Status.Restore != nil && cluster.Status.Restore.CompletionTime == nil {
+		restore = &apiv1.RestoreSpec{
+			Key: ptr.To(cluster.Status.Restore.Key),
+		}
+	}
+
 	// restore requested but backup credentials are not configured
-	if cluster.Spec.Restore != nil && len(config.BackupEnv) == 0 {
+	if restore != nil && len(config.BackupEnv) == 0 {
 		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
 			Type:    apiv1.ClusterRestore,
 			Status:  corev1.ConditionFalse,
@@ -405,7 +630,7 @@ func RestoreContainer(cluster *apiv1.EtcdCluster, config Config) *corev1.Contain
 		return nil
 	}
 
-	if cluster.Status.Phase != apiv1.ClusterBootstrap || cluster.Spec.Restore == nil || cluster.Spec.Restore.Key == nil {
+	if cluster.Status.Phase != apiv1.ClusterBootstrap || restore == nil || restore.Key == nil {
 		return nil
 	}
 
@@ -413,7 +638,7 @@ func RestoreContainer(cluster *apiv1.EtcdCluster, config Config) *corev1.Contain
 		Type:    apiv1.ClusterRestore,
 		Status:  corev1.ConditionTrue,
 		Reason:  "BackupFound",
-		Message: fmt.Sprintf("using backup object %q", *cluster.Spec.Restore.Key),
+		Message: fmt.Sprintf("using backup object %q", *restore.Key),
 	})
 
 	return &corev1.Container{
@@ -423,7 +648,7 @@ func RestoreContainer(cluster *apiv1.EtcdCluster, config Config) *corev1.Contain
 		Args: []string{
 			"restore",
 			"--config=" + ConfigFile,
-			"--key=" + *cluster.Spec.Restore.Key,
+			"--key=" + *restore.Key,
 		},
 		EnvFrom: []corev1.EnvFromSource{{
 			SecretRef: &corev1.SecretEnvSource{
@@ -451,8 +676,84 @@ func RestoreContainer(cluster *apiv1.EtcdCluster, config Config) *corev1.Contain
 	}
 }
 
-func BackupPodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
-	prefix := path.Join(cluster.Namespace, cluster.Name)
+// CloneSource returns the cluster to clone while cluster is bootstrapped, in-place restore takes precedence
+func CloneSource(cluster *apiv1.EtcdCluster) *client.ObjectKey {
+	restore := cluster.Spec.Restore
+	if cluster.Status.Phase != apiv1.ClusterBootstrap || cluster.Status.Restore != nil || restore == nil || restore.FromCluster == nil {
+		return nil
+	}
+
+	key := client.ObjectKey{
+		Namespace: restore.FromCluster.Namespace,
+		Name:      restore.FromCluster.Name,
+	}
+	if key.Namespace == "" {
+		key.Namespace = cluster.Namespace
+	}
+
+	return &key
+}
+
+// CloneContainer streams snapshot from the source cluster into the data dir,
+// endpoint and credentials of the source are read from the secret copied by the controller.
+// Snapshot revision is reported using container termination message.
+func CloneContainer(cluster *apiv1.EtcdCluster, config Config, source client.ObjectKey) *corev1.Container {
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterRestore,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Cloning",
+		Message: fmt.Sprintf("cloning from cluster %s", source),
+	})
+
+	return &corev1.Container{
+		Name:    "restore",
+		Image:   config.ControllerImage,
+		Command: []string{"etcd-tools"},
+		Args: []string{
+			"restore",
+			"--config=" + ConfigFile,
+			"--endpoint=$(SOURCE_ENDPOINT)",
+			"--credentials-dir=" + CloneCredentialsDir,
+			"--result-file=" + corev1.TerminationMessagePathDefault,
+		},
+		Env: []corev1.EnvVar{{
+			Name: "SOURCE_ENDPOINT",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cluster.Name + "-clone-source",
+					},
+					Key: CloneEndpointKey,
+				},
+			},
+		}},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "config",
+				MountPath: path.Dir(ConfigFile),
+				ReadOnly:  true,
+			},
+			{
+				Name:      "data",
+				MountPath: path.Dir(DataDir),
+				ReadOnly:  false,
+			},
+			{
+				Name:      "clone-source",
+				MountPath: CloneCredentialsDir,
+				ReadOnly:  true,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: InitResources,
+			Limits:   InitResources,
+		},
+	}
+}
+
+// BackupPodSpec runs backup of the cluster into the provided object key,
+// result of the backup is reported using container termination message
+func BackupPodSpec(cluster *apiv1.EtcdCluster, config Config, key string) corev1.PodSpec {
 	credentials := CredentialsSecretVolume(cluster)
 
 	secretName := cluster.Name + "-backup"
@@ -464,7 +765,8 @@ func BackupPodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
 			"backup",
 			"--endpoint=" + cluster.Status.Endpoint,
 			"--credentials-dir=" + CredentialsDir,
-			"--prefix=" + prefix,
+			"--key=" + key,
+			"--result-file=" + corev1.TerminationMessagePathDefault,
 		},
 		EnvFrom: []corev1.EnvFromSource{{
 			SecretRef: &corev1.SecretEnvSource{
@@ -480,42 +782,59 @@ func BackupPodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
 				ReadOnly:  true,
 			},
 		},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 	}
 
-	return corev1.PodSpec{
-		RestartPolicy:     corev1.RestartPolicyOnFailure,
+	return JobPodTemplate(cluster, corev1.PodSpec{
+		RestartPolicy:     corev1.RestartPolicyNever,
 		Containers:        []corev1.Container{container},
 		Volumes:           []corev1.Volume{credentials},
 		PriorityClassName: config.PriorityClassName,
-	}
+	})
 }
 
-func DefragCronJob(builder *resources.Builder, cluster *apiv1.EtcdCluster, config Config) *batchv1.CronJob {
-	schedule := DefragSchedule
-	if cluster.Spec.Defrag != nil && cluster.Spec.Defrag.Schedule != nil {
-		schedule = *cluster.Spec.Defrag.Schedule
-	}
-
-	suspend := false
-	if cluster.Spec.Defrag != nil && cluster.Spec.Defrag.Suspend != nil {
-		suspend = *cluster.Spec.Defrag.Suspend
-	}
-
-	cronJob := builder.CronJob("defrag").
-		Suspend(suspend).
-		ConcurrencyPolicy(batchv1.ForbidConcurrent).
-		Schedule(schedule).
+// BackupJob builds the job owned by EtcdBackup
+func BackupJob(builder *resources.Builder, cluster *apiv1.EtcdCluster, config Config, key string) *batchv1.Job {
+	job := builder.Job().
+		BackoffLimit(BackupBackoffLimit).
 		TTL(JobTTL).
 		ActiveDeadline(ActiveDeadline).
-		PodSpec(DefragPodSpec(cluster, config))
+		PodSpec(BackupPodSpec(cluster, config, key))
 
 	if cluster.Spec.PodTemplate != nil {
-		cronJob.
+		job.
 			PodLabels(cluster.Spec.PodTemplate.Labels).
 			PodAnnotations(cluster.Spec.PodTemplate.Annotations)
 	}
 
-	return cronJob.CronJob
+	return job.Job
+}
+
+// RequestBackupPodSpec creates EtcdBackup for the cluster and prunes scheduled backups exceeding history limit
+func RequestBackupPodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
+	historyLimit := BackupHistoryLimit
+	if cluster.Spec.Backup != nil && cluster.Spec.Backup.HistoryLimit != nil {
+		historyLimit = *cluster.Spec.Backup.HistoryLimit
+	}
+
+	container := corev1.Container{
+		Name:    "backup",
+		Image:   config.ControllerImage,
+		Command: []string{"etcd-tools"},
+		Args: []string{
+			"request-backup",
+			"--namespace=" + cluster.Namespace,
+			"--cluster=" + cluster.Name,
+			"--history-limit=" + strconv.Itoa(int(historyLimit)),
+		},
+	}
+
+	return JobPodTemplate(cluster, corev1.PodSpec{
+		RestartPolicy:      corev1.RestartPolicyOnFailure,
+		Containers:         []corev1.Container{container},
+		ServiceAccountName: cluster.Name + "-backup",
+		PriorityClassName:  config.PriorityClassName,
+	})
 }
 
 func BackupCronJob(builder *resources.Builder, cluster *apiv1.EtcdCluster, config Config) *batchv1.CronJob {
@@ -528,12 +847,13 @@ func BackupCronJob(builder *resources.Builder, cluster *apiv1.EtcdCluster, confi
 			Message: "backup is not configured",
 		})
 
-		builder.Delete(&batchv1.CronJob{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: cluster.Namespace,
-				Name:      cluster.Name + "-backup",
-			},
-		})
+		meta := metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name + "-backup",
+		}
+		builder.Delete(&batchv1.CronJob{ObjectMeta: meta})
+		builder.Delete(&rbacv1.RoleBinding{ObjectMeta: meta})
+		builder.Delete(&corev1.ServiceAccount{ObjectMeta: meta})
 
 		return nil
 	}
@@ -542,6 +862,12 @@ func BackupCronJob(builder *resources.Builder, cluster *apiv1.EtcdCluster, confi
 	builder.Secret("backup").
 		StringData(config.BackupEnv)
 
+	// backups are requested by dedicated service account, member pods can not create or delete EtcdBackups
+	serviceAccount := builder.ServiceAccount("backup")
+	builder.RoleBinding("backup").
+		ServiceAccountSubject(serviceAccount.ServiceAccount).
+		ClusterRoleRef("etcd-backup")
+
 	schedule := BackupSchedule
 	if cluster.Spec.Backup != nil && cluster.Spec.Backup.Schedule != "" {
 		schedule = cluster.Spec.Backup.Schedule
@@ -558,7 +884,7 @@ func BackupCronJob(builder *resources.Builder, cluster *apiv1.EtcdCluster, confi
 		Schedule(schedule).
 		TTL(JobTTL).
 		ActiveDeadline(ActiveDeadline).
-		PodSpec(BackupPodSpec(cluster, config))
+		PodSpec(RequestBackupPodSpec(cluster, config))
 
 	if cluster.Spec.PodTemplate != nil {
 		cronJob.
@@ -569,46 +895,6 @@ func BackupCronJob(builder *resources.Builder, cluster *apiv1.EtcdCluster, confi
 	return cronJob.CronJob
 }
 
-func DefragPodSpec(cluster *apiv1.EtcdCluster, config Config) corev1.PodSpec {
-	args := []string{
-		"defrag",
-		"--endpoint=" + cluster.Status.Endpoint,
-		"--credentials-dir=" + CredentialsDir,
-	}
-
-	if cluster.Spec.Defrag != nil && cluster.Spec.Defrag.Ratio != nil {
-		ratio := *cluster.Spec.Defrag.Ratio
-		args = append(args, "--ratio="+ratio)
-	}
-
-	if cluster.Spec.Defrag != nil && cluster.Spec.Defrag.Size != nil {
-		size := cluster.Spec.Defrag.Size.String()
-		args = append(args, "--unused-size="+size)
-	}
-
-	credentials := CredentialsSecretVolume(cluster)
-
-	container := corev1.Container{
-		Name:    "defrag",
-		Image:   config.ControllerImage,
-		Command: []string{"etcd-tools"},
-		Args:    args,
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				Name:      credentials.Name,
-				MountPath: CredentialsDir,
-			},
-		},
-	}
-
-	return corev1.PodSpec{
-		RestartPolicy:     corev1.RestartPolicyOnFailure,
-		Containers:        []corev1.Container{container},
-		Volumes:           []corev1.Volume{credentials},
-		PriorityClassName: config.PriorityClassName,
-	}
-}
-
 func CredentialsSecretVolume(cluster *apiv1.EtcdCluster) corev1.Volume {
 	return corev1.Volume{
 		Name: "pki",
@@ -1,13 +1,24 @@
 package cluster
 
 import (
+	"slices"
 	"testing"
+	"time"
 
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"gotest.tools/v3/golden"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/yaml"
 
 	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
@@ -50,6 +61,80 @@ func createTestConfig() Config {
 	}
 }
 
+func createTestClient(t testing.TB, objs ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{kscheme.AddToScheme, cmv1.AddToScheme, apiv1.AddToScheme} {
+		err := add(scheme)
+		if err != nil {
+			t.Fatal("add to scheme:", err)
+		}
+	}
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv1.EtcdCluster{}, &apiv1.EtcdRestore{}, &apiv1.EtcdBackup{}).
+		WithIndex(&apiv1.EtcdUser{}, ClusterNameField, func(obj client.Object) []string {
+			return []string{obj.(*apiv1.EtcdUser).Spec.ClusterName}
+		}).
+		WithIndex(&apiv1.EtcdRole{}, ClusterNameField, func(obj client.Object) []string {
+			return []string{obj.(*apiv1.EtcdRole).Spec.ClusterName}
+		}).
+		WithObjects(objs...).
+		Build()
+}
+
+func TestETCDConfig(t *testing.T) {
+	config := createTestConfig()
+
+	tests := []struct {
+		name    string
+		version string
+		spec    *apiv1.EtcdSpec
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:    "v3.6",
+			version: "v3.6.0",
+		},
+		{
+			name: "tuned",
+			spec: &apiv1.EtcdSpec{
+				HeartbeatInterval:  &metav1.Duration{Duration: 250 * time.Millisecond},
+				ElectionTimeout:    &metav1.Duration{Duration: 2500 * time.Millisecond},
+				SnapshotCount:      ptr.To(int64(50000)),
+				AutoCompactionMode: ptr.To(apiv1.CompactionPeriodic),
+				MaxRequestBytes:    ptr.To(resource.MustParse("4Mi")),
+				GRPCKeepAlive: &apiv1.GRPCKeepAliveSpec{
+					Interval: &metav1.Duration{Duration: 30 * time.Second},
+					Timeout:  &metav1.Duration{Duration: 10 * time.Second},
+				},
+				LogLevel: ptr.To("warn"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Etcd = tt.spec
+			if tt.version != "" {
+				cluster.Spec.Version = tt.version
+			}
+
+			got, err := ETCDConfig(cluster, config)
+			if err != nil {
+				t.Fatal("etcd config:", err)
+			}
+
+			golden.Assert(t, string(got), t.Name()+".json")
+		})
+	}
+}
+
 func TestRestoreContainer(t *testing.T) {
 	config := createTestConfig()
 
@@ -66,6 +151,15 @@ func TestRestoreContainer(t *testing.T) {
 				Key: ptr.To("test-key"),
 			},
 		},
+		{
+			name: "clone",
+			spec: &apiv1.RestoreSpec{
+				FromCluster: &apiv1.ClusterReference{
+					Namespace: "production",
+					Name:      "source",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -124,40 +218,559 @@ func TestBackupCronJob(t *testing.T) {
 	}
 }
 
-func TestDefragCronJob(t *testing.T) {
+func TestBackupServiceAccount(t *testing.T) {
+	cluster := createTestCluster()
+
+	builder := resources.NewBuilder(cluster)
+	cronJob := BackupCronJob(builder, cluster, createTestConfig())
+
+	// member service account is not used to request backups
+	serviceAccount := cronJob.Spec.JobTemplate.Spec.Template.Spec.ServiceAccountName
+	if serviceAccount != "test-cluster-backup" {
+		t.Errorf("expected service account test-cluster-backup, got %q", serviceAccount)
+	}
+
+	var bound bool
+	for _, obj := range builder.Objects() {
+		roleBinding, ok := obj.(*rbacv1.RoleBinding)
+		if !ok || roleBinding.Name != "test-cluster-backup" {
+			continue
+		}
+
+		bound = roleBinding.RoleRef.Name == "etcd-backup" &&
+			len(roleBinding.Subjects) == 1 && roleBinding.Subjects[0].Name == serviceAccount
+	}
+	if !bound {
+		t.Errorf("expected %s to be bound to etcd-backup cluster role", serviceAccount)
+	}
+}
+
+func TestBackupJob(t *testing.T) {
+	config := createTestConfig()
+	cluster := createTestCluster()
+
+	builder := resources.NewBuilder(cluster)
+	job := BackupJob(builder, cluster, config, "default/test-cluster/20240920040000")
+
+	// Convert spec to YAML for golden file comparison
+	got, err := yaml.Marshal(job)
+	if err != nil {
+		t.Fatal("marshal:", err)
+	}
+
+	golden.Assert(t, string(got), t.Name()+".yaml")
+}
+
+func TestMirrorDeployment(t *testing.T) {
+	config := createTestConfig()
+	cluster := createTestCluster()
+	cluster.Spec.Mirror = &apiv1.MirrorSpec{
+		Endpoint:   "https://source.example.com:2379",
+		SecretName: "source-credentials",
+		Prefix:     "/registry/",
+	}
+	cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+	cluster.Status.Conditions = append(cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:   apiv1.ClusterAuth,
+		Status: corev1.ConditionTrue,
+	})
+
+	builder := resources.NewBuilder(cluster)
+	deployment := MirrorDeployment(builder, cluster, config)
+
+	// Convert spec to YAML for golden file comparison
+	got, err := yaml.Marshal(deployment)
+	if err != nil {
+		t.Fatal("marshal:", err)
+	}
+
+	golden.Assert(t, string(got), t.Name()+".yaml")
+}
+
+func TestReadReplicas(t *testing.T) {
 	config := createTestConfig()
+	cluster := createTestCluster()
+	cluster.Spec.Version = "v3.6.0"
+	cluster.Spec.ReadReplicas = 2
+	// read replicas keep ephemeral data
+	cluster.Spec.Storage = &apiv1.StorageSpec{}
+
+	builder := resources.NewBuilder(cluster)
+	deployment := ReadReplicas(builder, cluster, config)
+
+	// Convert spec to YAML for golden file comparison
+	got, err := yaml.Marshal(deployment)
+	if err != nil {
+		t.Fatal("marshal:", err)
+	}
+
+	golden.Assert(t, string(got), t.Name()+".yaml")
+}
+
+func TestMembersSelector(t *testing.T) {
+	cluster := createTestCluster()
+	builder := resources.NewBuilder(cluster)
+	_, err := Workload(t.Context(), builder, cluster, createTestConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusterLabel := apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster))
+	member := labels.Set{apiv1.ClusterLabel: clusterLabel}
+	replica := labels.Set{apiv1.ClusterLabel: clusterLabel, apiv1.ReadReplicaLabel: "true"}
+
+	var deployment *appsv1.Deployment
+	for _, obj := range builder.Objects() {
+		var selector *metav1.LabelSelector
+		switch obj := obj.(type) {
+		case *appsv1.Deployment:
+			deployment = obj
+			selector = obj.Spec.Selector
+		case *policyv1.PodDisruptionBudget:
+			selector = obj.Spec.Selector
+		default:
+			continue
+		}
+
+		s, err := metav1.LabelSelectorAsSelector(selector)
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case !s.Matches(member):
+			t.Errorf("expected %T selector to match members", obj)
+		case s.Matches(replica):
+			t.Errorf("expected %T selector not to match read replicas", obj)
+		}
+	}
+
+	if deployment == nil {
+		t.Fatal("expected members deployment")
+	}
+
+	// deployment created with previous selector is deleted, replica sets are orphaned
+	outdated := deployment.DeepCopy()
+	outdated.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{apiv1.ClusterLabel: clusterLabel},
+	}
+
+	r := &Reconciler{
+		kcl: createTestClient(t, outdated),
+	}
+
+	for _, expected := range []bool{false, true} {
+		ready, err := r.ReconcileMembersSelector(t.Context(), deployment)
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case ready != expected:
+			t.Errorf("expected ready %t, got %t", expected, ready)
+		}
+	}
+
+	err = r.kcl.Create(t.Context(), deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ready, err := r.ReconcileMembersSelector(t.Context(), deployment)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case !ready:
+		t.Error("expected deployment with desired selector to be kept")
+	}
+}
+
+func TestExternalService(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Spec.Service = &apiv1.ServiceSpec{
+		Annotations: map[string]string{
+			"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+		},
+		ExternalDNSNames: []string{"etcd.example.com"},
+	}
+
+	builder := resources.NewBuilder(cluster)
+	service := ExternalService(builder, cluster)
+
+	// Convert spec to YAML for golden file comparison
+	got, err := yaml.Marshal(service)
+	if err != nil {
+		t.Fatal("marshal:", err)
+	}
+
+	golden.Assert(t, string(got), t.Name()+".yaml")
+}
+
+func TestExternalEndpoint(t *testing.T) {
 	tests := []struct {
-		name string
-		spec *apiv1.DefragSpec
+		name     string
+		spec     apiv1.ServiceSpec
+		service  corev1.Service
+		endpoint string
+	}{
+		{
+			name: "external dns name",
+			spec: apiv1.ServiceSpec{ExternalDNSNames: []string{"etcd.example.com", "etcd.example.org"}},
+			service: corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+				}},
+			},
+			endpoint: "https://etcd.example.com:2379",
+		},
+		{
+			name: "load balancer ip",
+			service: corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+				}},
+			},
+			endpoint: "https://10.0.0.1:2379",
+		},
+		{
+			name:    "load balancer pending",
+			service: corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}},
+		},
+		{
+			name: "node port",
+			spec: apiv1.ServiceSpec{ExternalDNSNames: []string{"etcd.example.com"}},
+			service: corev1.Service{Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Port: 2379, NodePort: 32379}},
+			}},
+			endpoint: "https://etcd.example.com:32379",
+		},
+		{
+			name: "ipv6",
+			service: corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "fd00::1"}},
+				}},
+			},
+			endpoint: "https://[fd00::1]:2379",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Service = &tt.spec
+
+			if got := ExternalEndpoint(cluster, &tt.service); got != tt.endpoint {
+				t.Errorf("expected endpoint %q, got %q", tt.endpoint, got)
+			}
+		})
+	}
+}
+
+func TestIssuer(t *testing.T) {
+	tests := []struct {
+		name   string
+		pki    *apiv1.PKISpec
+		server string
+		peer   string
+	}{
+		{
+			name:   "default",
+			server: "Issuer/test-cluster-server-ca",
+			peer:   "Issuer/test-cluster-peer-ca",
+		},
+		{
+			name: "server issuer",
+			pki: &apiv1.PKISpec{
+				ServerIssuerRef: &apiv1.IssuerReference{Name: "etcd-server", Kind: "ClusterIssuer"},
+			},
+			server: "ClusterIssuer/etcd-server",
+			peer:   "Issuer/test-cluster-peer-ca",
+		},
+		{
+			name: "peer issuer",
+			pki: &apiv1.PKISpec{
+				PeerIssuerRef: &apiv1.IssuerReference{Name: "etcd-peer"},
+			},
+			server: "Issuer/test-cluster-server-ca",
+			peer:   "Issuer/etcd-peer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.PKI = tt.pki
+
+			if got := FormatIssuer(ServerIssuer(cluster)); got != tt.server {
+				t.Errorf("expected server issuer %q, got %q", tt.server, got)
+			}
+			if got := FormatIssuer(PeerIssuer(cluster)); got != tt.peer {
+				t.Errorf("expected peer issuer %q, got %q", tt.peer, got)
+			}
+		})
+	}
+}
+
+func TestSidecarPKIArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		pki         *apiv1.PKISpec
+		auth        *apiv1.AuthSpec
+		generation  int32
+		step        string
+		authEnabled bool
+		args        []string
 	}{
 		{
 			name: "default",
 		},
 		{
-			name: "schedule",
-			spec: &apiv1.DefragSpec{
-				Schedule: ptr.To("@midnight"),
+			name:       "rotated",
+			generation: 2,
+			args: []string{
+				"--server-issuer=Issuer/test-cluster-server-ca-2",
+				"--peer-issuer=Issuer/test-cluster-peer-ca-2",
+			},
+		},
+		{
+			name: "issuing",
+			step: "Issuing",
+		},
+		{
+			name: "trusting",
+			step: "Trusting",
+			args: []string{
+				"--ca-bundle=test-cluster-ca-bundle",
+			},
+		},
+		{
+			name:       "reissuing",
+			generation: 1,
+			step:       "Reissuing",
+			args: []string{
+				"--server-issuer=Issuer/test-cluster-server-ca-1",
+				"--peer-issuer=Issuer/test-cluster-peer-ca-1",
+				"--ca-bundle=test-cluster-ca-bundle",
 			},
 		},
 		{
-			name: "threshold",
-			spec: &apiv1.DefragSpec{
-				Size:  ptr.To(resource.MustParse("1G")),
-				Ratio: ptr.To("0.1"),
+			name:       "dropping",
+			generation: 1,
+			step:       "Dropping",
+			args: []string{
+				"--server-issuer=Issuer/test-cluster-server-ca-1",
+				"--peer-issuer=Issuer/test-cluster-peer-ca-1",
 			},
 		},
+		{
+			name: "server issuer",
+			pki: &apiv1.PKISpec{
+				ServerIssuerRef: &apiv1.IssuerReference{Name: "etcd-server", Kind: "ClusterIssuer"},
+			},
+			generation: 1,
+			step:       "Reissuing",
+			args: []string{
+				"--server-issuer=ClusterIssuer/etcd-server",
+				"--peer-issuer=Issuer/test-cluster-peer-ca-1",
+				"--ca-bundle=test-cluster-ca-bundle",
+			},
+		},
+		{
+			name: "member certificates",
+			pki: &apiv1.PKISpec{
+				KeyAlgorithm: "ECDSA",
+				KeySize:      384,
+				Duration:     &metav1.Duration{Duration: 30 * 24 * time.Hour},
+				RenewBefore:  &metav1.Duration{Duration: 10 * 24 * time.Hour},
+			},
+			args: []string{
+				"--key-algorithm=ECDSA",
+				"--key-size=384",
+				"--duration=720h0m0s",
+				"--renew-before=240h0m0s",
+			},
+		},
+		{
+			name: "key size",
+			pki:  &apiv1.PKISpec{KeySize: 4096},
+			args: []string{
+				"--key-algorithm=RSA",
+				"--key-size=4096",
+			},
+		},
+		{
+			name: "auth",
+			auth: &apiv1.AuthSpec{Enabled: true},
+			args: []string{
+				"--common-name=root",
+			},
+		},
+		{
+			name:        "auth disabling",
+			auth:        &apiv1.AuthSpec{Enabled: false},
+			authEnabled: true,
+			args: []string{
+				"--common-name=root",
+			},
+		},
+		{
+			name: "auth disabled",
+			auth: &apiv1.AuthSpec{Enabled: false},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cluster := createTestCluster()
-			cluster.Spec.Defrag = tt.spec
+			cluster.Spec.PKI = tt.pki
+			cluster.Spec.Auth = tt.auth
+			cluster.Status.PKI = &apiv1.PKIStatus{Generation: tt.generation}
+			if tt.step != "" {
+				cluster.Status.Conditions = append(cluster.Status.Conditions, apiv1.ClusterCondition{
+					Type:   apiv1.ClusterCARotation,
+					Status: corev1.ConditionTrue,
+					Reason: tt.step,
+				})
+			}
+			if tt.authEnabled {
+				cluster.Status.Conditions = append(cluster.Status.Conditions, apiv1.ClusterCondition{
+					Type:   apiv1.ClusterAuth,
+					Status: corev1.ConditionTrue,
+					Reason: "Disabling",
+				})
+			}
 
-			builder := resources.NewBuilder(cluster)
-			cronJob := DefragCronJob(builder, cluster, config)
+			args := SidecarPKIArgs(cluster)
+			if !slices.Equal(args, tt.args) {
+				t.Errorf("expected %v, got %v", tt.args, args)
+			}
 
+			// rollout of members is detected by the same arguments
+			template := corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{SidecarContainer(cluster, createTestConfig())},
+			}}
+			if rolled := podTemplatePKIArgs(template); !slices.Equal(rolled, args) {
+				t.Errorf("expected pod template args %v, got %v", args, rolled)
+			}
+		})
+	}
+}
+
+func TestSidecarContainer(t *testing.T) {
+	config := createTestConfig()
+	config.ClusterDomain = "example.internal"
+
+	cluster := createTestCluster()
+	cluster.Spec.Service = &apiv1.ServiceSpec{
+		ExternalDNSNames: []string{"etcd.example.com"},
+		DNSNames:         []string{"etcd.example.org"},
+		IPAddresses:      []string{"10.0.0.1"},
+	}
+	cluster.Spec.PKI = &apiv1.PKISpec{
+		ServerIssuerRef: &apiv1.IssuerReference{Name: "etcd-server", Kind: "ClusterIssuer"},
+	}
+
+	container := SidecarContainer(cluster, config)
+
+	// Convert container to YAML for golden file comparison
+	got, err := yaml.Marshal(container)
+	if err != nil {
+		t.Fatal("marshal:", err)
+	}
+
+	golden.Assert(t, string(got), t.Name()+".yaml")
+}
+
+func TestDataVolumeClaim(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *apiv1.StorageSpec
+	}{
+		{
+			name: "default",
+			spec: &apiv1.StorageSpec{},
+		},
+		{
+			name: "class",
+			spec: &apiv1.StorageSpec{
+				Size:             ptr.To(resource.MustParse("16Gi")),
+				StorageClassName: ptr.To("fast"),
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Storage = tt.spec
+
+			claim := DataVolumeClaim(cluster)
+
+			// Convert claim to YAML for golden file comparison
+			got, err := yaml.Marshal(claim)
+			if err != nil {
+				t.Fatal("marshal:", err)
+			}
+
+			golden.Assert(t, string(got), t.Name()+".yaml")
+		})
+	}
+}
+
+func TestPodTemplate(t *testing.T) {
+	config := createTestConfig()
+
+	cluster := createTestCluster()
+	cluster.Spec.PodTemplate = &apiv1.PodTemplate{
+		Spec: &corev1.PodSpec{
+			NodeSelector: map[string]string{
+				"node-pool": "etcd",
+			},
+			Tolerations: []corev1.Toleration{{
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "etcd",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						apiv1.ClusterLabel: "test-cluster.default",
+					},
+				},
+			}},
+			ImagePullSecrets:  []corev1.LocalObjectReference{{Name: "registry"}},
+			PriorityClassName: "etcd-critical",
+			Containers: []corev1.Container{
+				// protected container is not overridden
+				{Name: "etcd", Image: "example"},
+				{Name: "exporter", Image: "exporter"},
+			},
+			ServiceAccountName: "example",
+		},
+	}
+
+	tests := []struct {
+		name string
+		spec func() corev1.PodSpec
+	}{
+		{
+			name: "member",
+			spec: func() corev1.PodSpec {
+				return PodSpec(cluster, config)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
 			// Convert spec to YAML for golden file comparison
-			got, err := yaml.Marshal(cronJob)
+			got, err := yaml.Marshal(tt.spec())
 			if err != nil {
 				t.Fatal("marshal:", err)
 			}
@@ -0,0 +1,301 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+// PodDeletionCostAnnotation is used to keep the leader pod to be replaced last by deployment rollout
+const PodDeletionCostAnnotation = "controller.kubernetes.io/pod-deletion-cost"
+
+// ReconcileUpgrade validates change of spec version against observed cluster version.
+// Pre-upgrade backup is taken before spec version is rolled out to members one at a time with the leader last.
+func (r *Reconciler) ReconcileUpgrade(ctx context.Context, cluster *apiv1.EtcdCluster) error {
+	if cluster.Status.Phase != apiv1.ClusterRunning || cluster.Status.Version == "" {
+		return nil
+	}
+
+	current, err := semver.NewVersion(cluster.Status.Version)
+	if err != nil {
+		return fmt.Errorf("cluster version: %w", err)
+	}
+
+	target, err := semver.NewVersion(strings.TrimPrefix(cluster.Spec.Version, "v"))
+	if err != nil {
+		r.upgradeBlocked(cluster, "InvalidVersion", fmt.Sprintf("invalid version %q: %v", cluster.Spec.Version, err))
+		return nil
+	}
+
+	// downgrade in progress is completed or cancelled first
+	if cluster.Status.DowngradeVersion != "" {
+		return r.ReconcileDowngrade(ctx, cluster, target)
+	}
+
+	cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterUpgrading)
+
+	// all members run spec version
+	switch {
+	case current.Equal(*target) && cond.Status == corev1.ConditionTrue && cond.Reason == "Rolling":
+		message := fmt.Sprintf("cluster upgraded to %s", target)
+		r.recorder.Event(cluster, corev1.EventTypeNormal, "Upgraded", message)
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterUpgrading,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Upgraded",
+			Message: message,
+		})
+		return nil
+	case current.Equal(*target):
+		conditions.Clear(&cluster.Status.Conditions, apiv1.ClusterUpgrading)
+		return nil
+	}
+
+	switch {
+	case current.Major != target.Major || target.Minor > current.Minor+1:
+		r.upgradeBlocked(cluster, "UnsupportedUpgradePath", fmt.Sprintf("upgrade from %d.%d to %d.%d is not supported, upgrade one minor version at a time", current.Major, current.Minor, target.Major, target.Minor))
+		return nil
+	case target.Minor+1 == current.Minor:
+		return r.ReconcileDowngrade(ctx, cluster, target)
+	case target.Minor < current.Minor:
+		r.downgradeFailed(cluster, "UnsupportedDowngradePath", fmt.Sprintf("downgrade from %d.%d to %d.%d is not supported, downgrade one minor version at a time", current.Major, current.Minor, target.Major, target.Minor))
+		return nil
+	}
+
+	if cond.Status == corev1.ConditionTrue && cond.Reason == "Rolling" {
+		return r.RollMembers(ctx, cluster, target)
+	}
+
+	done, err := r.UpgradeBackup(ctx, cluster, target)
+	if !done || err != nil {
+		return err
+	}
+
+	// spec version is applied to the workload
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "Upgrading", "Upgrading cluster from %s to %s", current, target)
+	cluster.Status.ObservedGeneration = 0
+
+	return r.RollMembers(ctx, cluster, target)
+}
+
+// UpgradeBackup creates pre-upgrade EtcdBackup and returns true once it has succeeded,
+// backup is skipped when backup is not configured
+func (r *Reconciler) UpgradeBackup(ctx context.Context, cluster *apiv1.EtcdCluster, target *semver.Version) (bool, error) {
+	if len(r.config.BackupEnv) == 0 {
+		return true, nil
+	}
+
+	key := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      fmt.Sprintf("%s-upgrade-%d-%d-%d", cluster.Name, target.Major, target.Minor, target.Patch),
+	}
+	etcdBackup := &apiv1.EtcdBackup{}
+	err := r.kcl.Get(ctx, key, etcdBackup)
+	switch {
+	case apierrors.IsNotFound(err):
+		etcdBackup = &apiv1.EtcdBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Labels: map[string]string{
+					apiv1.ClusterLabel: apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)),
+				},
+			},
+			Spec: apiv1.EtcdBackupSpec{
+				ClusterName: cluster.Name,
+			},
+		}
+		err = r.kcl.Create(ctx, etcdBackup)
+		if err != nil {
+			return false, fmt.Errorf("create pre-upgrade backup: %w", err)
+		}
+
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "BackupRequested", "Requested pre-upgrade backup %q", key.Name)
+	case err != nil:
+		return false, fmt.Errorf("get pre-upgrade backup: %w", err)
+	}
+
+	switch etcdBackup.Status.Phase {
+	case apiv1.BackupSucceeded:
+		return true, nil
+	case apiv1.BackupFailed:
+		r.upgradeBlocked(cluster, "BackupFailed", fmt.Sprintf("pre-upgrade backup %q failed: %s, delete it to retry", key.Name, etcdBackup.Status.Message))
+	default:
+		conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+			Type:    apiv1.ClusterUpgrading,
+			Status:  corev1.ConditionTrue,
+			Reason:  "BackupInProgress",
+			Message: fmt.Sprintf("waiting for pre-upgrade backup %q", key.Name),
+		})
+	}
+
+	return false, nil
+}
+
+// RollMembers reports upgrade progress and keeps the leader to be replaced last
+func (r *Reconciler) RollMembers(ctx context.Context, cluster *apiv1.EtcdCluster, target *semver.Version) error {
+	upgraded := 0
+	var leader *apiv1.MemberStatus
+	for i, member := range cluster.Status.Members {
+		if member.Version == target.String() {
+			upgraded++
+		}
+		if member.Role == apiv1.MemberRoleLeader {
+			leader = &cluster.Status.Members[i]
+		}
+	}
+
+	conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterUpgrading,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Rolling",
+		Message: fmt.Sprintf("%d of %d members upgraded to %s", upgraded, len(cluster.Status.Members), target),
+	})
+
+	if leader == nil || leader.Version == target.String() {
+		return nil
+	}
+
+	// statefulset replaces members in reverse ordinal order - move leadership to the first member
+	if cluster.Spec.Storage != nil {
+		return r.MoveLeader(ctx, cluster, leader, cluster.Name+"-0")
+	}
+
+	// deployment replaces pods with the lowest deletion cost first
+	pod := &corev1.Pod{}
+	err := r.kcl.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: leader.Name}, pod)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("get leader pod: %w", err)
+	case pod.Annotations[PodDeletionCostAnnotation] != "":
+		return nil
+	}
+
+	base := pod.DeepCopy()
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, PodDeletionCostAnnotation, "1000")
+	err = r.kcl.Patch(ctx, pod, client.MergeFrom(base))
+	if err != nil {
+		return fmt.Errorf("patch leader pod: %w", err)
+	}
+
+	return nil
+}
+
+// MoveLeader transfers leadership to the member with given name
+func (r *Reconciler) MoveLeader(ctx context.Context, cluster *apiv1.EtcdCluster, leader *apiv1.MemberStatus, name string) error {
+	if leader.Name == name || leader.Endpoint == "" {
+		return nil
+	}
+
+	var transferee *apiv1.MemberStatus
+	for i, member := range cluster.Status.Members {
+		if member.Name == name && member.Available {
+			transferee = &cluster.Status.Members[i]
+		}
+	}
+	if transferee == nil {
+		return nil
+	}
+
+	id, err := strconv.ParseUint(transferee.ID, 16, 64)
+	if err != nil {
+		return fmt.Errorf("parse member id: %w", err)
+	}
+
+	key := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Status.SecretName,
+	}
+	tlsConfig, err := r.tlsCache.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("tls config: %v", err)
+	}
+
+	// leadership can only be transferred by the leader
+	ecl, err := etcd.Connect(ctx, tlsConfig, leader.Endpoint)
+	if err != nil {
+		return fmt.Errorf("connect to leader: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	_, err = ecl.MoveLeader(ctx, id)
+	if err != nil {
+		return fmt.Errorf("move leader to %q: %w", name, err)
+	}
+
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "LeaderMoved", "Moved leadership from %q to %q", leader.Name, name)
+
+	return nil
+}
+
+func (r *Reconciler) upgradeBlocked(cluster *apiv1.EtcdCluster, reason, message string) {
+	changed := conditions.Upsert(&cluster.Status.Conditions, apiv1.ClusterCondition{
+		Type:    apiv1.ClusterUpgrading,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	if changed {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// ClusterVersion returns the lowest version of cluster members
+func ClusterVersion(members []apiv1.MemberStatus) string {
+	var lowest *semver.Version
+	for _, member := range members {
+		if member.Version == "" {
+			continue
+		}
+
+		version, err := semver.NewVersion(member.Version)
+		if err != nil {
+			continue
+		}
+
+		if lowest == nil || version.LessThan(*lowest) {
+			lowest = version
+		}
+	}
+
+	if lowest == nil {
+		return ""
+	}
+
+	return lowest.String()
+}
+
+// MemberVersion returns etcd version members are run with, spec version is used
+// unless observed cluster version differs and spec version is not being rolled out
+func MemberVersion(cluster *apiv1.EtcdCluster) string {
+	if cluster.Status.Version == "" || strings.TrimPrefix(cluster.Spec.Version, "v") == cluster.Status.Version {
+		return cluster.Spec.Version
+	}
+
+	for _, tpe := range []apiv1.ClusterConditionType{apiv1.ClusterUpgrading, apiv1.ClusterDowngrading} {
+		cond, ok := conditions.Get(cluster.Status.Conditions, tpe)
+		if ok && cond.Status == corev1.ConditionTrue && cond.Reason == "Rolling" {
+			return cluster.Spec.Version
+		}
+	}
+
+	return "v" + cluster.Status.Version
+}
@@ -0,0 +1,301 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coreos/go-semver/semver"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/conditions"
+)
+
+func TestReconcileUpgrade(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		target    string
+		condition apiv1.ClusterConditionType
+		reason    string
+		version   string
+	}{
+		{
+			name:      "skip minor",
+			current:   "3.4.30",
+			target:    "v3.6.0",
+			condition: apiv1.ClusterUpgrading,
+			reason:    "UnsupportedUpgradePath",
+			version:   "v3.4.30",
+		},
+		{
+			name:      "major",
+			current:   "3.5.14",
+			target:    "v4.0.0",
+			condition: apiv1.ClusterUpgrading,
+			reason:    "UnsupportedUpgradePath",
+			version:   "v3.5.14",
+		},
+		{
+			name:      "skip minor downgrade",
+			current:   "3.6.0",
+			target:    "v3.4.30",
+			condition: apiv1.ClusterDowngrading,
+			reason:    "UnsupportedDowngradePath",
+			version:   "v3.6.0",
+		},
+		{
+			name:      "invalid",
+			current:   "3.5.14",
+			target:    "latest",
+			condition: apiv1.ClusterUpgrading,
+			reason:    "InvalidVersion",
+			version:   "v3.5.14",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Version = tt.target
+			cluster.Status.Version = tt.current
+
+			r := &Reconciler{
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			err := r.ReconcileUpgrade(t.Context(), cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cond, ok := conditions.Get(cluster.Status.Conditions, tt.condition)
+			switch {
+			case !ok:
+				t.Fatalf("expected %s condition", tt.condition)
+			case cond.Status != corev1.ConditionFalse || cond.Reason != tt.reason:
+				t.Errorf("expected %s condition False %s, got %s %s", tt.condition, tt.reason, cond.Status, cond.Reason)
+			}
+
+			if got := MemberVersion(cluster); got != tt.version {
+				t.Errorf("expected member version %q, got %q", tt.version, got)
+			}
+		})
+	}
+}
+
+func TestUpgradeBackup(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		phase   apiv1.BackupPhase
+		done    bool
+		reason  string
+		created bool
+	}{
+		{
+			name: "backup not configured",
+			done: true,
+		},
+		{
+			name:    "requested",
+			config:  createTestConfig(),
+			reason:  "BackupInProgress",
+			created: true,
+		},
+		{
+			name:   "running",
+			config: createTestConfig(),
+			phase:  apiv1.BackupRunning,
+			reason: "BackupInProgress",
+		},
+		{
+			name:   "succeeded",
+			config: createTestConfig(),
+			phase:  apiv1.BackupSucceeded,
+			done:   true,
+		},
+		{
+			name:   "failed",
+			config: createTestConfig(),
+			phase:  apiv1.BackupFailed,
+			reason: "BackupFailed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Status.Version = "3.5.7"
+
+			var objects []client.Object
+			if tt.phase != "" {
+				objects = append(objects, &apiv1.EtcdBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: cluster.Namespace,
+						Name:      "test-cluster-upgrade-3-6-0",
+					},
+					Spec: apiv1.EtcdBackupSpec{
+						ClusterName: cluster.Name,
+					},
+					Status: apiv1.EtcdBackupStatus{
+						Phase: tt.phase,
+					},
+				})
+			}
+
+			kcl := createTestClient(t, objects...)
+			r := &Reconciler{
+				kcl:      kcl,
+				recorder: record.NewFakeRecorder(10),
+				config:   tt.config,
+			}
+
+			done, err := r.UpgradeBackup(t.Context(), cluster, semver.New("3.6.0"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterUpgrading)
+			switch {
+			case done != tt.done:
+				t.Errorf("expected done %v, got %v", tt.done, done)
+			case cond.Reason != tt.reason:
+				t.Errorf("expected upgrading condition %q, got %q: %s", tt.reason, cond.Reason, cond.Message)
+			}
+
+			backup := &apiv1.EtcdBackup{}
+			err = kcl.Get(t.Context(), client.ObjectKey{Namespace: cluster.Namespace, Name: "test-cluster-upgrade-3-6-0"}, backup)
+			switch {
+			case tt.config.BackupEnv == nil && !apierrors.IsNotFound(err):
+				t.Errorf("expected no backup, got %v", err)
+			case tt.config.BackupEnv == nil:
+			case err != nil:
+				t.Fatal(err)
+			case backup.Spec.ClusterName != cluster.Name:
+				t.Errorf("expected backup of cluster %q, got %q", cluster.Name, backup.Spec.ClusterName)
+			case tt.created && backup.Labels[apiv1.ClusterLabel] != apiv1.ClusterLabelValue(client.ObjectKeyFromObject(cluster)):
+				t.Errorf("expected cluster label, got %v", backup.Labels)
+			}
+		})
+	}
+}
+
+func TestReconcileUpgradeRolling(t *testing.T) {
+	cluster := createTestCluster()
+	cluster.Spec.Version = "v3.6.0"
+	cluster.Status.Version = "3.5.7"
+	cluster.Status.ObservedGeneration = 1
+
+	backup := &apiv1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      "test-cluster-upgrade-3-6-0",
+		},
+		Status: apiv1.EtcdBackupStatus{
+			Phase: apiv1.BackupSucceeded,
+		},
+	}
+
+	r := &Reconciler{
+		kcl:      createTestClient(t, backup),
+		recorder: record.NewFakeRecorder(10),
+		config:   createTestConfig(),
+	}
+
+	err := r.ReconcileUpgrade(t.Context(), cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterUpgrading)
+	switch {
+	case cond.Status != corev1.ConditionTrue || cond.Reason != "Rolling":
+		t.Errorf("expected rolling upgrade, got %s/%s: %s", cond.Status, cond.Reason, cond.Message)
+	case cluster.Status.ObservedGeneration != 0:
+		t.Error("expected spec version to be applied")
+	case MemberVersion(cluster) != "v3.6.0":
+		t.Errorf("expected member version v3.6.0, got %s", MemberVersion(cluster))
+	}
+}
+
+func TestRollMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		storage  *apiv1.StorageSpec
+		pods     []string
+		leader   string
+		expected string
+	}{
+		{
+			name:     "deployment",
+			pods:     []string{"test-cluster-abc", "test-cluster-def", "test-cluster-ghi"},
+			leader:   "test-cluster-def",
+			expected: "test-cluster-def",
+		},
+		{
+			name:    "statefulset",
+			storage: &apiv1.StorageSpec{},
+			pods:    []string{"test-cluster-0", "test-cluster-1", "test-cluster-2"},
+			leader:  "test-cluster-0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster()
+			cluster.Spec.Storage = tt.storage
+
+			var objects []client.Object
+			for _, name := range tt.pods {
+				role := apiv1.MemberRoleMember
+				if name == tt.leader {
+					role = apiv1.MemberRoleLeader
+				}
+				cluster.Status.Members = append(cluster.Status.Members, apiv1.MemberStatus{
+					Name:     name,
+					Endpoint: "https://" + name + ":2379",
+					Version:  "3.5.7",
+					Role:     role,
+				})
+				objects = append(objects, createTestMemberPod(cluster, name, time.Hour, corev1.PodRunning))
+			}
+
+			kcl := createTestClient(t, objects...)
+			r := &Reconciler{
+				kcl:      kcl,
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			err := r.RollMembers(t.Context(), cluster, semver.New("3.6.0"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range tt.pods {
+				pod := &corev1.Pod{}
+				err = kcl.Get(t.Context(), client.ObjectKey{Namespace: cluster.Namespace, Name: name}, pod)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				cost, ok := pod.Annotations[PodDeletionCostAnnotation]
+				switch {
+				case name == tt.expected && cost != "1000":
+					t.Errorf("expected deletion cost of leader pod %s, got %q", name, cost)
+				case name != tt.expected && ok:
+					t.Errorf("expected no deletion cost of pod %s, got %q", name, cost)
+				}
+			}
+
+			cond, _ := conditions.Get(cluster.Status.Conditions, apiv1.ClusterUpgrading)
+			if cond.Reason != "Rolling" || cond.Message != "0 of 3 members upgraded to 3.6.0" {
+				t.Errorf("expected rolling progress, got %s: %s", cond.Reason, cond.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+type UserReconciler struct {
+	kcl      client.Client
+	recorder record.EventRecorder
+	tlsCache *etcd.TLSCache
+}
+
+// SetupUserWithManager creates EtcdUser controller
+func SetupUserWithManager(mgr manager.Manager, tlsCache *etcd.TLSCache) error {
+	reconciler := &UserReconciler{
+		kcl:      mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("etcduser"),
+		tlsCache: tlsCache,
+	}
+
+	// index users by cluster name
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1.EtcdUser{}, ClusterNameField, func(obj client.Object) []string {
+		return []string{obj.(*apiv1.EtcdUser).Spec.ClusterName}
+	})
+	if err != nil {
+		return fmt.Errorf("index users: %w", err)
+	}
+
+	// users are applied once cluster is running
+	clusterHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		users := &apiv1.EtcdUserList{}
+		err := reconciler.kcl.List(ctx, users, client.InNamespace(obj.GetNamespace()), client.MatchingFields{
+			ClusterNameField: obj.GetName(),
+		})
+		if err != nil {
+			log.FromContext(ctx).Error(err, "list users")
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(users.Items))
+		for _, user := range users.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&user),
+			})
+		}
+
+		return requests
+	})
+
+	return builder.ControllerManagedBy(mgr).
+		For(&apiv1.EtcdUser{}).
+		Watches(&apiv1.EtcdCluster{}, clusterHandler).
+		Complete(reconcile.AsReconciler(mgr.GetClient(), reconciler))
+}
+
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdusers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdusers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=etcd.fleet.agoda.com,resources=etcdusers/finalizers,verbs=update
+
+func (r *UserReconciler) Reconcile(ctx context.Context, user *apiv1.EtcdUser) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.V(3).Info("Reconciling user", "name", user.Name)
+
+	// user is deleted from etcd before finalizer is removed
+	if !user.DeletionTimestamp.IsZero() {
+		err := r.ReconcileDeletion(ctx, user)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("reconcile deletion: %w", err)
+		}
+
+		return reconcile.Result{}, nil
+	}
+
+	err := addAuthFinalizer(ctx, r.kcl, user)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	base := user.DeepCopy()
+
+	err = r.ReconcileUser(ctx, user)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("reconcile user: %w", err)
+	}
+
+	// roles are compared with etcd periodically to revert drift
+	result := reconcile.Result{RequeueAfter: AuthPollInterval}
+	if user.Status.Phase == apiv1.AuthReady {
+		result.RequeueAfter = AuthDriftInterval
+	}
+
+	// bail if status did not change
+	if reflect.DeepEqual(base.Status, user.Status) {
+		return result, nil
+	}
+
+	patch := client.MergeFrom(base)
+	err = r.kcl.Status().Patch(ctx, user, patch)
+	switch {
+	case client.IgnoreNotFound(err) != nil:
+		return reconcile.Result{}, fmt.Errorf("patch user status: %v", err)
+	case err == nil:
+		logger.V(3).Info("patched user status")
+	}
+
+	return result, nil
+}
+
+// ReconcileUser creates etcd user without password and makes its roles match the spec,
+// roles changed outside of the operator are reported as drift
+func (r *UserReconciler) ReconcileUser(ctx context.Context, user *apiv1.EtcdUser) (err error) {
+	status := &user.Status
+	if status.Phase == "" {
+		status.Phase = apiv1.AuthPending
+	}
+	status.Username = Username(user)
+
+	key := client.ObjectKey{
+		Namespace: user.Namespace,
+		Name:      user.Spec.ClusterName,
+	}
+	cluster := &apiv1.EtcdCluster{}
+	err = r.kcl.Get(ctx, key, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+		r.pending(user, "ClusterNotFound", fmt.Sprintf("cluster %q not found", key.Name))
+		return nil
+	case err != nil:
+		return fmt.Errorf("get cluster: %w", err)
+	case cluster.Status.Phase != apiv1.ClusterRunning:
+		r.pending(user, "ClusterPending", fmt.Sprintf("cluster %q is not running", key.Name))
+		return nil
+	}
+
+	// single EtcdUser manages etcd user
+	users := &apiv1.EtcdUserList{}
+	err = r.kcl.List(ctx, users, client.InNamespace(user.Namespace), client.MatchingFields{
+		ClusterNameField: key.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("list users: %w", err)
+	}
+
+	var managing []*apiv1.EtcdUser
+	for i := range users.Items {
+		if Username(&users.Items[i]) == status.Username {
+			managing = append(managing, &users.Items[i])
+		}
+	}
+	if len(managing) != 0 {
+		if owner := authOwner(managing); owner.UID != user.UID {
+			r.pending(user, "Conflict", fmt.Sprintf("user %q is managed by EtcdUser %q", status.Username, owner.Name))
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, 30*time.Second, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		r.pending(user, "NoConnection", err.Error())
+		return nil
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	var current []string
+	var changes []string
+	resp, err := ecl.UserGet(ctx, status.Username)
+	switch {
+	// users authenticate with common name of client certificate
+	case errors.Is(err, rpctypes.ErrUserNotFound):
+		_, err = ecl.UserAddWithOptions(ctx, status.Username, "", &etcdv3.UserAddOptions{NoPassword: true})
+		if err != nil {
+			r.failed(user, fmt.Sprintf("add user: %v", rpctypes.ErrorDesc(err)))
+			return nil
+		}
+
+		changes = append(changes, "created user")
+		r.recorder.Eventf(user, corev1.EventTypeNormal, "Created", "Created user %q", status.Username)
+	case err != nil:
+		r.failed(user, fmt.Sprintf("get user: %v", rpctypes.ErrorDesc(err)))
+		return nil
+	default:
+		current = resp.Roles
+	}
+
+	// root role bypasses permissions, it is granted once standby cluster is promoted
+	roles := UserRoles(user)
+	if Mirroring(cluster) {
+		roles = slices.DeleteFunc(roles, func(role string) bool {
+			return role == RootUser
+		})
+	}
+
+	grant, revoke := DiffRoles(current, roles)
+	for _, role := range revoke {
+		_, err = ecl.UserRevokeRole(ctx, status.Username, role)
+		if err != nil {
+			r.failed(user, fmt.Sprintf("revoke role %q: %v", role, rpctypes.ErrorDesc(err)))
+			return nil
+		}
+		changes = append(changes, fmt.Sprintf("revoked role %q", role))
+	}
+	for _, role := range grant {
+		_, err = ecl.UserGrantRole(ctx, status.Username, role)
+		switch {
+		// role can be created later by EtcdRole
+		case errors.Is(err, rpctypes.ErrRoleNotFound):
+			r.pending(user, "RoleNotFound", fmt.Sprintf("role %q not found", role))
+			return nil
+		case err != nil:
+			r.failed(user, fmt.Sprintf("grant role %q: %v", role, rpctypes.ErrorDesc(err)))
+			return nil
+		}
+		changes = append(changes, fmt.Sprintf("granted role %q", role))
+	}
+
+	// user applied from unchanged spec with the same roles was modified outside of the operator
+	if len(changes) != 0 && status.Phase == apiv1.AuthReady && status.ObservedGeneration == user.Generation && slices.Equal(status.Roles, roles) {
+		status.Drift = strings.Join(changes, ", ")
+		status.DriftTime = ptr.To(metav1.Now())
+		r.recorder.Eventf(user, corev1.EventTypeWarning, "Drift", "Reverted roles of user %q: %s", status.Username, status.Drift)
+	}
+
+	status.Roles = roles
+	status.ObservedGeneration = user.Generation
+	status.Phase = apiv1.AuthReady
+	status.Reason = ""
+	status.Message = ""
+
+	return nil
+}
+
+// ReconcileDeletion deletes etcd user managed by EtcdUser, users of missing clusters are not deleted
+func (r *UserReconciler) ReconcileDeletion(ctx context.Context, user *apiv1.EtcdUser) (err error) {
+	if !controllerutil.ContainsFinalizer(user, apiv1.AuthFinalizer) {
+		return nil
+	}
+
+	cluster := &apiv1.EtcdCluster{}
+	err = r.kcl.Get(ctx, client.ObjectKey{Namespace: user.Namespace, Name: user.Spec.ClusterName}, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("get cluster: %w", err)
+	// user of conflicting EtcdUser was never applied
+	case cluster.DeletionTimestamp.IsZero() && user.Status.ObservedGeneration != 0:
+		err = r.deleteUser(ctx, cluster, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return removeAuthFinalizer(ctx, r.kcl, user)
+}
+
+func (r *UserReconciler) deleteUser(ctx context.Context, cluster *apiv1.EtcdCluster, user *apiv1.EtcdUser) (err error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, 30*time.Second, ErrOperationTimeout)
+	defer cancel()
+
+	ecl, err := connect(ctx, r.tlsCache, cluster)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	_, err = ecl.UserDelete(ctx, Username(user))
+	if err != nil && !errors.Is(err, rpctypes.ErrUserNotFound) {
+		return fmt.Errorf("delete user: %w", err)
+	}
+
+	return nil
+}
+
+// Username returns etcd user of EtcdUser
+func Username(user *apiv1.EtcdUser) string {
+	if user.Spec.Username != "" {
+		return user.Spec.Username
+	}
+
+	return user.Name
+}
+
+// UserRoles returns sorted roles of EtcdUser without duplicates
+func UserRoles(user *apiv1.EtcdUser) []string {
+	roles := slices.Clone(user.Spec.Roles)
+	slices.Sort(roles)
+
+	return slices.Compact(roles)
+}
+
+// DiffRoles returns roles to grant and revoke so that current roles match desired ones
+func DiffRoles(current, desired []string) (grant, revoke []string) {
+	for _, role := range current {
+		if !slices.Contains(desired, role) {
+			revoke = append(revoke, role)
+		}
+	}
+
+	for _, role := range desired {
+		if !slices.Contains(current, role) {
+			grant = append(grant, role)
+		}
+	}
+
+	return grant, revoke
+}
+
+func (r *UserReconciler) pending(user *apiv1.EtcdUser, reason, message string) {
+	user.Status.Phase = apiv1.AuthPending
+	user.Status.Reason = reason
+	user.Status.Message = message
+}
+
+func (r *UserReconciler) failed(user *apiv1.EtcdUser, message string) {
+	user.Status.Phase = apiv1.AuthFailed
+	user.Status.Reason = "EtcdError"
+	user.Status.Message = message
+}
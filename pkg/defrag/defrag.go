@@ -0,0 +1,100 @@
+package defrag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Default thresholds of unused space of member database
+const (
+	DefaultRatio = 0.7
+	DefaultSize  = 128_000_000
+)
+
+// Params are thresholds of unused space of member database
+type Params struct {
+	Ratio float64
+	Size  int64
+}
+
+// Defrag defragments cluster members with unused space above thresholds one at a time, leader is defragmented last.
+// Revisions are not compacted, history is left to auto compaction of members.
+func Defrag(ctx context.Context, ecl *etcdv3.Client, params Params) error {
+	logger := log.FromContext(ctx)
+
+	members, err := ecl.MemberList(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		errs      []error
+		endpoints []string
+		leader    string
+	)
+	for _, member := range members.Members {
+		if member.IsLearner || len(member.ClientURLs) == 0 {
+			continue
+		}
+
+		endpoint := member.ClientURLs[0]
+		memberStatus, err := ecl.Status(ctx, endpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("status: %w", err))
+			continue
+		}
+
+		switch {
+		case !Needed(memberStatus.DbSize, memberStatus.DbSizeInUse, params):
+			logger.Info("skipped", "endpoint", endpoint)
+		case memberStatus.Leader == memberStatus.Header.MemberId:
+			leader = endpoint
+		default:
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	if leader != "" {
+		endpoints = append(endpoints, leader)
+	}
+
+	for _, endpoint := range endpoints {
+		_, err = ecl.Defragment(ctx, endpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("defragment: %w", err))
+			continue
+		}
+
+		logger.Info("defragmented", "endpoint", endpoint)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Needed returns true when unused space of member database exceeds both size and ratio thresholds
+func Needed(size, inUse int64, params Params) bool {
+	unused := size - inUse
+	return size > 0 && unused > params.Size && float64(unused)/float64(size) > params.Ratio
+}
+
+// Compact compacts revisions before the current revision of member at endpoint and waits until compaction is applied,
+// returns the compacted revision. Revision which is already compacted is not an error.
+func Compact(ctx context.Context, ecl *etcdv3.Client, endpoint string) (int64, error) {
+	memberStatus, err := ecl.Status(ctx, endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("status: %w", err)
+	}
+
+	rev := memberStatus.Header.Revision
+	_, err = ecl.Compact(ctx, rev, etcdv3.WithCompactPhysical())
+	if err != nil && !errors.Is(err, rpctypes.ErrCompacted) {
+		return 0, err
+	}
+
+	return rev, nil
+}
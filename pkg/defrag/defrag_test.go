@@ -0,0 +1,48 @@
+package defrag
+
+import "testing"
+
+func TestNeeded(t *testing.T) {
+	params := Params{Ratio: 0.7, Size: 128_000_000}
+
+	tests := []struct {
+		name     string
+		size     int64
+		inUse    int64
+		expected bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:  "compact",
+			size:  1_000_000_000,
+			inUse: 900_000_000,
+		},
+		{
+			name:  "small",
+			size:  100_000_000,
+			inUse: 10_000_000,
+		},
+		{
+			name:  "below ratio",
+			size:  1_000_000_000,
+			inUse: 500_000_000,
+		},
+		{
+			name:     "fragmented",
+			size:     1_000_000_000,
+			inUse:    200_000_000,
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			needed := Needed(tt.size, tt.inUse, params)
+			if needed != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, needed)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ type Config struct {
 	HeartbeatInterval int64 `json:"heartbeat-interval,omitempty"`
 	ElectionTimeout   int64 `json:"election-timeout,omitempty"`
 	QuotaBackendBytes int64 `json:"quota-backend-bytes,omitempty"`
+	MaxRequestBytes   int64 `json:"max-request-bytes,omitempty"`
 
 	ListenPeerURLs    string `json:"listen-peer-urls,omitempty"`
 	ListenClientURLs  string `json:"listen-client-urls,omitempty"`
@@ -28,6 +29,7 @@ type Config struct {
 	InitialCluster      string       `json:"initial-cluster,omitempty"`
 	InitialClusterToken string       `json:"initial-cluster-token,omitempty"`
 	InitialClusterState InitialState `json:"initial-cluster-state,omitempty"`
+	ForceNewCluster     bool         `json:"force-new-cluster,omitempty"`
 
 	ClientTransportSecurity *TransportSecurity `json:"client-transport-security,omitempty"`
 	PeerTransportSecurity   *TransportSecurity `json:"peer-transport-security,omitempty"`
@@ -39,6 +41,13 @@ type Config struct {
 	AutoCompactionMode      string `json:"auto-compaction-mode,omitempty"`
 	AutoCompactionRetention string `json:"auto-compaction-retention,omitempty"`
 
+	GRPCKeepAliveMinTime  time.Duration `json:"grpc-keepalive-min-time,omitempty"`
+	GRPCKeepAliveInterval time.Duration `json:"grpc-keepalive-interval,omitempty"`
+	GRPCKeepAliveTimeout  time.Duration `json:"grpc-keepalive-timeout,omitempty"`
+
+	// MaxLearners is only supported by etcd v3.6 and later
+	MaxLearners int32 `json:"max-learners,omitempty"`
+
 	ExpInitialCorruptCheck         bool          `json:"experimental-initial-corrupt-check,omitempty"`
 	ExpWatchProgressNotifyInterval time.Duration `json:"experimental-watch-progress-notify-interval,omitempty"`
 }
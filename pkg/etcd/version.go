@@ -0,0 +1,47 @@
+package etcd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.etcd.io/etcd/api/v3/version"
+)
+
+// Versions queries server and cluster version from /version endpoint of the member,
+// cluster version is not reported by Status RPC
+func Versions(ctx context.Context, tlsConfig *tls.Config, endpoint string) (*version.Versions, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/version", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	versions := &version.Versions{}
+	err = json.NewDecoder(resp.Body).Decode(versions)
+	if err != nil {
+		return nil, fmt.Errorf("decode versions: %w", err)
+	}
+
+	return versions, nil
+}
@@ -18,8 +18,9 @@ type Observer struct {
 	attributes   attribute.Set
 	registration metric.Registration
 
-	desiredReplicas, replicas, readyReplicas, updatedReplicas, availableReplicas, learnerReplicas metric.Int64ObservableGauge
-	backupLastScheduleTime, backupLastSuccessfulTime                                              metric.Int64ObservableGauge
+	desiredReplicas, replicas, readyReplicas, updatedReplicas, availableReplicas, learnerReplicas, readReplicas metric.Int64ObservableGauge
+	backupLastScheduleTime, backupLastSuccessfulTime                                                            metric.Int64ObservableGauge
+	mirrorLag                                                                                                   metric.Int64ObservableGauge
 }
 
 func Register(meter metric.Meter, key client.ObjectKey) (*Observer, error) {
@@ -65,6 +66,11 @@ func Register(meter metric.Meter, key client.ObjectKey) (*Observer, error) {
 			description: "Number of learner replicas",
 			dest:        &o.learnerReplicas,
 		},
+		{
+			name:        "fleet.etcd.cluster.read_replicas",
+			description: "Number of read replicas",
+			dest:        &o.readReplicas,
+		},
 		{
 			name:        "fleet.etcd.cluster.backup.last_schedule_time",
 			description: "Last backup schedule time",
@@ -75,6 +81,11 @@ func Register(meter metric.Meter, key client.ObjectKey) (*Observer, error) {
 			description: "Last backup successful time",
 			dest:        &o.backupLastSuccessfulTime,
 		},
+		{
+			name:        "fleet.etcd.cluster.mirror.lag",
+			description: "Number of source revisions not yet applied to standby cluster",
+			dest:        &o.mirrorLag,
+		},
 	}
 
 	var observables []metric.Observable
@@ -114,6 +125,7 @@ func (o *Observer) Observe(ctx context.Context, observer metric.Observer) error
 	observer.ObserveInt64(o.updatedReplicas, int64(cluster.Status.UpdatedReplicas), opts...)
 	observer.ObserveInt64(o.availableReplicas, int64(cluster.Status.AvailableReplicas), opts...)
 	observer.ObserveInt64(o.learnerReplicas, int64(cluster.Status.LearnerReplicas), opts...)
+	observer.ObserveInt64(o.readReplicas, int64(cluster.Status.ReadReplicas), opts...)
 
 	if cluster.Status.Backup != nil && cluster.Status.Backup.LastScheduleTime != nil {
 		observer.ObserveInt64(o.backupLastScheduleTime, cluster.Status.Backup.LastScheduleTime.Unix(), opts...)
@@ -123,6 +135,10 @@ func (o *Observer) Observe(ctx context.Context, observer metric.Observer) error
 		observer.ObserveInt64(o.backupLastSuccessfulTime, cluster.Status.Backup.LastSuccessfulTime.Unix(), opts...)
 	}
 
+	if cluster.Status.Mirror != nil && cluster.Status.Mirror.PromotedTime == nil {
+		observer.ObserveInt64(o.mirrorLag, cluster.Status.Mirror.Lag, opts...)
+	}
+
 	return nil
 }
 
@@ -54,11 +54,17 @@ func TestObserver(t *testing.T) {
 			ReadyReplicas:     2,
 			AvailableReplicas: 2,
 			LearnerReplicas:   1,
+			ReadReplicas:      2,
 			UpdatedReplicas:   3,
 			Backup: &apiv1.BackupStatus{
 				LastScheduleTime:   ptr.To(metav1.NewTime(ts)),
 				LastSuccessfulTime: ptr.To(metav1.NewTime(ts)),
 			},
+			Mirror: &apiv1.MirrorStatus{
+				Revision:       120,
+				SourceRevision: 125,
+				Lag:            5,
+			},
 		},
 	}
 
@@ -0,0 +1,307 @@
+package mirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// RevisionKey stores the last source revision applied to the standby cluster,
+	// it is written in the same transaction as mirrored keys so that mirroring resumes without gaps
+	RevisionKey = "/etcd-operator/mirror/revision"
+
+	// MaxTxnOps is the number of mirrored operations per transaction,
+	// one operation of the default etcd limit of 128 is reserved for the revision key
+	MaxTxnOps = 127
+
+	// PageSize is the number of keys fetched per request during base sync
+	PageSize = 1000
+
+	// ProgressInterval is the interval of watch progress requests keeping the synced revision current while there are no changes
+	ProgressInterval = 5 * time.Second
+)
+
+// Syncer mirrors keys with prefix from source to target cluster using watch API
+type Syncer struct {
+	Source *etcdv3.Client
+	Target *etcdv3.Client
+	Prefix string
+}
+
+// Revision returns the last source revision applied to target cluster, zero is returned when mirroring has not started
+func Revision(ctx context.Context, kv etcdv3.KV) (int64, error) {
+	resp, err := kv.Get(ctx, RevisionKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+}
+
+// prefixRange returns the key range of prefix, empty prefix selects all keys as with etcdv3.WithPrefix
+func prefixRange(prefix string) (string, string) {
+	if prefix == "" {
+		return "\x00", "\x00"
+	}
+
+	return prefix, etcdv3.GetPrefixRangeEnd(prefix)
+}
+
+// Run mirrors keys until context is cancelled. Mirroring resumes from the revision stored in target cluster,
+// keys are copied again when that revision has already been compacted in source cluster.
+func (s *Syncer) Run(ctx context.Context) error {
+	logger := log.FromContext(ctx, "prefix", s.Prefix)
+
+	for {
+		rev, err := Revision(ctx, s.Target)
+		if err != nil {
+			return fmt.Errorf("synced revision: %w", err)
+		}
+
+		if rev == 0 {
+			rev, err = s.SyncBase(ctx)
+			if err != nil {
+				return fmt.Errorf("base sync: %w", err)
+			}
+
+			logger.Info("synced base", "revision", rev)
+		}
+
+		logger.Info("watching source", "revision", rev)
+
+		err = s.Watch(ctx, rev)
+		switch {
+		case errors.Is(err, rpctypes.ErrCompacted):
+			logger.Info("synced revision was compacted, copying keys again", "revision", rev)
+
+			_, err = s.Target.Delete(ctx, RevisionKey)
+			if err != nil {
+				return fmt.Errorf("reset synced revision: %w", err)
+			}
+		case ctx.Err() != nil:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+// SyncBase copies keys from the current source revision and deletes keys missing in source from target cluster,
+// revision of the copy is returned
+func (s *Syncer) SyncBase(ctx context.Context) (int64, error) {
+	var (
+		rev  int64
+		ops  []etcdv3.Op
+		seen = map[string]struct{}{}
+	)
+
+	// paginate source keys at the revision of the first page
+	start, end := prefixRange(s.Prefix)
+	key := start
+	for {
+		opts := []etcdv3.OpOption{
+			etcdv3.WithRange(end),
+			etcdv3.WithLimit(PageSize),
+		}
+		if rev != 0 {
+			opts = append(opts, etcdv3.WithRev(rev))
+		}
+
+		resp, err := s.Source.Get(ctx, key, opts...)
+		if err != nil {
+			return 0, err
+		}
+		if rev == 0 {
+			rev = resp.Header.Revision
+		}
+
+		for _, kv := range resp.Kvs {
+			if string(kv.Key) == RevisionKey {
+				continue
+			}
+
+			seen[string(kv.Key)] = struct{}{}
+			ops, err = s.flush(ctx, append(ops, etcdv3.OpPut(string(kv.Key), string(kv.Value))))
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if !resp.More {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+
+	// keys deleted from source while target was not mirroring
+	key = start
+	for {
+		resp, err := s.Target.Get(ctx, key, etcdv3.WithRange(end), etcdv3.WithLimit(PageSize), etcdv3.WithKeysOnly())
+		if err != nil {
+			return 0, err
+		}
+
+		for _, kv := range resp.Kvs {
+			_, ok := seen[string(kv.Key)]
+			if ok || string(kv.Key) == RevisionKey {
+				continue
+			}
+
+			ops, err = s.flush(ctx, append(ops, etcdv3.OpDelete(string(kv.Key))))
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if !resp.More {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+
+	err := s.commit(ctx, ops, rev)
+	if err != nil {
+		return 0, err
+	}
+
+	return rev, nil
+}
+
+// Watch applies source changes after revision until watch fails or context is cancelled
+func (s *Syncer) Watch(ctx context.Context, rev int64) error {
+	ctx, cancel := context.WithCancel(etcdv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	key, end := prefixRange(s.Prefix)
+	wch := s.Source.Watch(ctx, key,
+		etcdv3.WithRange(end),
+		etcdv3.WithRev(rev+1),
+		etcdv3.WithProgressNotify(),
+	)
+
+	ticker := time.NewTicker(ProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			err := s.Source.RequestProgress(ctx)
+			if err != nil {
+				return fmt.Errorf("request progress: %w", err)
+			}
+		case resp, ok := <-wch:
+			if !ok {
+				return ctx.Err()
+			}
+
+			err := resp.Err()
+			if err != nil {
+				return err
+			}
+
+			synced, err := s.apply(ctx, resp, rev)
+			if err != nil {
+				return err
+			}
+			rev = synced
+		}
+	}
+}
+
+// apply mirrors events of watch response, synced revision is returned
+func (s *Syncer) apply(ctx context.Context, resp etcdv3.WatchResponse, rev int64) (int64, error) {
+	if resp.IsProgressNotify() {
+		if resp.Header.Revision <= rev {
+			return rev, nil
+		}
+
+		return resp.Header.Revision, s.commit(ctx, nil, resp.Header.Revision)
+	}
+
+	var (
+		ops  []etcdv3.Op
+		keys = map[string]struct{}{}
+		prev int64
+	)
+	for _, ev := range resp.Events {
+		key := string(ev.Kv.Key)
+
+		// etcd rejects transactions exceeding operation limit or modifying the same key twice,
+		// source revision split across target transactions is only synced once its last event is applied
+		_, duplicate := keys[key]
+		if duplicate || len(ops) == MaxTxnOps {
+			synced := prev
+			if ev.Kv.ModRevision == prev {
+				synced--
+			}
+
+			err := s.commit(ctx, ops, max(synced, rev))
+			if err != nil {
+				return 0, err
+			}
+
+			ops, keys, rev = nil, map[string]struct{}{}, max(synced, rev)
+		}
+
+		prev = ev.Kv.ModRevision
+		if key == RevisionKey {
+			continue
+		}
+
+		keys[key] = struct{}{}
+		switch ev.Type {
+		case mvccpb.PUT:
+			ops = append(ops, etcdv3.OpPut(key, string(ev.Kv.Value)))
+		case mvccpb.DELETE:
+			ops = append(ops, etcdv3.OpDelete(key))
+		}
+	}
+
+	if prev == 0 {
+		return rev, nil
+	}
+
+	rev = max(prev, rev)
+
+	return rev, s.commit(ctx, ops, rev)
+}
+
+// flush commits operations without revision once transaction is full, remaining operations are returned
+func (s *Syncer) flush(ctx context.Context, ops []etcdv3.Op) ([]etcdv3.Op, error) {
+	if len(ops) < MaxTxnOps {
+		return ops, nil
+	}
+
+	_, err := s.Target.Txn(ctx).Then(ops...).Commit()
+	if err != nil {
+		return nil, fmt.Errorf("apply %d operations: %w", len(ops), err)
+	}
+
+	return nil, nil
+}
+
+// commit applies operations together with synced revision
+func (s *Syncer) commit(ctx context.Context, ops []etcdv3.Op, rev int64) error {
+	ops = append(ops, etcdv3.OpPut(RevisionKey, strconv.FormatInt(rev, 10)))
+
+	_, err := s.Target.Txn(ctx).Then(ops...).Commit()
+	if err != nil {
+		return fmt.Errorf("apply revision %d: %w", rev, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package mirror
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	etcdv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestPrefixRange(t *testing.T) {
+	tests := []struct {
+		prefix string
+		key    string
+		end    string
+	}{
+		{prefix: "/app/", key: "/app/", end: "/app0"},
+		{prefix: "", key: "\x00", end: "\x00"},
+	}
+
+	for _, tt := range tests {
+		key, end := prefixRange(tt.prefix)
+		if key != tt.key || end != tt.end {
+			t.Errorf("expected range of %q [%q, %q), got [%q, %q)", tt.prefix, tt.key, tt.end, key, end)
+		}
+	}
+}
+
+func TestSyncer(t *testing.T) {
+	if testing.Short() || os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("envtest is not configured")
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   map[string]string
+	}{
+		{
+			name:   "prefix",
+			prefix: "/app/",
+			want:   map[string]string{"/app/b": "2", "/app/d": "4"},
+		},
+		{
+			name:   "all keys",
+			prefix: "",
+			want:   map[string]string{"/app/b": "2", "/app/d": "4", "/other/c": "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(os.Getenv("KUBEBUILDER_ASSETS"), "etcd")
+			source := setupEtcd(t, &envtest.Etcd{Path: path})
+			target := setupEtcd(t, &envtest.Etcd{Path: path})
+
+			for key, value := range map[string]string{"/app/a": "1", "/app/b": "2", "/other/c": "3"} {
+				_, err := source.Put(t.Context(), key, value)
+				if err != nil {
+					t.Fatal("put:", err)
+				}
+			}
+
+			// stale key is removed by base sync
+			_, err := target.Put(t.Context(), "/app/stale", "0")
+			if err != nil {
+				t.Fatal("put:", err)
+			}
+
+			syncer := &Syncer{
+				Source: source,
+				Target: target,
+				Prefix: tt.prefix,
+			}
+
+			ctx, cancel := context.WithCancel(t.Context())
+			done := make(chan error)
+			go func() {
+				done <- syncer.Run(ctx)
+			}()
+
+			_, err = source.Delete(t.Context(), "/app/a")
+			if err != nil {
+				t.Fatal("delete:", err)
+			}
+			resp, err := source.Put(t.Context(), "/app/d", "4")
+			if err != nil {
+				t.Fatal("put:", err)
+			}
+
+			// wait for the last source revision to be synced
+			deadline := time.Now().Add(30 * time.Second)
+			for {
+				rev, err := Revision(t.Context(), target)
+				if err != nil {
+					t.Fatal("revision:", err)
+				}
+				if rev >= resp.Header.Revision {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("revision %d was not synced, got %d", resp.Header.Revision, rev)
+				}
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			cancel()
+			err = <-done
+			if err != nil {
+				t.Fatal("run:", err)
+			}
+
+			got, err := target.Get(t.Context(), "/", etcdv3.WithPrefix())
+			if err != nil {
+				t.Fatal("get:", err)
+			}
+
+			kvs := map[string]string{}
+			for _, kv := range got.Kvs {
+				if string(kv.Key) != RevisionKey {
+					kvs[string(kv.Key)] = string(kv.Value)
+				}
+			}
+			if len(kvs) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, kvs)
+			}
+			for key, value := range tt.want {
+				if kvs[key] != value {
+					t.Errorf("expected %s=%s, got %q", key, value, kvs[key])
+				}
+			}
+		})
+	}
+}
+
+func setupEtcd(t testing.TB, db *envtest.Etcd) *etcdv3.Client {
+	err := db.Start()
+	if err != nil {
+		t.Fatal("start etcd:", err)
+	}
+	t.Cleanup(func() {
+		err := db.Stop()
+		if err != nil {
+			t.Error("stop etcd:", err)
+		}
+	})
+
+	ecl, err := etcdv3.New(etcdv3.Config{
+		Context:   t.Context(),
+		Endpoints: []string{db.URL.String()},
+		DialOptions: []grpc.DialOption{
+			grpc.WithDisableRetry(),
+		},
+		Logger: zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatal("etcd client:", err)
+	}
+
+	return ecl
+}
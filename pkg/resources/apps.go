@@ -69,6 +69,17 @@ func (b DeploymentBuilder) Selector(label, value string) DeploymentBuilder {
 	return b
 }
 
+// SelectorNotIn excludes pods with label set to one of values, pod template is left unchanged
+func (b DeploymentBuilder) SelectorNotIn(label string, values ...string) DeploymentBuilder {
+	b.Spec.Selector.MatchExpressions = append(b.Spec.Selector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      label,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   values,
+	})
+
+	return b
+}
+
 func (b DeploymentBuilder) PodLabels(labels map[string]string) DeploymentBuilder {
 	if b.Spec.Template.Labels == nil {
 		b.Spec.Template.Labels = map[string]string{}
@@ -94,3 +105,95 @@ func (b DeploymentBuilder) PodSpec(spec corev1.PodSpec) DeploymentBuilder {
 
 	return b
 }
+
+type StatefulSetBuilder struct{ *appsv1.StatefulSet }
+
+func (b *Builder) StatefulSet(names ...string) StatefulSetBuilder {
+	s := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.name(names),
+			Namespace: b.owner.GetNamespace(),
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{},
+				},
+			},
+		},
+	}
+	b.add(s)
+
+	return StatefulSetBuilder{
+		StatefulSet: s,
+	}
+}
+
+func (b StatefulSetBuilder) Replicas(replicas int32) StatefulSetBuilder {
+	b.Spec.Replicas = ptr.To(replicas)
+
+	return b
+}
+
+func (b StatefulSetBuilder) ServiceName(name string) StatefulSetBuilder {
+	b.Spec.ServiceName = name
+
+	return b
+}
+
+func (b StatefulSetBuilder) PodManagementPolicy(policy appsv1.PodManagementPolicyType) StatefulSetBuilder {
+	b.Spec.PodManagementPolicy = policy
+
+	return b
+}
+
+func (b StatefulSetBuilder) RetentionPolicy(whenDeleted, whenScaled appsv1.PersistentVolumeClaimRetentionPolicyType) StatefulSetBuilder {
+	b.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: whenDeleted,
+		WhenScaled:  whenScaled,
+	}
+
+	return b
+}
+
+func (b StatefulSetBuilder) Selector(label, value string) StatefulSetBuilder {
+	b.Spec.Selector.MatchLabels[label] = value
+	b.Spec.Template.Labels[label] = value
+
+	return b
+}
+
+func (b StatefulSetBuilder) PodLabels(labels map[string]string) StatefulSetBuilder {
+	if b.Spec.Template.Labels == nil {
+		b.Spec.Template.Labels = map[string]string{}
+	}
+
+	maps.Copy(b.Spec.Template.Labels, labels)
+
+	return b
+}
+
+func (b StatefulSetBuilder) PodAnnotations(annotations map[string]string) StatefulSetBuilder {
+	if b.Spec.Template.Annotations == nil {
+		b.Spec.Template.Annotations = make(map[string]string)
+	}
+
+	maps.Copy(b.Spec.Template.Annotations, annotations)
+
+	return b
+}
+
+func (b StatefulSetBuilder) PodSpec(spec corev1.PodSpec) StatefulSetBuilder {
+	b.Spec.Template.Spec = spec
+
+	return b
+}
+
+func (b StatefulSetBuilder) VolumeClaim(claim corev1.PersistentVolumeClaim) StatefulSetBuilder {
+	b.Spec.VolumeClaimTemplates = append(b.Spec.VolumeClaimTemplates, claim)
+
+	return b
+}
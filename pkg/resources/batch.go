@@ -76,6 +76,36 @@ func (b JobBuilder) TTL(ttl time.Duration) JobBuilder {
 	return b
 }
 
+func (b JobBuilder) ActiveDeadline(duration time.Duration) JobBuilder {
+	b.Spec.ActiveDeadlineSeconds = ptr.To(int64(duration.Seconds()))
+	return b
+}
+
+func (b JobBuilder) BackoffLimit(limit int32) JobBuilder {
+	b.Spec.BackoffLimit = ptr.To(limit)
+	return b
+}
+
+func (b JobBuilder) PodLabels(labels map[string]string) JobBuilder {
+	if b.Spec.Template.Labels == nil {
+		b.Spec.Template.Labels = map[string]string{}
+	}
+
+	maps.Copy(b.Spec.Template.Labels, labels)
+
+	return b
+}
+
+func (b JobBuilder) PodAnnotations(annotations map[string]string) JobBuilder {
+	if b.Spec.Template.Annotations == nil {
+		b.Spec.Template.Annotations = make(map[string]string)
+	}
+
+	maps.Copy(b.Spec.Template.Annotations, annotations)
+
+	return b
+}
+
 func (b CronJobBuilder) ConcurrencyPolicy(policy batchv1.ConcurrencyPolicy) CronJobBuilder {
 	b.Spec.ConcurrencyPolicy = policy
 	return b
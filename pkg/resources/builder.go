@@ -67,6 +67,11 @@ func (b *Builder) Delete(obj client.Object) *Builder {
 	return b
 }
 
+// Objects returns objects added to the builder
+func (b *Builder) Objects() []client.Object {
+	return b.children
+}
+
 func (b *Builder) Build(scheme *runtime.Scheme) error {
 	for _, child := range b.children {
 		if child.GetNamespace() != "" {
@@ -43,6 +43,16 @@ func (b *Builder) Secret(names ...string) SecretBuilder {
 	}
 }
 
+func (b SecretBuilder) Data(data map[string][]byte) SecretBuilder {
+	if b.Secret.Data == nil {
+		b.Secret.Data = map[string][]byte{}
+	}
+
+	maps.Copy(b.Secret.Data, data)
+
+	return b
+}
+
 func (b SecretBuilder) StringData(data map[string]string) SecretBuilder {
 	if b.Secret.StringData == nil {
 		b.Secret.StringData = map[string]string{}
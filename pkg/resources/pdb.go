@@ -34,6 +34,16 @@ func (b *PdbBuilder) Selector(label, value string) *PdbBuilder {
 	return b
 }
 
+// SelectorNotIn excludes pods with label set to one of values
+func (b *PdbBuilder) SelectorNotIn(label string, values ...string) *PdbBuilder {
+	b.Spec.Selector.MatchExpressions = append(b.Spec.Selector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      label,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   values,
+	})
+	return b
+}
+
 func (b *PdbBuilder) UnhealthyPodEvictionPolicy(v policyv1.UnhealthyPodEvictionPolicyType) *PdbBuilder {
 	b.Spec.UnhealthyPodEvictionPolicy = &v
 	return b
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,9 +13,12 @@ import (
 
 type CertificateBuilder struct{ *cmv1.Certificate }
 
-func (b *Builder) CA(names ...string) {
-	b.Certificate(names...).CA().Duration(5 * 365 * 24 * time.Hour)
+func (b *Builder) CA(duration time.Duration, names ...string) CertificateBuilder {
+	// renewal is the last resort, CAs are expected to be rotated by the owner before
+	cert := b.Certificate(names...).CA().Duration(duration).RenewBefore(min(90*24*time.Hour, duration/10))
 	b.Issuer(names...)
+
+	return cert
 }
 
 func (b *Builder) SelfSign() *cmv1.Issuer {
@@ -68,6 +72,11 @@ func (c CertificateBuilder) Issuer(names ...string) CertificateBuilder {
 	return c
 }
 
+func (c CertificateBuilder) IssuerRef(ref cmmeta.ObjectReference) CertificateBuilder {
+	c.Spec.IssuerRef = ref
+	return c
+}
+
 func (c CertificateBuilder) CA() CertificateBuilder {
 	c.Spec.IsCA = true
 	c.Spec.IssuerRef = cmmeta.ObjectReference{
@@ -83,6 +92,36 @@ func (c CertificateBuilder) Duration(duration time.Duration) CertificateBuilder
 	return c
 }
 
+func (c CertificateBuilder) RenewBefore(duration time.Duration) CertificateBuilder {
+	c.Spec.RenewBefore = &metav1.Duration{Duration: duration}
+	return c
+}
+
+func (c CertificateBuilder) PrivateKey(algorithm cmv1.PrivateKeyAlgorithm, size int) CertificateBuilder {
+	c.Spec.PrivateKey.Algorithm = algorithm
+	c.Spec.PrivateKey.Size = size
+
+	// key encipherment only applies to RSA keys
+	if algorithm != cmv1.RSAKeyAlgorithm {
+		c.Spec.Usages = slices.DeleteFunc(c.Spec.Usages, func(usage cmv1.KeyUsage) bool {
+			return usage == cmv1.UsageKeyEncipherment
+		})
+	}
+
+	return c
+}
+
+// RotateKey generates a new private key whenever certificate is reissued
+func (c CertificateBuilder) RotateKey() CertificateBuilder {
+	c.Spec.PrivateKey.RotationPolicy = cmv1.RotationPolicyAlways
+	return c
+}
+
+func (c CertificateBuilder) SecretName(name string) CertificateBuilder {
+	c.Spec.SecretName = name
+	return c
+}
+
 func (c CertificateBuilder) Usages(usages ...cmv1.KeyUsage) CertificateBuilder {
 	c.Spec.Usages = append(c.Spec.Usages, usages...)
 	return c
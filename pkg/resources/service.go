@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"maps"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -28,6 +30,20 @@ func (sb ServiceBuilder) Headless(publishNotReady bool) ServiceBuilder {
 	return sb
 }
 
+func (sb ServiceBuilder) Type(tpe corev1.ServiceType) ServiceBuilder {
+	sb.Spec.Type = tpe
+	return sb
+}
+
+func (sb ServiceBuilder) Annotations(annotations map[string]string) ServiceBuilder {
+	if sb.Service.Annotations == nil {
+		sb.Service.Annotations = map[string]string{}
+	}
+
+	maps.Copy(sb.Service.Annotations, annotations)
+	return sb
+}
+
 func (b *Builder) Service(name ...string) ServiceBuilder {
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
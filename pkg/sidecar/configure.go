@@ -2,12 +2,12 @@ package sidecar
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"maps"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -44,18 +44,32 @@ func (s *Sidecar) Configure(ctx context.Context) error {
 
 	etcdConfig.Name = s.pod.Name
 	etcdConfig.AdvertiseClientURLs = fmt.Sprintf("https://%s:2379", s.pod.Status.PodIP)
-	etcdConfig.InitialAdvertisePeerURLs = fmt.Sprintf("https://%s:2380", s.pod.Status.PodIP)
+	etcdConfig.InitialAdvertisePeerURLs = s.PeerURL()
+
+	// restart persistent member in place
+	var restarted *etcdserverpb.Member
+	if s.config.Persistent {
+		restarted, err = s.Rejoin(ctx)
+		if err != nil {
+			return fmt.Errorf("rejoin: %w", err)
+		}
+	}
 
 	pod := &s.pod
 	patch := client.StrategicMergeFrom(pod.DeepCopy())
 	labels := maps.Clone(pod.Labels)
-	switch s.etcdConfig.InitialClusterState {
+	switch {
+	case restarted != nil:
+		labels[apiv1.LearnerLabel] = strconv.FormatBool(restarted.IsLearner)
+		if restarted.ID != 0 {
+			labels[apiv1.MemberIDLabel] = apiv1.FormatMemberID(restarted.ID)
+		}
 	// bootstrap
-	case etcd.InitialStateNew:
+	case s.etcdConfig.InitialClusterState == etcd.InitialStateNew:
 		s.etcdConfig.InitialCluster = fmt.Sprintf("%s=https://%s:2380", s.pod.Name, s.pod.Status.PodIP)
 		labels[apiv1.LearnerLabel] = "false"
 	// add learner if we're joining existing cluster
-	case etcd.InitialStateExisiting:
+	case s.etcdConfig.InitialClusterState == etcd.InitialStateExisiting:
 		member, err := s.AddLearner(ctx)
 		if err != nil {
 			return fmt.Errorf("add learner: %w", err)
@@ -67,10 +81,9 @@ func (s *Sidecar) Configure(ctx context.Context) error {
 		return fmt.Errorf("invalid initial-cluster-state %q", s.etcdConfig.InitialClusterState)
 	}
 
-	data, err := json.MarshalIndent(s.etcdConfig, "", "\t")
-	if err != nil {
-		return err
-	}
+	// recovery requested before member was started is not applied
+	s.forcedCluster = pod.Annotations[apiv1.ForceNewClusterAnnotation]
+	s.etcdConfig.ForceNewCluster = false
 
 	if !maps.Equal(labels, pod.Labels) {
 		pod.Labels = labels
@@ -80,9 +93,9 @@ func (s *Sidecar) Configure(ctx context.Context) error {
 		}
 	}
 
-	err = os.WriteFile(s.config.ConfigFile, data, 0644)
+	err = s.WriteConfig()
 	if err != nil {
-		return fmt.Errorf("write config: %w", err)
+		return err
 	}
 
 	logger.Info("config written", "path", s.config.ConfigFile)
@@ -157,3 +170,112 @@ func (s *Sidecar) AddLearner(ctx context.Context) (*etcdserverpb.Member, error)
 
 	return member, err
 }
+
+// PeerURL returns stable peer url of statefulset member or pod ip based url otherwise
+func (s *Sidecar) PeerURL() string {
+	if s.pod.Spec.Subdomain != "" {
+		return fmt.Sprintf("https://%s.%s.%s.svc.%s:2380", s.pod.Name, s.pod.Spec.Subdomain, s.pod.Namespace, s.config.ClusterDomain)
+	}
+
+	return fmt.Sprintf("https://%s:2380", s.pod.Status.PodIP)
+}
+
+// Rejoin returns the member to restart in place when data dir of the previous run exists.
+// Data of a member removed from cluster is discarded, as well as a member registered without data,
+// so that member can join as a learner again.
+func (s *Sidecar) Rejoin(ctx context.Context) (*etcdserverpb.Member, error) {
+	logger := log.FromContext(ctx).WithName("rejoin")
+
+	_, err := os.Stat(filepath.Join(s.etcdConfig.DataDir, "member"))
+	exists := err == nil
+	switch {
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("stat data dir: %w", err)
+	// nothing to rejoin while bootstrapping
+	case !exists && s.etcdConfig.InitialClusterState == etcd.InitialStateNew:
+		return nil, nil
+	}
+
+	// single member restarts during bootstrap
+	self := &etcdserverpb.Member{
+		Name:     s.pod.Name,
+		PeerURLs: []string{s.etcdConfig.InitialAdvertisePeerURLs},
+	}
+	if exists && s.etcdConfig.InitialClusterState == etcd.InitialStateNew {
+		s.etcdConfig.InitialCluster = self.Name + "=" + self.PeerURLs[0]
+		logger.Info("restarting bootstrap member")
+		return self, nil
+	}
+
+	ecl, err := etcd.Connect(ctx, &s.tlsConfig, s.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	lctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	members, err := ecl.MemberList(lctx)
+	switch {
+	// cluster is not reachable when all members are restarted, quorum is restored once they are up
+	case err != nil && exists:
+		logger.Info("cluster not reachable, restarting with existing data", "reason", err.Error())
+		s.etcdConfig.InitialCluster = self.Name + "=" + self.PeerURLs[0]
+		return self, nil
+	case err != nil:
+		return nil, fmt.Errorf("query member list: %w", err)
+	}
+
+	i := slices.IndexFunc(members.Members, func(member *etcdserverpb.Member) bool {
+		return member.Name == s.pod.Name || slices.Contains(member.PeerURLs, s.etcdConfig.InitialAdvertisePeerURLs)
+	})
+
+	switch {
+	// member was removed while it was down - discard its data
+	case i == -1 && exists:
+		logger.Info("member not found, removing data", "dir", s.etcdConfig.DataDir)
+		err = os.RemoveAll(s.etcdConfig.DataDir)
+		if err != nil {
+			return nil, fmt.Errorf("remove data dir: %w", err)
+		}
+		return nil, nil
+	case i == -1:
+		return nil, nil
+	// member without data can not restart, remove it so that it can be added as learner
+	case !exists:
+		member := members.Members[i]
+		_, err = ecl.MemberRemove(lctx, member.ID)
+		if err != nil && !errors.Is(err, rpctypes.ErrMemberNotFound) {
+			return nil, fmt.Errorf("remove stale member: %w", err)
+		}
+		logger.Info("removed stale member", "id", apiv1.FormatMemberID(member.ID))
+		return nil, nil
+	}
+
+	member := members.Members[i]
+	if !slices.Equal(member.PeerURLs, []string{s.etcdConfig.InitialAdvertisePeerURLs}) {
+		_, err = ecl.MemberUpdate(lctx, member.ID, []string{s.etcdConfig.InitialAdvertisePeerURLs})
+		if err != nil {
+			return nil, fmt.Errorf("update peer urls: %w", err)
+		}
+		logger.Info("updated peer urls", "id", apiv1.FormatMemberID(member.ID), "url", s.etcdConfig.InitialAdvertisePeerURLs)
+	}
+
+	endpoints := []string{}
+	for _, m := range members.Members {
+		switch {
+		case m.ID == member.ID:
+			endpoints = append(endpoints, s.pod.Name+"="+s.etcdConfig.InitialAdvertisePeerURLs)
+		case m.Name != "" && len(m.PeerURLs) != 0:
+			endpoints = append(endpoints, m.Name+"="+m.PeerURLs[0])
+		}
+	}
+	s.etcdConfig.InitialCluster = strings.Join(endpoints, ",")
+
+	logger.Info("restarting member", "id", apiv1.FormatMemberID(member.ID))
+
+	return member, nil
+}
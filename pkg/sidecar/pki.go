@@ -1,6 +1,7 @@
 package sidecar
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/rand"
@@ -8,12 +9,15 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"syscall"
+	"slices"
+	"strings"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,34 +55,89 @@ func (s *Sidecar) GenerateCredentials(ctx context.Context) error {
 		logger.Info("expired", "renewAt", renewAt)
 	}
 
+	// CAs trusted next to CAs of the issuers while CAs are rotated
+	var bundle map[string]string
+	if s.config.CABundle != "" {
+		configMap := &corev1.ConfigMap{}
+		err = s.kcl.Get(ctx, client.ObjectKey{Namespace: s.pod.Namespace, Name: s.config.CABundle}, configMap)
+		if err != nil {
+			return fmt.Errorf("get ca bundle: %w", err)
+		}
+
+		bundle = configMap.Data
+	}
+
 	b := resources.NewBuilder(pod).
 		Label(apiv1.ClusterLabel, s.pod.Labels[apiv1.ClusterLabel])
 
 	// peer certificate prototype
 	peerCert := b.Certificate("peer").
-		Duration(cmv1.DefaultCertificateDuration).
-		Issuer(cluster.Name, "peer-ca").
+		Duration(s.config.Duration).
+		IssuerRef(IssuerRef(s.config.PeerIssuer, cluster.Name+"-peer-ca")).
 		Usages(cmv1.UsageServerAuth, cmv1.UsageClientAuth).
 		IP(s.pod.Status.PodIP)
 
+	// stable peer name of statefulset member
+	if s.pod.Spec.Subdomain != "" {
+		peerCert.DNS(s.pod.Name, s.pod.Spec.Subdomain, s.pod.Namespace, "svc", s.config.ClusterDomain)
+	}
+
 	// server cert prototype
 	serverCert := b.Certificate("server").
-		Duration(cmv1.DefaultCertificateDuration).
-		Issuer(cluster.Name, "server-ca").
+		Duration(s.config.Duration).
+		IssuerRef(IssuerRef(s.config.ServerIssuer, cluster.Name+"-server-ca")).
 		Usages(cmv1.UsageServerAuth, cmv1.UsageClientAuth).
 		IP(s.pod.Status.PodIP).
-		DNS(s.pod.Name, cluster.Name, cluster.Namespace, "svc.cluster.local").
-		DNS(cluster.Name, cluster.Namespace, "svc.cluster.local").
+		DNS(s.pod.Name, cluster.Name, cluster.Namespace, "svc", s.config.ClusterDomain).
+		DNS(cluster.Name, cluster.Namespace, "svc", s.config.ClusterDomain).
 		IP("127.0.0.1").
 		DNS("localhost").
 		DNS(s.pod.Name)
 
+	// members authenticate with common name of server certificate
+	if s.config.CommonName != "" {
+		serverCert.CommonName(s.config.CommonName)
+	}
+
+	// read replica service
+	if s.pod.Labels[apiv1.ReadReplicaLabel] == "true" {
+		serverCert.DNS(cluster.Name+"-replica", cluster.Namespace, "svc", s.config.ClusterDomain)
+	}
+
+	// names of external service
+	for _, name := range s.config.DNSNames {
+		serverCert.DNS(name)
+	}
+	for _, ip := range s.config.IPAddresses {
+		serverCert.IP(ip)
+	}
+
+	for _, cert := range []resources.CertificateBuilder{peerCert, serverCert} {
+		cert.PrivateKey(cmv1.PrivateKeyAlgorithm(s.config.KeyAlgorithm), s.config.KeySize)
+		if s.config.RenewBefore != 0 {
+			cert.RenewBefore(s.config.RenewBefore)
+		}
+	}
+
 	// we only build those objects, they are not applied
 	err = b.Build(s.kcl.Scheme())
 	if err != nil {
 		return fmt.Errorf("build certificates: %w", err)
 	}
 
+	// CAs loaded by running etcd, files do not exist before the first start
+	caFiles := []string{
+		s.etcdConfig.ClientTransportSecurity.TrustedCAFile,
+		s.etcdConfig.PeerTransportSecurity.TrustedCAFile,
+	}
+	cas := make([][]byte, len(caFiles))
+	for i, name := range caFiles {
+		cas[i], err = os.ReadFile(name)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("read ca: %w", err)
+		}
+	}
+
 	wg, wctx := errgroup.WithContext(ctx)
 
 	// generate peer credentials
@@ -87,6 +146,7 @@ func (s *Sidecar) GenerateCredentials(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("peer: %w", err)
 		}
+		creds.CACert = trustBundle(creds.CACert, bundle[apiv1.PeerCABundleKey])
 
 		// write out the credentials and update config
 		err = creds.WriteTransportSecurity(*s.etcdConfig.PeerTransportSecurity)
@@ -105,6 +165,7 @@ func (s *Sidecar) GenerateCredentials(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("server: %w", err)
 		}
+		creds.CACert = trustBundle(creds.CACert, bundle[apiv1.ServerCABundleKey])
 
 		// write out the credentials and update config
 		err = creds.WriteTransportSecurity(*s.etcdConfig.ClientTransportSecurity)
@@ -136,34 +197,50 @@ func (s *Sidecar) GenerateCredentials(ctx context.Context) error {
 		}
 	}
 
-	// try to restart etcd container if CA has changed
-	// https://github.com/etcd-io/etcd/pull/16500 is stuck in purgatory
-	reload := false
-	caFiles := []string{
-		s.etcdConfig.ClientTransportSecurity.TrustedCAFile,
-		s.etcdConfig.PeerTransportSecurity.TrustedCAFile,
-	}
-	for _, name := range caFiles {
-		info, err := os.Stat(name)
+	// etcd loads CAs on start only, https://github.com/etcd-io/etcd/pull/16500 is stuck in purgatory
+	for i, name := range caFiles {
+		data, err := os.ReadFile(name)
 		if err != nil {
-			return fmt.Errorf("stat ca: %w", err)
+			return fmt.Errorf("read ca: %w", err)
 		}
 
-		if info.ModTime().After(start) {
-			reload = true
-			break
-		}
-	}
-	if reload {
-		err = syscall.Kill(1, syscall.SIGKILL)
-		if err != nil && !errors.Is(err, syscall.ENOENT) {
-			logger.Error(err, "restart etcd container")
+		if len(cas[i]) != 0 && !bytes.Equal(cas[i], data) {
+			logger.Info("ca changed", "file", name)
+			return s.RequestRestart(ctx)
 		}
 	}
 
 	return nil
 }
 
+// IssuerRef parses issuer formatted as kind/name, issuer with given name is returned when value is empty
+func IssuerRef(value, name string) cmmetav1.ObjectReference {
+	ref := cmmetav1.ObjectReference{
+		Name:  name,
+		Kind:  cmv1.IssuerKind,
+		Group: cmv1.SchemeGroupVersion.Group,
+	}
+
+	if kind, issuer, ok := strings.Cut(value, "/"); ok {
+		ref.Kind = kind
+		ref.Name = issuer
+	}
+
+	return ref
+}
+
+// trustBundle appends CA of the issuer to the bundle unless it is already included
+func trustBundle(ca []byte, bundle string) []byte {
+	switch {
+	case bundle == "":
+		return ca
+	case bytes.Contains([]byte(bundle), ca):
+		return []byte(bundle)
+	default:
+		return slices.Concat([]byte(bundle), ca)
+	}
+}
+
 // GenerateCredentials generates tls credentials using CertificateRequest created from provided Certificate
 func GenerateCredentials(ctx context.Context, kcl client.Client, crt *cmv1.Certificate, interval time.Duration) (*etcd.Credentials, error) {
 	pk, err := pki.GeneratePrivateKeyForCertificate(crt)
@@ -203,10 +280,26 @@ func GenerateCredentials(ctx context.Context, kcl client.Client, crt *cmv1.Certi
 		Key:     pkData,
 		Cert:    cr.Status.Certificate,
 		CACert:  cr.Status.CA,
-		RenewAt: cr.CreationTimestamp.Add(cr.Spec.Duration.Duration * 3 / 4),
+		RenewAt: RenewAt(cr.CreationTimestamp.Time, crt),
 	}, nil
 }
 
+// RenewAt returns renewal time of certificate issued at given time, certificates are renewed
+// a quarter of their duration before expiry unless renew before is set
+func RenewAt(issued time.Time, crt *cmv1.Certificate) time.Time {
+	duration := cmv1.DefaultCertificateDuration
+	if crt.Spec.Duration != nil {
+		duration = crt.Spec.Duration.Duration
+	}
+
+	renewBefore := duration / 4
+	if crt.Spec.RenewBefore != nil && crt.Spec.RenewBefore.Duration < duration {
+		renewBefore = crt.Spec.RenewBefore.Duration
+	}
+
+	return issued.Add(duration - renewBefore)
+}
+
 func GenerateCertificateRequest(crt *cmv1.Certificate, signer crypto.Signer) (*cmv1.CertificateRequest, error) {
 	csr, err := pki.GenerateCSR(crt)
 	if err != nil {
@@ -0,0 +1,43 @@
+package sidecar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// procDir is the mount point of proc filesystem
+var procDir = "/proc"
+
+// SignalEtcd sends signal to etcd process running in the shared process namespace of the pod
+func SignalEtcd(sig syscall.Signal) error {
+	paths, err := filepath.Glob(filepath.Join(procDir, "[0-9]*", "comm"))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range paths {
+		comm, err := os.ReadFile(name)
+		if err != nil || strings.TrimSpace(string(comm)) != "etcd" {
+			continue
+		}
+
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(name)))
+		if err != nil {
+			continue
+		}
+
+		err = syscall.Kill(pid, sig)
+		if err != nil && !errors.Is(err, syscall.ESRCH) {
+			return fmt.Errorf("kill %d: %w", pid, err)
+		}
+
+		return nil
+	}
+
+	return errors.New("etcd process not found")
+}
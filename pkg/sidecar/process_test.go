@@ -0,0 +1,100 @@
+package sidecar
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+// fakeProc replaces proc filesystem with a directory listing given processes
+func fakeProc(t *testing.T, procs map[int]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for pid, comm := range procs {
+		err := os.Mkdir(filepath.Join(dir, strconv.Itoa(pid)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(filepath.Join(dir, strconv.Itoa(pid), "comm"), []byte(comm+"\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	previous := procDir
+	procDir = dir
+	t.Cleanup(func() {
+		procDir = previous
+	})
+}
+
+// startProcess starts a process standing in for etcd
+func startProcess(t *testing.T) *exec.Cmd {
+	t.Helper()
+
+	cmd := exec.Command("sleep", "60")
+	err := cmd.Start()
+	if err != nil {
+		t.Skip("start process:", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	return cmd
+}
+
+// signaled returns signal the process was terminated with
+func signaled(t *testing.T, cmd *exec.Cmd) syscall.Signal {
+	t.Helper()
+
+	exitErr := &exec.ExitError{}
+	err := cmd.Wait()
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected process to be terminated, got %v", err)
+	}
+
+	return exitErr.Sys().(syscall.WaitStatus).Signal()
+}
+
+func TestSignalEtcd(t *testing.T) {
+	etcd := startProcess(t)
+	other := startProcess(t)
+	fakeProc(t, map[int]string{
+		other.Process.Pid: "sidecar",
+		etcd.Process.Pid:  "etcd",
+	})
+
+	err := SignalEtcd(syscall.SIGTERM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sig := signaled(t, etcd); sig != syscall.SIGTERM {
+		t.Errorf("expected etcd to be terminated by %s, got %s", syscall.SIGTERM, sig)
+	}
+
+	// other processes are left running
+	err = other.Process.Signal(syscall.Signal(0))
+	if err != nil {
+		t.Errorf("expected other process to be running, got %v", err)
+	}
+}
+
+func TestSignalEtcdNotFound(t *testing.T) {
+	fakeProc(t, map[int]string{
+		1: "sidecar",
+	})
+
+	err := SignalEtcd(syscall.SIGTERM)
+	if err == nil {
+		t.Error("expected error when etcd process is not running")
+	}
+}
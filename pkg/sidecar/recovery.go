@@ -0,0 +1,64 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"syscall"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+// ForceNewCluster restarts etcd with force-new-cluster when requested by the operator through pod annotation,
+// member keeps its data and becomes the only member of the cluster
+func (s *Sidecar) ForceNewCluster(ctx context.Context) error {
+	pod := &s.pod
+	err := s.kcl.Get(ctx, s.config.ObjectKey, pod)
+	if err != nil {
+		return err
+	}
+
+	value, ok := pod.Annotations[apiv1.ForceNewClusterAnnotation]
+	if !ok || value == s.forcedCluster {
+		return nil
+	}
+
+	logger := log.FromContext(ctx).WithName("force-new-cluster").WithValues("attempt", value)
+
+	s.etcdConfig.ForceNewCluster = true
+	s.etcdConfig.InitialCluster = s.pod.Name + "=" + s.etcdConfig.InitialAdvertisePeerURLs
+
+	err = s.WriteConfig()
+	if err != nil {
+		return err
+	}
+
+	s.forcedCluster = value
+
+	err = SignalEtcd(syscall.SIGTERM)
+	if err != nil {
+		return fmt.Errorf("restart etcd: %w", err)
+	}
+
+	logger.Info("restarting etcd as new cluster")
+
+	return nil
+}
+
+// WriteConfig writes etcd config file used on the next etcd start
+func (s *Sidecar) WriteConfig() error {
+	data, err := json.MarshalIndent(s.etcdConfig, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(s.config.ConfigFile, data, 0644)
+	if err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+
+	return nil
+}
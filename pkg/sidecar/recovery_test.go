@@ -0,0 +1,100 @@
+package sidecar
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+func TestForceNewCluster(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster-abc",
+			Namespace: "default",
+		},
+	}
+	kcl := fake.NewClientBuilder().
+		WithScheme(kscheme.Scheme).
+		WithObjects(pod).
+		Build()
+
+	configFile := filepath.Join(t.TempDir(), "etcd.json")
+	s := &Sidecar{
+		kcl: kcl,
+		config: Config{
+			ObjectKey:  client.ObjectKeyFromObject(pod),
+			ConfigFile: configFile,
+		},
+		etcdConfig: etcd.Config{
+			InitialAdvertisePeerURLs: "https://10.0.0.1:2380",
+			InitialCluster:           "test-cluster-abc=https://10.0.0.1:2380,test-cluster-def=https://10.0.0.2:2380",
+		},
+	}
+
+	// no recovery requested
+	err := s.ForceNewCluster(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Stat(configFile)
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected config not to be written, got %v", err)
+	}
+
+	// recovery requested by the operator
+	etcdProcess := startProcess(t)
+	fakeProc(t, map[int]string{
+		etcdProcess.Process.Pid: "etcd",
+	})
+
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, apiv1.ForceNewClusterAnnotation, "1")
+	err = kcl.Update(t.Context(), pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.ForceNewCluster(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sig := signaled(t, etcdProcess); sig != syscall.SIGTERM {
+		t.Errorf("expected etcd to be terminated by %s, got %s", syscall.SIGTERM, sig)
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := etcd.Config{}
+	err = json.Unmarshal(data, &config)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case !config.ForceNewCluster:
+		t.Error("expected force-new-cluster to be set")
+	case config.InitialCluster != "test-cluster-abc=https://10.0.0.1:2380":
+		t.Errorf("expected initial cluster of the member only, got %q", config.InitialCluster)
+	}
+
+	// the same attempt is applied once, signaling etcd again would fail
+	fakeProc(t, nil)
+	err = s.ForceNewCluster(t.Context())
+	if err != nil {
+		t.Errorf("expected attempt not to be applied again, got %v", err)
+	}
+}
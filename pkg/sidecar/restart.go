@@ -0,0 +1,168 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	"github.com/agoda-com/etcd-operator/pkg/etcd"
+)
+
+// RequestRestart asks the operator for restart slot through pod annotation, the earliest request is kept
+func (s *Sidecar) RequestRestart(ctx context.Context) error {
+	pod := &corev1.Pod{}
+	err := s.kcl.Get(ctx, s.config.ObjectKey, pod)
+	if err != nil {
+		return err
+	}
+
+	if pod.Annotations[apiv1.RestartRequestedAnnotation] != "" {
+		return nil
+	}
+
+	patch := client.StrategicMergeFrom(pod.DeepCopy())
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, apiv1.RestartRequestedAnnotation, time.Now().UTC().Format(time.RFC3339))
+	err = s.kcl.Patch(ctx, pod, patch)
+	if err != nil {
+		return fmt.Errorf("patch pod annotations: %w", err)
+	}
+
+	log.FromContext(ctx).Info("requested restart")
+
+	return nil
+}
+
+// Restart gracefully restarts etcd once the operator grants restart slot to the member,
+// leadership is transferred before etcd is stopped and the slot is released once etcd is ready again
+func (s *Sidecar) Restart(ctx context.Context) error {
+	pod := &corev1.Pod{}
+	err := s.kcl.Get(ctx, s.config.ObjectKey, pod)
+	if err != nil {
+		return err
+	}
+
+	logger := log.FromContext(ctx).WithName("restart")
+
+	switch {
+	case pod.Annotations[apiv1.RestartRequestedAnnotation] == "":
+		return nil
+	case pod.Annotations[apiv1.RestartGrantedAnnotation] == "":
+		logger.V(3).Info("waiting for restart slot")
+		return nil
+	}
+
+	err = s.TransferLeadership(ctx)
+	if err != nil {
+		return fmt.Errorf("transfer leadership: %w", err)
+	}
+
+	restarts, _ := etcdContainer(pod)
+
+	err = SignalEtcd(syscall.SIGTERM)
+	if err != nil {
+		return fmt.Errorf("stop etcd: %w", err)
+	}
+
+	logger.Info("restarting etcd")
+
+	err = Poll(ctx, s.kcl, pod, s.config.Interval, func(pod *corev1.Pod) (bool, error) {
+		count, ready := etcdContainer(pod)
+		return count > restarts && ready, nil
+	})
+	if err != nil {
+		return fmt.Errorf("etcd not ready: %w", err)
+	}
+
+	patch := client.StrategicMergeFrom(pod.DeepCopy())
+	delete(pod.Annotations, apiv1.RestartRequestedAnnotation)
+	delete(pod.Annotations, apiv1.RestartGrantedAnnotation)
+	err = s.kcl.Patch(ctx, pod, patch)
+	if err != nil {
+		return fmt.Errorf("patch pod annotations: %w", err)
+	}
+
+	logger.Info("restarted etcd")
+
+	return nil
+}
+
+// TransferLeadership moves leadership of local member to the first available voting member
+func (s *Sidecar) TransferLeadership(ctx context.Context) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	// leadership can only be transferred by the leader
+	ecl, err := etcd.Connect(ctx, &s.tlsConfig, "https://127.0.0.1:2379")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, ecl.Close())
+	}()
+
+	status, err := ecl.Status(ctx, "https://127.0.0.1:2379")
+	if err != nil {
+		return fmt.Errorf("query status: %w", err)
+	}
+
+	// bail if not leader
+	if status.Leader != status.Header.MemberId {
+		return nil
+	}
+
+	members, err := ecl.MemberList(ctx)
+	if err != nil {
+		return fmt.Errorf("query member list: %w", err)
+	}
+
+	for _, member := range members.Members {
+		if !transferee(ctx, ecl, member, status.Header.MemberId) {
+			continue
+		}
+
+		_, err = ecl.MoveLeader(ctx, member.ID)
+		if err != nil {
+			return fmt.Errorf("move leader to %q: %w", member.Name, err)
+		}
+
+		log.FromContext(ctx).Info("moved leader", "to", member.Name)
+
+		return nil
+	}
+
+	// single member cluster restarts as leader
+	return nil
+}
+
+// transferee returns true for available voting member other than the leader
+func transferee(ctx context.Context, ecl *etcdv3.Client, member *etcdserverpb.Member, leader uint64) bool {
+	if member.ID == leader || member.IsLearner || len(member.ClientURLs) == 0 {
+		return false
+	}
+
+	_, err := ecl.Status(ctx, member.ClientURLs[0])
+	return err == nil
+}
+
+// etcdContainer returns restart count and readiness of etcd container
+func etcdContainer(pod *corev1.Pod) (int32, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == "etcd" {
+			return status.RestartCount, status.Ready
+		}
+	}
+
+	return 0, false
+}
@@ -37,7 +37,28 @@ type Config struct {
 	Timeout         time.Duration
 	ShutdownTimeout time.Duration
 
-	Prune bool
+	Prune      bool
+	Persistent bool
+
+	// ServerIssuer and PeerIssuer are issuers of member certificates formatted as kind/name,
+	// CA issuers of the cluster are used when not set
+	ServerIssuer string
+	PeerIssuer   string
+	// CABundle is the name of config map with CAs trusted next to CAs of the issuers
+	CABundle string
+	// KeyAlgorithm, KeySize, Duration and RenewBefore define keys and lifetime of member certificates
+	KeyAlgorithm string
+	KeySize      int
+	Duration     time.Duration
+	RenewBefore  time.Duration
+	// CommonName of server certificate authenticates member as etcd user when auth is enabled
+	CommonName string
+
+	// ClusterDomain is the DNS domain of Kubernetes services
+	ClusterDomain string
+	// DNSNames and IPAddresses are additional subject alternative names of server certificate
+	DNSNames    []string
+	IPAddresses []string
 }
 
 type Sidecar struct {
@@ -48,9 +69,14 @@ type Sidecar struct {
 	pod        corev1.Pod
 	tlsConfig  tls.Config
 	etcdConfig etcd.Config
+
+	// forcedCluster is the value of force new cluster annotation already applied
+	forcedCluster string
 }
 
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get
 //+kubebuilder:rbac:groups=cert-manager.io,resources=certificaterequests,verbs=get;create;delete
 
 func New(kcl client.Client, kubeconfig *rest.Config, config Config) *Sidecar {
@@ -100,6 +126,11 @@ func (s *Sidecar) Start(ctx context.Context) error {
 		// remove member when terminating using separate context
 		<-ctx.Done()
 
+		// persistent member restarts in place and is removed by prune once its volume is deleted
+		if s.config.Persistent {
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
 		defer cancel()
 
@@ -124,6 +155,17 @@ func (s *Sidecar) Start(ctx context.Context) error {
 		return s.WatchCluster(ctx)
 	})
 
+	errg.Go(func() error {
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			err := s.Restart(ctx)
+			if err != nil {
+				logger.Error(err, "restart")
+			}
+		}, s.config.Interval)
+
+		return nil
+	})
+
 	errg.Go(func() error {
 		wait.UntilWithContext(ctx, func(ctx context.Context) {
 			err := s.GenerateCredentials(ctx)
@@ -208,7 +250,13 @@ func (s *Sidecar) WatchCluster(ctx context.Context) error {
 		ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
 		defer cancel()
 
-		err := s.Sync(ctx, ecl)
+		err := s.ForceNewCluster(ctx)
+		if err != nil {
+			logger.Error(err, "force new cluster")
+			return
+		}
+
+		err = s.Sync(ctx, ecl)
 		if err != nil {
 			logger.Error(err, "sync")
 		}
@@ -47,6 +47,17 @@ func (s *Sidecar) Sync(ctx context.Context, ecl *etcdv3.Client) error {
 		return nil
 	}
 
+	// new cluster is started, force-new-cluster must not be used on the next restart
+	if s.etcdConfig.ForceNewCluster {
+		s.etcdConfig.ForceNewCluster = false
+		err = s.WriteConfig()
+		if err != nil {
+			return err
+		}
+
+		log.FromContext(ctx).Info("started new cluster")
+	}
+
 	members, err := ecl.MemberList(ctx)
 	if err != nil {
 		return fmt.Errorf("query member list: %w", err)
@@ -65,7 +76,7 @@ func (s *Sidecar) Sync(ctx context.Context, ecl *etcdv3.Client) error {
 	return nil
 }
 
-// Prune removes members which do not have associated pod
+// Prune removes members which do not have associated pod or persistent volume claim
 func (s *Sidecar) Prune(ctx context.Context, ecl etcdv3.Cluster, members *etcdv3.MemberListResponse) error {
 	if !s.config.Prune {
 		return nil
@@ -85,6 +96,21 @@ func (s *Sidecar) Prune(ctx context.Context, ecl etcdv3.Cluster, members *etcdv3
 		return err
 	}
 
+	// persistent members are kept while their data volume exists
+	claims := &corev1.PersistentVolumeClaimList{}
+	if s.config.Persistent {
+		err = s.kcl.List(ctx, claims, client.InNamespace(s.config.Namespace), client.MatchingLabels{
+			apiv1.ClusterLabel: s.pod.Labels[apiv1.ClusterLabel],
+		})
+		switch {
+		case apierrors.IsTooManyRequests(err):
+			logger.V(3).Info("prune: too many requests")
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+
 	// find member without pod
 	i := slices.IndexFunc(members.Members, func(member *etcdserverpb.Member) bool {
 		// skip unstarted
@@ -92,6 +118,13 @@ func (s *Sidecar) Prune(ctx context.Context, ecl etcdv3.Cluster, members *etcdv3
 			return false
 		}
 
+		claimed := slices.ContainsFunc(claims.Items, func(claim corev1.PersistentVolumeClaim) bool {
+			return claim.Name == "data-"+member.Name && claim.DeletionTimestamp.IsZero()
+		})
+		if claimed {
+			return false
+		}
+
 		return !slices.ContainsFunc(pods.Items, func(pod corev1.Pod) bool {
 			return pod.Name == member.Name || apiv1.ParseMemberID(pod.Labels) == member.ID
 		})
@@ -112,9 +145,31 @@ func (s *Sidecar) Prune(ctx context.Context, ecl etcdv3.Cluster, members *etcdv3
 	return nil
 }
 
+// Promote promotes the first learner of a member pod, learners of read replica pods are never promoted
+// and learners without pod are left to be pruned
 func (s *Sidecar) Promote(ctx context.Context, ecl etcdv3.Cluster, members *etcdv3.MemberListResponse) error {
-	i := slices.IndexFunc(members.Members, func(member *etcdserverpb.Member) bool {
+	if !slices.ContainsFunc(members.Members, func(member *etcdserverpb.Member) bool {
 		return member.IsLearner && member.Name != ""
+	}) {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	err := s.kcl.List(ctx, pods, client.InNamespace(s.config.Namespace), client.MatchingLabels{
+		apiv1.ClusterLabel: s.pod.Labels[apiv1.ClusterLabel],
+	})
+	if err != nil {
+		return fmt.Errorf("list pods: %w", err)
+	}
+
+	i := slices.IndexFunc(members.Members, func(member *etcdserverpb.Member) bool {
+		if !member.IsLearner || member.Name == "" {
+			return false
+		}
+
+		return slices.ContainsFunc(pods.Items, func(pod corev1.Pod) bool {
+			return pod.Name == member.Name && pod.Labels[apiv1.ReadReplicaLabel] != "true"
+		})
 	})
 	if i == -1 {
 		return nil
@@ -123,7 +178,7 @@ func (s *Sidecar) Promote(ctx context.Context, ecl etcdv3.Cluster, members *etcd
 	learner := members.Members[i]
 	logger := log.FromContext(ctx, "learner", learner.Name, "id", apiv1.FormatMemberID(learner.ID))
 
-	_, err := ecl.MemberPromote(ctx, learner.ID)
+	_, err = ecl.MemberPromote(ctx, learner.ID)
 	switch {
 	case errors.Is(err, rpctypes.ErrMemberLearnerNotReady):
 		logger.Info("waiting for learner to catch up")
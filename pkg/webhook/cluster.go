@@ -0,0 +1,644 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/coreos/go-semver/semver"
+	"github.com/robfig/cron/v3"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+	clusterspec "github.com/agoda-com/etcd-operator/pkg/cluster"
+)
+
+const (
+	// DefaultHeartbeatInterval of etcd server
+	DefaultHeartbeatInterval = 100 * time.Millisecond
+	// DefaultElectionTimeout of etcd server
+	DefaultElectionTimeout = time.Second
+	// MaxElectionTimeout accepted by etcd server
+	MaxElectionTimeout = 50 * time.Second
+)
+
+const (
+	// MinCertificateDuration and MinRenewBefore accepted by cert-manager
+	MinCertificateDuration = time.Hour
+	MinRenewBefore         = 5 * time.Minute
+	// MinRSAKeySize and MaxRSAKeySize accepted by cert-manager
+	MinRSAKeySize = 2048
+	MaxRSAKeySize = 8192
+)
+
+// MinConsistencyCheckInterval limits load of hashing member data
+const MinConsistencyCheckInterval = 5 * time.Minute
+
+// ECDSAKeySizes are supported curve sizes of ECDSA keys
+var ECDSAKeySizes = []int{256, 384, 521}
+
+var (
+	// MinVersion is the oldest supported etcd minor version
+	MinVersion = semver.Version{Major: 3, Minor: 4}
+	// MaxVersion is the newest supported etcd minor version
+	MaxVersion = semver.Version{Major: 3, Minor: 6}
+)
+
+// ClusterValidator validates EtcdCluster invariants that can not be expressed with CRD validation markers
+type ClusterValidator struct {
+	kcl client.Client
+}
+
+var _ admission.CustomValidator = &ClusterValidator{}
+
+// SetupWithManager registers EtcdCluster validating webhook
+func SetupWithManager(mgr manager.Manager) error {
+	return builder.WebhookManagedBy(mgr).
+		For(&apiv1.EtcdCluster{}).
+		WithValidator(&ClusterValidator{
+			kcl: mgr.GetClient(),
+		}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-etcd-fleet-agoda-com-v1-etcdcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=etcd.fleet.agoda.com,resources=etcdclusters,verbs=create;update,versions=v1,name=vetcdcluster.etcd.fleet.agoda.com,admissionReviewVersions=v1
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// ValidateCreate implements admission.CustomValidator
+func (v *ClusterValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*apiv1.EtcdCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected EtcdCluster, got %T", obj)
+	}
+
+	warnings, err := validate(nil, cluster)
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, v.authorizeClone(ctx, nil, cluster)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *ClusterValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*apiv1.EtcdCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected EtcdCluster, got %T", oldObj)
+	}
+	cluster, ok := newObj.(*apiv1.EtcdCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected EtcdCluster, got %T", newObj)
+	}
+
+	warnings, err := validate(old, cluster)
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, v.authorizeClone(ctx, old, cluster)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *ClusterValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks cluster spec, old is nil on create.
+// Fields that did not change on update are not validated so existing clusters can still be updated.
+func validate(old, cluster *apiv1.EtcdCluster) (admission.Warnings, error) {
+	var (
+		warnings admission.Warnings
+		errs     field.ErrorList
+	)
+
+	spec := field.NewPath("spec")
+	create := old == nil
+	if create {
+		old = &apiv1.EtcdCluster{}
+	}
+
+	if create || cluster.Spec.Replicas != old.Spec.Replicas {
+		w, err := validateReplicas(spec.Child("replicas"), old, cluster)
+		warnings = append(warnings, w...)
+		errs = append(errs, err...)
+	}
+
+	if create || cluster.Spec.Version != old.Spec.Version {
+		w, err := validateVersion(spec.Child("version"), old, cluster)
+		warnings = append(warnings, w...)
+		errs = append(errs, err...)
+	}
+
+	if cluster.Spec.ReadReplicas != 0 && (cluster.Spec.ReadReplicas != old.Spec.ReadReplicas || cluster.Spec.Version != old.Spec.Version) {
+		errs = append(errs, validateReadReplicas(spec.Child("readReplicas"), old, cluster)...)
+	}
+
+	if backup := cluster.Spec.Backup; backup != nil && (old.Spec.Backup == nil || backup.Schedule != old.Spec.Backup.Schedule) {
+		errs = append(errs, validateSchedule(spec.Child("backup", "schedule"), backup.Schedule)...)
+	}
+
+	if defrag := cluster.Spec.Defrag; defrag != nil && defrag.Schedule != nil && (old.Spec.Defrag == nil || !equality.Semantic.DeepEqual(defrag.Schedule, old.Spec.Defrag.Schedule)) {
+		errs = append(errs, validateSchedule(spec.Child("defrag", "schedule"), *defrag.Schedule)...)
+	}
+
+	if cluster.Spec.DeletionPolicy == apiv1.DeletionPolicyDelete && old.Spec.DeletionPolicy != cluster.Spec.DeletionPolicy {
+		warnings = append(warnings, "spec.deletionPolicy: Delete removes all backup objects of the cluster when cluster is deleted")
+	}
+
+	if etcd := cluster.Spec.Etcd; etcd != nil && !equality.Semantic.DeepEqual(etcd, old.Spec.Etcd) {
+		errs = append(errs, validateEtcd(spec.Child("etcd"), etcd)...)
+		if !create {
+			warnings = append(warnings, "spec.etcd: change of etcd parameters replaces members one at a time")
+		}
+	}
+
+	if template := cluster.Spec.PodTemplate; template != nil && template.Spec != nil && (old.Spec.PodTemplate == nil || !equality.Semantic.DeepEqual(template.Spec, old.Spec.PodTemplate.Spec)) {
+		errs = append(errs, validatePodTemplate(spec.Child("podTemplate", "spec"), cluster)...)
+	}
+
+	// restore is only applied while cluster is bootstrapped
+	bootstrapped := old.Status.Phase != "" && old.Status.Phase != apiv1.ClusterBootstrap
+	if bootstrapped && !equality.Semantic.DeepEqual(old.Spec.Restore, cluster.Spec.Restore) {
+		errs = append(errs, field.Forbidden(spec.Child("restore"), "restore can not be changed after cluster is bootstrapped, use EtcdRestore to restore running cluster"))
+	}
+
+	if restore := cluster.Spec.Restore; restore != nil && restore.FromCluster != nil {
+		source := restore.FromCluster
+		if source.Name == cluster.Name && (source.Namespace == "" || source.Namespace == cluster.Namespace) {
+			errs = append(errs, field.Invalid(spec.Child("restore", "fromCluster"), source.Name, "cluster can not be cloned from itself"))
+		}
+	}
+
+	if service := cluster.Spec.Service; service != nil && !equality.Semantic.DeepEqual(service, old.Spec.Service) {
+		errs = append(errs, validateService(spec.Child("service"), service)...)
+	}
+
+	// certificate names are passed to member sidecars
+	if !create && !equality.Semantic.DeepEqual(certificateNames(old.Spec.Service), certificateNames(cluster.Spec.Service)) {
+		warnings = append(warnings, "spec.service: change of certificate names replaces members one at a time")
+	}
+
+	// rotation trigger is compared with the value applied by the latest rotation
+	if rotate := clusterspec.RotateTrigger(cluster); !create && rotate != clusterspec.RotateTrigger(old) {
+		if len(clusterspec.SelfSignedCAs(cluster)) == 0 {
+			errs = append(errs, field.Forbidden(spec.Child("pki", "rotate"), "CAs of referenced issuers are not rotated by operator"))
+		} else {
+			warnings = append(warnings, "spec.pki.rotate: CA rotation replaces members one at a time three times")
+		}
+	}
+
+	if pki := cluster.Spec.PKI; pki != nil && !equality.Semantic.DeepEqual(pki, old.Spec.PKI) {
+		errs = append(errs, validatePKI(spec.Child("pki"), cluster)...)
+	}
+
+	// changed keys would reissue self-signed CAs, existing certificates would not be trusted anymore
+	if !create && len(clusterspec.SelfSignedCAs(cluster)) != 0 {
+		algorithm, size := clusterspec.PrivateKey(cluster)
+		oldAlgorithm, oldSize := clusterspec.PrivateKey(old)
+		if algorithm != oldAlgorithm || size != oldSize {
+			errs = append(errs, field.Forbidden(spec.Child("pki", "keyAlgorithm"), "key of self-signed CAs can not be changed, set key algorithm and size when cluster is created"))
+		}
+		if clusterspec.CADuration(cluster) != clusterspec.CADuration(old) {
+			errs = append(errs, field.Forbidden(spec.Child("pki", "caDuration"), "duration of self-signed CAs can not be changed, set it when cluster is created"))
+		}
+	}
+
+	// keys and lifetime of member certificates are passed to member sidecars
+	if !create && !equality.Semantic.DeepEqual(old.Spec.PKI, cluster.Spec.PKI) && !slices.Equal(clusterspec.SidecarPKIArgs(old), clusterspec.SidecarPKIArgs(cluster)) {
+		warnings = append(warnings, "spec.pki: change of member certificates replaces members one at a time")
+	}
+
+	// members are reissued certificates of root user before auth is enabled
+	if enabled := authEnabled(cluster); !create && enabled != authEnabled(old) {
+		if enabled {
+			warnings = append(warnings, "spec.auth: members are replaced one at a time before authentication is enabled, clients without EtcdUser lose access")
+		} else {
+			warnings = append(warnings, "spec.auth: members are replaced one at a time after authentication is disabled")
+		}
+	}
+
+	if mirror := cluster.Spec.Mirror; mirror != nil {
+		w, err := validateMirror(spec.Child("mirror"), old.Spec.Mirror, mirror)
+		warnings = append(warnings, w...)
+		errs = append(errs, err...)
+
+		// only root credentials of mirror worker can write to standby cluster
+		if !mirror.Promote && !authEnabled(cluster) {
+			errs = append(errs, field.Required(spec.Child("auth", "enabled"), "standby cluster is read-only through etcd authentication, enable it while mirroring"))
+		}
+	}
+
+	if check := cluster.Spec.ConsistencyCheck; check != nil && !equality.Semantic.DeepEqual(check, old.Spec.ConsistencyCheck) {
+		if check.Interval != nil && check.Interval.Duration < MinConsistencyCheckInterval {
+			errs = append(errs, field.Invalid(spec.Child("consistencyCheck", "interval"), check.Interval.Duration.String(), fmt.Sprintf("must be at least %s", MinConsistencyCheckInterval)))
+		}
+		if old.Spec.ConsistencyCheck == nil {
+			warnings = append(warnings, "spec.consistencyCheck: members diverging from the majority are replaced one at a time and lose their data")
+		}
+	}
+
+	if recovery := cluster.Spec.Recovery; recovery != nil && recovery.Policy == apiv1.RecoveryForceNewCluster && (old.Spec.Recovery == nil || old.Spec.Recovery.Policy != recovery.Policy) {
+		warnings = append(warnings, "spec.recovery.policy: ForceNewCluster may lose writes that were not replicated to the surviving member")
+	}
+
+	if len(errs) != 0 {
+		return warnings, apierrors.NewInvalid(apiv1.GroupVersion.WithKind("EtcdCluster").GroupKind(), cluster.Name, errs)
+	}
+
+	return warnings, nil
+}
+
+// authorizeClone checks that the requesting user can read the source cluster and its root credentials,
+// cloning grants full access to the source data so the check applies to the same namespace as well
+func (v *ClusterValidator) authorizeClone(ctx context.Context, old, cluster *apiv1.EtcdCluster) error {
+	restore := cluster.Spec.Restore
+	if restore == nil || restore.FromCluster == nil {
+		return nil
+	}
+	if old != nil && old.Spec.Restore != nil && equality.Semantic.DeepEqual(old.Spec.Restore.FromCluster, restore.FromCluster) {
+		return nil
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	namespace := restore.FromCluster.Namespace
+	if namespace == "" {
+		namespace = cluster.Namespace
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(req.UserInfo.Extra))
+	for key, value := range req.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	path := field.NewPath("spec", "restore", "fromCluster")
+	attributes := []authorizationv1.ResourceAttributes{
+		{
+			Namespace: namespace,
+			Verb:      "get",
+			Group:     apiv1.GroupVersion.Group,
+			Resource:  "etcdclusters",
+			Name:      restore.FromCluster.Name,
+		},
+		{
+			Namespace: namespace,
+			Verb:      "get",
+			Resource:  "secrets",
+			Name:      restore.FromCluster.Name + "-user-root",
+		},
+	}
+
+	var errs field.ErrorList
+	for _, attrs := range attributes {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &attrs,
+				User:               req.UserInfo.Username,
+				UID:                req.UserInfo.UID,
+				Groups:             req.UserInfo.Groups,
+				Extra:              extra,
+			},
+		}
+		err = v.kcl.Create(ctx, review)
+		if err != nil {
+			return fmt.Errorf("subject access review: %w", err)
+		}
+
+		if !review.Status.Allowed {
+			errs = append(errs, field.Forbidden(path, fmt.Sprintf("user %q can not get %s %s/%s", req.UserInfo.Username, attrs.Resource, attrs.Namespace, attrs.Name)))
+		}
+	}
+
+	if len(errs) != 0 {
+		return apierrors.NewInvalid(apiv1.GroupVersion.WithKind("EtcdCluster").GroupKind(), cluster.Name, errs)
+	}
+
+	return nil
+}
+
+func validateReplicas(path *field.Path, old, cluster *apiv1.EtcdCluster) (admission.Warnings, field.ErrorList) {
+	var (
+		warnings admission.Warnings
+		errs     field.ErrorList
+	)
+
+	replicas := cluster.Spec.Replicas
+	switch {
+	case replicas%2 == 0:
+		errs = append(errs, field.Invalid(path, replicas, "must be odd, even number of members does not improve fault tolerance"))
+	case replicas == 1:
+		warnings = append(warnings, "spec.replicas: single member cluster has no fault tolerance")
+	}
+
+	// cluster has no members yet
+	if old.Spec.Replicas == 0 {
+		return warnings, errs
+	}
+
+	quorum := old.Spec.Replicas/2 + 1
+	switch {
+	case replicas < quorum:
+		errs = append(errs, field.Invalid(path, replicas, fmt.Sprintf("scale down from %d to %d members drops below quorum of %d", old.Spec.Replicas, replicas, quorum)))
+	case replicas < old.Spec.Replicas:
+		warnings = append(warnings, fmt.Sprintf("spec.replicas: scale down from %d to %d members removes members one at a time", old.Spec.Replicas, replicas))
+	}
+
+	return warnings, errs
+}
+
+func validateVersion(path *field.Path, old, cluster *apiv1.EtcdCluster) (admission.Warnings, field.ErrorList) {
+	var warnings admission.Warnings
+
+	target, err := semver.NewVersion(strings.TrimPrefix(cluster.Spec.Version, "v"))
+	if err != nil {
+		return nil, field.ErrorList{field.Invalid(path, cluster.Spec.Version, "must be semantic version, e.g. v3.5.17")}
+	}
+
+	minor := semver.Version{Major: target.Major, Minor: target.Minor}
+	if minor.LessThan(MinVersion) || MaxVersion.LessThan(minor) {
+		return nil, field.ErrorList{field.NotSupported(path, cluster.Spec.Version, []string{
+			fmt.Sprintf("v%d.%d.x - v%d.%d.x", MinVersion.Major, MinVersion.Minor, MaxVersion.Major, MaxVersion.Minor),
+		})}
+	}
+
+	// cluster has not been created yet
+	if old.Status.Version == "" {
+		return nil, nil
+	}
+
+	current, err := semver.NewVersion(old.Status.Version)
+	if err != nil {
+		return nil, nil
+	}
+
+	switch {
+	case target.Minor > current.Minor+1:
+		return nil, field.ErrorList{field.Forbidden(path, fmt.Sprintf("upgrade from %d.%d to %d.%d is not supported, upgrade one minor version at a time", current.Major, current.Minor, target.Major, target.Minor))}
+	case target.Minor+1 < current.Minor:
+		return nil, field.ErrorList{field.Forbidden(path, fmt.Sprintf("downgrade from %d.%d to %d.%d is not supported, downgrade one minor version at a time", current.Major, current.Minor, target.Major, target.Minor))}
+	case target.Minor < current.Minor:
+		warnings = append(warnings, fmt.Sprintf("spec.version: downgrade from %s to %s lowers cluster version and restarts all members", current, target))
+	case !target.Equal(*current):
+		warnings = append(warnings, fmt.Sprintf("spec.version: upgrade from %s to %s restarts all members", current, target))
+	}
+
+	if old.Status.Phase != apiv1.ClusterRunning {
+		warnings = append(warnings, fmt.Sprintf("spec.version: cluster is %s, version change is applied once cluster is Running", old.Status.Phase))
+	}
+
+	return warnings, nil
+}
+
+func validateService(path *field.Path, service *apiv1.ServiceSpec) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, name := range service.ExternalDNSNames {
+		errs = append(errs, validateDNSName(path.Child("externalDNSNames").Index(i), name)...)
+	}
+	for i, name := range service.DNSNames {
+		errs = append(errs, validateDNSName(path.Child("dnsNames").Index(i), name)...)
+	}
+
+	for i, ip := range service.IPAddresses {
+		if net.ParseIP(ip) == nil {
+			errs = append(errs, field.Invalid(path.Child("ipAddresses").Index(i), ip, "must be valid IP address"))
+		}
+	}
+
+	return errs
+}
+
+func validateDNSName(path *field.Path, name string) field.ErrorList {
+	if validation.IsDNS1123Subdomain(name) != nil && validation.IsWildcardDNS1123Subdomain(name) != nil {
+		return field.ErrorList{field.Invalid(path, name, "must be valid DNS name")}
+	}
+
+	return nil
+}
+
+// certificateNames returns additional names of server certificates
+func certificateNames(service *apiv1.ServiceSpec) []string {
+	if service == nil {
+		return nil
+	}
+
+	return slices.Concat(service.ExternalDNSNames, service.DNSNames, service.IPAddresses)
+}
+
+// authEnabled returns true when auth is enabled by spec, unlike clusterspec.AuthEnabled observed auth state is ignored
+func authEnabled(cluster *apiv1.EtcdCluster) bool {
+	return cluster.Spec.Auth != nil && cluster.Spec.Auth.Enabled
+}
+
+// validateReadReplicas checks that both target and observed cluster version support learners next to read replicas
+func validateReadReplicas(path *field.Path, old, cluster *apiv1.EtcdCluster) field.ErrorList {
+	minimum := fmt.Sprintf("v%d.%d", clusterspec.ReadReplicaVersion.Major, clusterspec.ReadReplicaVersion.Minor)
+
+	if !clusterspec.ReadReplicasSupported(cluster.Spec.Version) {
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf("read replicas require etcd %s or later", minimum))}
+	}
+
+	if old.Status.Version != "" && !clusterspec.ReadReplicasSupported(old.Status.Version) {
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf("cluster runs etcd %s, read replicas can be added once cluster is upgraded to %s", old.Status.Version, minimum))}
+	}
+
+	return nil
+}
+
+func validateEtcd(path *field.Path, spec *apiv1.EtcdSpec) field.ErrorList {
+	var errs field.ErrorList
+
+	heartbeat, election := DefaultHeartbeatInterval, DefaultElectionTimeout
+	if spec.HeartbeatInterval != nil {
+		heartbeat = spec.HeartbeatInterval.Duration
+		if heartbeat < time.Millisecond {
+			errs = append(errs, field.Invalid(path.Child("heartbeatInterval"), heartbeat.String(), "must be at least 1ms"))
+		}
+	}
+	if spec.ElectionTimeout != nil {
+		election = spec.ElectionTimeout.Duration
+		if election > MaxElectionTimeout {
+			errs = append(errs, field.Invalid(path.Child("electionTimeout"), election.String(), fmt.Sprintf("must be at most %s", MaxElectionTimeout)))
+		}
+	}
+	if election < 5*heartbeat {
+		errs = append(errs, field.Invalid(path.Child("electionTimeout"), election.String(), fmt.Sprintf("must be at least 5 times heartbeat interval of %s", heartbeat)))
+	}
+
+	if spec.AutoCompactionRetention != nil {
+		retention := *spec.AutoCompactionRetention
+		mode := apiv1.CompactionRevision
+		if spec.AutoCompactionMode != nil {
+			mode = *spec.AutoCompactionMode
+		}
+
+		switch mode {
+		case apiv1.CompactionRevision:
+			revisions, err := strconv.ParseInt(retention, 10, 64)
+			if err != nil || revisions <= 0 {
+				errs = append(errs, field.Invalid(path.Child("autoCompactionRetention"), retention, "must be positive number of revisions in revision mode"))
+			}
+		case apiv1.CompactionPeriodic:
+			// etcd accepts number of hours or duration
+			hours, err := strconv.ParseInt(retention, 10, 64)
+			if err == nil && hours > 0 {
+				break
+			}
+			period, err := time.ParseDuration(retention)
+			if err != nil || period <= 0 {
+				errs = append(errs, field.Invalid(path.Child("autoCompactionRetention"), retention, "must be positive duration, e.g. 1h, in periodic mode"))
+			}
+		}
+	}
+
+	if spec.MaxRequestBytes != nil && spec.MaxRequestBytes.Sign() <= 0 {
+		errs = append(errs, field.Invalid(path.Child("maxRequestBytes"), spec.MaxRequestBytes.String(), "must be positive"))
+	}
+
+	if keepAlive := spec.GRPCKeepAlive; keepAlive != nil {
+		keepAlivePath := path.Child("grpcKeepAlive")
+		durations := []struct {
+			name     string
+			duration *metav1.Duration
+		}{
+			{"minTime", keepAlive.MinTime},
+			{"interval", keepAlive.Interval},
+			{"timeout", keepAlive.Timeout},
+		}
+		for _, d := range durations {
+			if d.duration != nil && d.duration.Duration <= 0 {
+				errs = append(errs, field.Invalid(keepAlivePath.Child(d.name), d.duration.Duration.String(), "must be positive"))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validatePodTemplate(path *field.Path, cluster *apiv1.EtcdCluster) field.ErrorList {
+	var errs field.ErrorList
+
+	// member pod spec without overlay including restore container, clone source and data volumes
+	generated := cluster.DeepCopy()
+	generated.Spec.PodTemplate.Spec = nil
+	generated.Spec.Storage = nil
+	generated.Spec.Restore = &apiv1.RestoreSpec{FromCluster: &apiv1.ClusterReference{}}
+	generated.Status.Phase = apiv1.ClusterBootstrap
+	generated.Status.Restore = nil
+	spec := clusterspec.PodSpec(generated, clusterspec.Config{})
+
+	overlay := cluster.Spec.PodTemplate.Spec.DeepCopy()
+	for _, protected := range clusterspec.ProtectPodSpec(spec, overlay) {
+		errs = append(errs, field.Forbidden(path.Child(protected), "field is managed by etcd-operator and can not be overridden"))
+	}
+
+	_, err := clusterspec.MergePodSpec(spec, *overlay)
+	if err != nil {
+		errs = append(errs, field.Invalid(path, "", err.Error()))
+	}
+
+	return errs
+}
+
+func validatePKI(path *field.Path, cluster *apiv1.EtcdCluster) field.ErrorList {
+	var errs field.ErrorList
+
+	pki := cluster.Spec.PKI
+	switch size := pki.KeySize; pki.KeyAlgorithm {
+	case "", string(cmv1.RSAKeyAlgorithm):
+		if size != 0 && (size < MinRSAKeySize || size > MaxRSAKeySize) {
+			errs = append(errs, field.Invalid(path.Child("keySize"), size, fmt.Sprintf("must be between %d and %d for RSA", MinRSAKeySize, MaxRSAKeySize)))
+		}
+	case string(cmv1.ECDSAKeyAlgorithm):
+		if size != 0 && !slices.Contains(ECDSAKeySizes, size) {
+			errs = append(errs, field.NotSupported(path.Child("keySize"), size, []string{"256", "384", "521"}))
+		}
+	case string(cmv1.Ed25519KeyAlgorithm):
+		if size != 0 {
+			errs = append(errs, field.Invalid(path.Child("keySize"), size, "must not be set, Ed25519 keys have fixed size"))
+		}
+	}
+
+	duration := cmv1.DefaultCertificateDuration
+	if pki.Duration != nil {
+		duration = pki.Duration.Duration
+	}
+	clientDuration := cmv1.DefaultCertificateDuration
+	if pki.ClientDuration != nil {
+		clientDuration = pki.ClientDuration.Duration
+	}
+	caDuration := clusterspec.CADuration(cluster)
+
+	durations := []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"duration", pki.Duration},
+		{"clientDuration", pki.ClientDuration},
+		{"caDuration", pki.CADuration},
+	}
+	for _, d := range durations {
+		if d.duration != nil && d.duration.Duration < MinCertificateDuration {
+			errs = append(errs, field.Invalid(path.Child(d.name), d.duration.Duration.String(), fmt.Sprintf("must be at least %s", MinCertificateDuration)))
+		}
+	}
+
+	if renewBefore := pki.RenewBefore; renewBefore != nil && (renewBefore.Duration < MinRenewBefore || renewBefore.Duration >= duration) {
+		errs = append(errs, field.Invalid(path.Child("renewBefore"), renewBefore.Duration.String(), fmt.Sprintf("must be at least %s and less than duration of %s", MinRenewBefore, duration)))
+	}
+
+	// certificates issued by self-signed CAs must not outlive them
+	if len(clusterspec.SelfSignedCAs(cluster)) != 0 && caDuration <= max(duration, clientDuration) {
+		errs = append(errs, field.Invalid(path.Child("caDuration"), caDuration.String(), fmt.Sprintf("must be longer than duration of member and client certificates of %s", max(duration, clientDuration))))
+	}
+
+	return errs
+}
+
+func validateMirror(path *field.Path, old, mirror *apiv1.MirrorSpec) (admission.Warnings, field.ErrorList) {
+	switch {
+	case old == nil:
+		return admission.Warnings{fmt.Sprintf("spec.mirror: cluster is read-only standby, write permissions of EtcdRoles and root role of EtcdUsers are withheld until promoted, keys with prefix %q missing in source cluster are deleted", mirror.Prefix)}, nil
+	case old.Promote && !mirror.Promote:
+		return nil, field.ErrorList{field.Forbidden(path.Child("promote"), "promoted cluster can not resume mirroring, remove spec.mirror and add it again to mirror from scratch")}
+	case !old.Promote && (mirror.Endpoint != old.Endpoint || mirror.Prefix != old.Prefix):
+		return nil, field.ErrorList{field.Forbidden(path, "endpoint and prefix can not be changed while mirroring, remove spec.mirror and add it again to mirror from scratch")}
+	case !old.Promote && mirror.Promote:
+		return admission.Warnings{"spec.mirror.promote: mirroring is stopped and can not be resumed, check status.mirror.lag before planned switchover"}, nil
+	}
+
+	return nil, nil
+}
+
+func validateSchedule(path *field.Path, schedule string) field.ErrorList {
+	if schedule == "" {
+		return nil
+	}
+
+	_, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, schedule, fmt.Sprintf("invalid cron schedule: %v", err))}
+	}
+
+	return nil
+}
@@ -0,0 +1,737 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	crwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	apiv1 "github.com/agoda-com/etcd-operator/api/v1"
+)
+
+// kcl is connected to envtest api server with webhook installed, nil when envtest is not available
+var kcl client.Client
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	// envtest binaries are provided by `make integration-test`
+	if testing.Short() || os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		os.Exit(m.Run())
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "config", "webhook", "manifests.yaml")},
+		},
+	}
+
+	kubeconfig, err := env.Start()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "start envtest:", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = start(ctx, kubeconfig, &env.WebhookInstallOptions)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	cancel()
+	err = env.Stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "stop envtest:", err)
+	}
+
+	os.Exit(code)
+}
+
+// start runs manager with webhook server and waits for it to serve
+func start(ctx context.Context, kubeconfig *rest.Config, options *envtest.WebhookInstallOptions) error {
+	scheme := runtime.NewScheme()
+	builder := runtime.NewSchemeBuilder(kscheme.AddToScheme, apiv1.AddToScheme)
+	err := builder.AddToScheme(scheme)
+	if err != nil {
+		return fmt.Errorf("scheme: %w", err)
+	}
+
+	mgr, err := manager.New(kubeconfig, manager.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: "0",
+		},
+		WebhookServer: crwebhook.NewServer(crwebhook.Options{
+			Host:    options.LocalServingHost,
+			Port:    options.LocalServingPort,
+			CertDir: options.LocalServingCertDir,
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("manager: %w", err)
+	}
+
+	err = SetupWithManager(mgr)
+	if err != nil {
+		return fmt.Errorf("cluster webhook: %w", err)
+	}
+
+	go func() {
+		err := mgr.Start(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "manager:", err)
+		}
+	}()
+
+	kcl, err = client.New(kubeconfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	addr := net.JoinHostPort(options.LocalServingHost, fmt.Sprint(options.LocalServingPort))
+	dialer := &net.Dialer{Timeout: time.Second}
+	for range 30 {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		if err == nil {
+			return conn.Close()
+		}
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("webhook server %s is not serving", addr)
+}
+
+func createTestCluster(name string) *apiv1.EtcdCluster {
+	return &apiv1.EtcdCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+		Spec: apiv1.EtcdClusterSpec{
+			Replicas: 3,
+			Version:  "v3.5.17",
+		},
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	if kcl == nil {
+		t.Skip("envtest is not available")
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(cluster *apiv1.EtcdCluster)
+		err    string
+	}{
+		{
+			name:   "valid",
+			mutate: func(cluster *apiv1.EtcdCluster) {},
+		},
+		{
+			name: "even replicas",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Replicas = 4
+			},
+			err: "spec.replicas: Invalid value: 4: must be odd",
+		},
+		{
+			name: "invalid version",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Version = "latest"
+			},
+			err: "spec.version: Invalid value: \"latest\": must be semantic version",
+		},
+		{
+			name: "unsupported version",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Version = "v3.3.27"
+			},
+			err: "spec.version: Unsupported value: \"v3.3.27\"",
+		},
+		{
+			name: "invalid backup schedule",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Backup = &apiv1.BackupSpec{Schedule: "every hour"}
+			},
+			err: "spec.backup.schedule: Invalid value: \"every hour\": invalid cron schedule",
+		},
+		{
+			name: "invalid defrag schedule",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Defrag = &apiv1.DefragSpec{Schedule: ptr.To("0 25 * * *")}
+			},
+			err: "spec.defrag.schedule: Invalid value: \"0 25 * * *\": invalid cron schedule",
+		},
+		{
+			name: "election timeout",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Etcd = &apiv1.EtcdSpec{
+					HeartbeatInterval: &metav1.Duration{Duration: 500 * time.Millisecond},
+				}
+			},
+			err: "spec.etcd.electionTimeout: Invalid value: \"1s\": must be at least 5 times heartbeat interval of 500ms",
+		},
+		{
+			name: "periodic compaction",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Etcd = &apiv1.EtcdSpec{
+					AutoCompactionMode:      ptr.To(apiv1.CompactionPeriodic),
+					AutoCompactionRetention: ptr.To("30m"),
+				}
+			},
+		},
+		{
+			name: "revision compaction",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Etcd = &apiv1.EtcdSpec{
+					AutoCompactionRetention: ptr.To("30m"),
+				}
+			},
+			err: "spec.etcd.autoCompactionRetention: Invalid value: \"30m\": must be positive number of revisions",
+		},
+		{
+			name: "pod template",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PodTemplate = &apiv1.PodTemplate{
+					Spec: &corev1.PodSpec{
+						NodeSelector: map[string]string{"node-pool": "etcd"},
+						Containers:   []corev1.Container{{Name: "exporter", Image: "exporter"}},
+					},
+				}
+			},
+		},
+		{
+			name: "protected container",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PodTemplate = &apiv1.PodTemplate{
+					Spec: &corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "etcd", Image: "example"}},
+					},
+				}
+			},
+			err: "spec.podTemplate.spec.containers[etcd]: Forbidden: field is managed by etcd-operator",
+		},
+		{
+			name: "clone",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Restore = &apiv1.RestoreSpec{
+					FromCluster: &apiv1.ClusterReference{Namespace: "production", Name: "source"},
+				}
+			},
+		},
+		{
+			name: "clone from itself",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Restore = &apiv1.RestoreSpec{
+					FromCluster: &apiv1.ClusterReference{Name: cluster.Name},
+				}
+			},
+			err: "spec.restore.fromCluster: Invalid value: \"create-clone-from-itself\": cluster can not be cloned from itself",
+		},
+		{
+			name: "clone with key",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Restore = &apiv1.RestoreSpec{
+					Key:         ptr.To("default/backup"),
+					FromCluster: &apiv1.ClusterReference{Name: "source"},
+				}
+			},
+			err: "fromCluster can not be combined with prefix or key",
+		},
+		{
+			name: "read replicas",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Version = "v3.6.0"
+				cluster.Spec.ReadReplicas = 2
+			},
+		},
+		{
+			name: "read replicas before v3.6",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.ReadReplicas = 2
+			},
+			err: "spec.readReplicas: Forbidden: read replicas require etcd v3.6 or later",
+		},
+		{
+			name: "service",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Service = &apiv1.ServiceSpec{
+					Type:             corev1.ServiceTypeLoadBalancer,
+					ExternalDNSNames: []string{"etcd.example.com"},
+					DNSNames:         []string{"*.etcd.example.com"},
+					IPAddresses:      []string{"10.0.0.1"},
+				}
+			},
+		},
+		{
+			name: "invalid service ip",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Service = &apiv1.ServiceSpec{IPAddresses: []string{"etcd.example.com"}}
+			},
+			err: "spec.service.ipAddresses[0]: Invalid value: \"etcd.example.com\": must be valid IP address",
+		},
+		{
+			name: "ecdsa",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{
+					KeyAlgorithm: "ECDSA",
+					KeySize:      256,
+					Duration:     &metav1.Duration{Duration: 30 * 24 * time.Hour},
+					RenewBefore:  &metav1.Duration{Duration: 10 * 24 * time.Hour},
+				}
+			},
+		},
+		{
+			name: "invalid ecdsa key size",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{KeyAlgorithm: "ECDSA", KeySize: 2048}
+			},
+			err: "spec.pki.keySize: Unsupported value: 2048",
+		},
+		{
+			name: "ed25519 key size",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{KeyAlgorithm: "Ed25519", KeySize: 256}
+			},
+			err: "spec.pki.keySize: Invalid value: 256: must not be set, Ed25519 keys have fixed size",
+		},
+		{
+			name: "renew before duration",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{
+					Duration:    &metav1.Duration{Duration: 24 * time.Hour},
+					RenewBefore: &metav1.Duration{Duration: 48 * time.Hour},
+				}
+			},
+			err: "spec.pki.renewBefore: Invalid value: \"48h0m0s\": must be at least 5m0s and less than duration of 24h0m0s",
+		},
+		{
+			name: "ca outlived",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{CADuration: &metav1.Duration{Duration: 30 * 24 * time.Hour}}
+			},
+			err: "spec.pki.caDuration: Invalid value: \"720h0m0s\": must be longer than duration of member and client certificates of 2160h0m0s",
+		},
+		{
+			name: "consistency check interval",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.ConsistencyCheck = &apiv1.ConsistencyCheckSpec{Interval: &metav1.Duration{Duration: time.Minute}}
+			},
+			err: "spec.consistencyCheck.interval: Invalid value: \"1m0s\": must be at least 5m0s",
+		},
+		{
+			name: "mirror without auth",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source"}
+			},
+			err: "spec.auth.enabled: Required value: standby cluster is read-only through etcd authentication",
+		},
+		{
+			name: "mirror",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source"}
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster("create-" + strings.ReplaceAll(tt.name, " ", "-"))
+			tt.mutate(cluster)
+
+			err := kcl.Create(t.Context(), cluster)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.err != "" && err == nil:
+				t.Fatalf("expected error %q", tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Errorf("expected error %q, got %q", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	if kcl == nil {
+		t.Skip("envtest is not available")
+	}
+
+	tests := []struct {
+		name    string
+		initial func(cluster *apiv1.EtcdCluster)
+		status  apiv1.EtcdClusterStatus
+		mutate  func(cluster *apiv1.EtcdCluster)
+		err     string
+	}{
+		{
+			name: "scale down",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Replicas = 1
+			},
+			err: "spec.replicas: Invalid value: 1: scale down from 3 to 1 members drops below quorum of 2",
+		},
+		{
+			name: "scale up",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Replicas = 5
+			},
+		},
+		{
+			name:   "restore after bootstrap",
+			status: apiv1.EtcdClusterStatus{Phase: apiv1.ClusterRunning},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Restore = &apiv1.RestoreSpec{Key: ptr.To("default/backup")}
+			},
+			err: "spec.restore: Forbidden: restore can not be changed after cluster is bootstrapped",
+		},
+		{
+			name:   "restore during bootstrap",
+			status: apiv1.EtcdClusterStatus{Phase: apiv1.ClusterBootstrap},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Restore = &apiv1.RestoreSpec{Key: ptr.To("default/backup")}
+			},
+		},
+		{
+			name:   "skip minor upgrade",
+			status: apiv1.EtcdClusterStatus{Phase: apiv1.ClusterRunning, Version: "3.4.30"},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Version = "v3.6.0"
+			},
+			err: "spec.version: Forbidden: upgrade from 3.4 to 3.6 is not supported",
+		},
+		{
+			name:   "minor upgrade",
+			status: apiv1.EtcdClusterStatus{Phase: apiv1.ClusterRunning, Version: "3.5.17"},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Version = "v3.6.0"
+			},
+		},
+		{
+			name:   "read replicas during upgrade",
+			status: apiv1.EtcdClusterStatus{Phase: apiv1.ClusterRunning, Version: "3.5.17"},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Version = "v3.6.0"
+				cluster.Spec.ReadReplicas = 1
+			},
+			err: "spec.readReplicas: Forbidden: cluster runs etcd 3.5.17, read replicas can be added once cluster is upgraded to v3.6",
+		},
+		{
+			name: "resume mirroring",
+			initial: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source", Promote: true}
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror.Promote = false
+			},
+			err: "spec.mirror.promote: Forbidden: promoted cluster can not resume mirroring",
+		},
+		{
+			name: "change mirror prefix",
+			initial: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source"}
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror.Prefix = "/registry/"
+			},
+			err: "spec.mirror: Forbidden: endpoint and prefix can not be changed while mirroring",
+		},
+		{
+			name: "rotate",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{Rotate: "2024-01-01"}
+			},
+		},
+		{
+			name: "rotate referenced issuers",
+			initial: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{
+					ServerIssuerRef: &apiv1.IssuerReference{Name: "etcd-server"},
+					PeerIssuerRef:   &apiv1.IssuerReference{Name: "etcd-peer"},
+				}
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI.Rotate = "2024-01-01"
+			},
+			err: "spec.pki.rotate: Forbidden: CAs of referenced issuers are not rotated by operator",
+		},
+		{
+			name: "add issuer",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{ServerIssuerRef: &apiv1.IssuerReference{Name: "etcd-server"}}
+			},
+			err: "pki.serverIssuerRef can not be added or removed",
+		},
+		{
+			name: "change issuer",
+			initial: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{ServerIssuerRef: &apiv1.IssuerReference{Name: "etcd-server"}}
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI.ServerIssuerRef.Name = "etcd"
+			},
+			err: "issuer can not be changed",
+		},
+		{
+			name: "change key algorithm",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{KeyAlgorithm: "ECDSA"}
+			},
+			err: "spec.pki.keyAlgorithm: Forbidden: key of self-signed CAs can not be changed",
+		},
+		{
+			name: "change key algorithm of referenced issuers",
+			initial: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{
+					ServerIssuerRef: &apiv1.IssuerReference{Name: "etcd-server"},
+					PeerIssuerRef:   &apiv1.IssuerReference{Name: "etcd-peer"},
+				}
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI.KeyAlgorithm = "ECDSA"
+			},
+		},
+		{
+			name: "disable auth while mirroring",
+			initial: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source"}
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Auth = nil
+			},
+			err: "spec.auth.enabled: Required value: standby cluster is read-only through etcd authentication",
+		},
+		{
+			name: "promote",
+			initial: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source"}
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror.Promote = true
+			},
+		},
+		{
+			name: "disable auth of promoted cluster",
+			initial: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source", Promote: true}
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Auth = nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster("update-" + strings.ReplaceAll(tt.name, " ", "-"))
+			if tt.initial != nil {
+				tt.initial(cluster)
+			}
+
+			err := kcl.Create(t.Context(), cluster)
+			if err != nil {
+				t.Fatal("create cluster:", err)
+			}
+
+			cluster.Status = tt.status
+			err = kcl.Status().Update(t.Context(), cluster)
+			if err != nil {
+				t.Fatal("update status:", err)
+			}
+
+			tt.mutate(cluster)
+
+			err = kcl.Update(t.Context(), cluster)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatal("unexpected error:", err)
+			case tt.err != "" && err == nil:
+				t.Fatalf("expected error %q", tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Errorf("expected error %q, got %q", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateWarnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     *apiv1.EtcdCluster
+		mutate  func(cluster *apiv1.EtcdCluster)
+		warning string
+	}{
+		{
+			name: "single member",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Replicas = 1
+			},
+			warning: "spec.replicas: single member cluster has no fault tolerance",
+		},
+		{
+			name: "scale down",
+			old:  &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{Replicas: 5, Version: "v3.5.17"}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Replicas = 3
+			},
+			warning: "spec.replicas: scale down from 5 to 3 members removes members one at a time",
+		},
+		{
+			name: "upgrade",
+			old: &apiv1.EtcdCluster{
+				Spec:   apiv1.EtcdClusterSpec{Replicas: 3, Version: "v3.5.17"},
+				Status: apiv1.EtcdClusterStatus{Phase: apiv1.ClusterRunning, Version: "3.5.17"},
+			},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Version = "v3.5.21"
+			},
+			warning: "spec.version: upgrade from 3.5.17 to 3.5.21 restarts all members",
+		},
+		{
+			name: "force new cluster",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Recovery = &apiv1.RecoverySpec{Policy: apiv1.RecoveryForceNewCluster}
+			},
+			warning: "spec.recovery.policy: ForceNewCluster may lose writes",
+		},
+		{
+			name: "delete backups",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.DeletionPolicy = apiv1.DeletionPolicyDelete
+			},
+			warning: "spec.deletionPolicy: Delete removes all backup objects",
+		},
+		{
+			name: "etcd parameters",
+			old:  &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{Replicas: 3, Version: "v3.5.17"}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Etcd = &apiv1.EtcdSpec{
+					ElectionTimeout: &metav1.Duration{Duration: 5 * time.Second},
+				}
+			},
+			warning: "spec.etcd: change of etcd parameters replaces members one at a time",
+		},
+		{
+			name: "mirror",
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source"}
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			},
+			warning: "spec.mirror: cluster is read-only standby",
+		},
+		{
+			name: "promote",
+			old: &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{
+				Replicas: 3,
+				Version:  "v3.5.7",
+				Mirror:   &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source"},
+			}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Mirror = &apiv1.MirrorSpec{Endpoint: "https://source:2379", SecretName: "source", Promote: true}
+			},
+			warning: "spec.mirror.promote: mirroring is stopped and can not be resumed",
+		},
+		{
+			name: "certificate names",
+			old:  &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{Replicas: 3, Version: "v3.5.17"}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Service = &apiv1.ServiceSpec{ExternalDNSNames: []string{"etcd.example.com"}}
+			},
+			warning: "spec.service: change of certificate names replaces members one at a time",
+		},
+		{
+			name: "ca rotation",
+			old:  &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{Replicas: 3, Version: "v3.5.17"}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{Rotate: "2024-01-01"}
+			},
+			warning: "spec.pki.rotate: CA rotation replaces members one at a time three times",
+		},
+		{
+			name: "member certificates",
+			old:  &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{Replicas: 3, Version: "v3.5.17"}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.PKI = &apiv1.PKISpec{Duration: &metav1.Duration{Duration: 30 * 24 * time.Hour}}
+			},
+			warning: "spec.pki: change of member certificates replaces members one at a time",
+		},
+		{
+			name: "enable auth",
+			old:  &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{Replicas: 3, Version: "v3.5.17"}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Auth = &apiv1.AuthSpec{Enabled: true}
+			},
+			warning: "spec.auth: members are replaced one at a time before authentication is enabled",
+		},
+		{
+			name: "disable auth",
+			old: &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{
+				Replicas: 3,
+				Version:  "v3.5.17",
+				Auth:     &apiv1.AuthSpec{Enabled: true},
+			}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.Auth = nil
+			},
+			warning: "spec.auth: members are replaced one at a time after authentication is disabled",
+		},
+		{
+			name: "enable consistency check",
+			old:  &apiv1.EtcdCluster{Spec: apiv1.EtcdClusterSpec{Replicas: 3, Version: "v3.5.17"}},
+			mutate: func(cluster *apiv1.EtcdCluster) {
+				cluster.Spec.ConsistencyCheck = &apiv1.ConsistencyCheckSpec{}
+			},
+			warning: "spec.consistencyCheck: members diverging from the majority are replaced",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := createTestCluster("warnings")
+			tt.mutate(cluster)
+
+			warnings, err := validate(tt.old, cluster)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			for _, warning := range warnings {
+				if strings.HasPrefix(warning, tt.warning) {
+					return
+				}
+			}
+			t.Errorf("expected warning %q, got %q", tt.warning, warnings)
+		})
+	}
+}